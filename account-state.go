@@ -0,0 +1,391 @@
+package gobinance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAccountStateReconcileInterval = 5 * time.Minute
+	defaultAccountStateResyncDelay       = time.Second
+)
+
+// AccountDriftError is passed to the error handler of an AccountState when reconciliation with binance finds
+// that the locally held state was incorrect.  The local state is corrected before the handler is called.
+type AccountDriftError struct {
+	// Assets holds the assets whose balances differed
+	Assets []string
+	// OrderIDs holds the IDs of open orders which differed, were missing, or should not have been present
+	OrderIDs []int64
+}
+
+// Error implements the error interface and returns a human-readable description of the error
+func (a *AccountDriftError) Error() string {
+	return fmt.Sprintf("account state drifted from binance. assets: [%v] orders: %v", strings.Join(a.Assets, ", "), a.OrderIDs)
+}
+
+// AccountStateOption is a function that applies optional configuration to an AccountState
+type AccountStateOption func(s *AccountState)
+
+// AccountStateReconcileInterval sets how often the account state is compared against binance's REST API.  The
+// default is 5 minutes.
+func AccountStateReconcileInterval(d time.Duration) AccountStateOption {
+	return func(s *AccountState) {
+		s.reconcileInterval = d
+	}
+}
+
+// AccountStateResyncDelay sets how long to wait before resynchronising the account state after an error.
+// The default is 1 second.
+func AccountStateResyncDelay(d time.Duration) AccountStateOption {
+	return func(s *AccountState) {
+		s.resyncDelay = d
+	}
+}
+
+// AccountStateErrorHandler sets a function to be called with errors encountered while maintaining the account
+// state.  This includes errors causing a resynchronisation, failed reconciliations, and *AccountDriftError
+// when reconciliation corrects the local state.
+func AccountStateErrorHandler(handle func(err error)) AccountStateOption {
+	return func(s *AccountState) {
+		s.handleErr = handle
+	}
+}
+
+// AccountState maintains an in-memory view of the balances and open orders of the account associated with the
+// API Key provided to the client.  It is bootstrapped using AccountInformation and AllOpenSpotOrders, then kept
+// up to date using the user data stream.
+//
+// All methods are safe to call concurrently.
+type AccountState struct {
+	client            *Client
+	reconcileInterval time.Duration
+	resyncDelay       time.Duration
+	handleErr         func(err error)
+
+	mu            sync.RWMutex
+	synced        bool
+	bootstrapTime time.Time
+	balances      map[string]Balance
+	balanceTimes  map[string]time.Time
+	orders        map[int64]SpotOrder
+}
+
+// NewAccountState creates an AccountState.  The state is empty until Run is called.
+func (c *Client) NewAccountState(opts ...AccountStateOption) *AccountState {
+	s := &AccountState{
+		client:            c,
+		reconcileInterval: defaultAccountStateReconcileInterval,
+		resyncDelay:       defaultAccountStateResyncDelay,
+		handleErr:         func(error) {},
+		balances:          make(map[string]Balance),
+		balanceTimes:      make(map[string]time.Time),
+		orders:            make(map[int64]SpotOrder),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Run synchronises the account state and keeps it up to date, resynchronising upon errors from the user data
+// stream.  It blocks until the context is cancelled, returning the context's error.
+func (s *AccountState) Run(ctx context.Context) error {
+	for {
+		err := s.sync(ctx)
+		s.mu.Lock()
+		s.synced = false
+		s.mu.Unlock()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.handleErr(err)
+
+		t := time.NewTimer(s.resyncDelay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Synced returns true when the account state is synchronised with binance
+func (s *AccountState) Synced() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.synced
+}
+
+// Balance returns the balance of the given asset, or false if the account holds no balance of that asset
+func (s *AccountState) Balance(asset string) (Balance, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.balances[asset]
+	if !ok {
+		return Balance{}, false
+	}
+	return copyBalance(b), true
+}
+
+// Balances returns the balances of all assets, keyed by asset
+func (s *AccountState) Balances() map[string]Balance {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]Balance, len(s.balances))
+	for asset, b := range s.balances {
+		out[asset] = copyBalance(b)
+	}
+	return out
+}
+
+// AllOpenSpotOrders returns all open orders, ordered by order ID
+func (s *AccountState) AllOpenSpotOrders() []SpotOrder {
+	return s.openOrders(func(SpotOrder) bool { return true })
+}
+
+// OpenSpotOrdersForSymbol returns the open orders on the given symbol, ordered by order ID
+func (s *AccountState) OpenSpotOrdersForSymbol(symbol string) []SpotOrder {
+	return s.openOrders(func(o SpotOrder) bool { return o.Symbol == symbol })
+}
+
+func (s *AccountState) openOrders(include func(SpotOrder) bool) []SpotOrder {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]SpotOrder, 0, len(s.orders))
+	for _, o := range s.orders {
+		if include(o) {
+			out = append(out, o)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].OrderID < out[j].OrderID
+	})
+	return out
+}
+
+func (s *AccountState) sync(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := s.client.UserData(ctx)
+	defer func() {
+		// stop the stream and wait for it to shut down, so its connection is closed before returning
+		cancel()
+		for range events {
+		}
+	}()
+
+	info, orders, err := s.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.replace(info, orders)
+	s.synced = true
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(s.reconcileInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if err := s.handleEvent(event, ok); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := s.reconcile(ctx, events); err != nil {
+				s.handleErr(err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *AccountState) handleEvent(event UserDataEventOrError, ok bool) error {
+	if !ok {
+		return fmt.Errorf("user data stream closed")
+	}
+	if event.Err != nil {
+		return fmt.Errorf("error from user data stream: %w", event.Err)
+	}
+	s.mu.Lock()
+	s.apply(event.UserDataEvent)
+	s.mu.Unlock()
+	return nil
+}
+
+// fetch fetches the current account information and open orders from binance's REST API
+func (s *AccountState) fetch(ctx context.Context) (AccountInformation, []SpotOrder, error) {
+	info, err := s.client.AccountInformation(ctx)
+	if err != nil {
+		return AccountInformation{}, nil, fmt.Errorf("error fetching account information: %w", err)
+	}
+	orders, err := s.client.AllOpenSpotOrders(ctx)
+	if err != nil {
+		return AccountInformation{}, nil, fmt.Errorf("error fetching open orders: %w", err)
+	}
+	return info, orders, nil
+}
+
+// reconcile fetches the account state from binance's REST API, replacing the local state and returning an
+// *AccountDriftError if the two differ.
+func (s *AccountState) reconcile(ctx context.Context, events <-chan UserDataEventOrError) error {
+	info, orders, err := s.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("error reconciling account state: %w", err)
+	}
+
+	// collect events which arrived while fetching, which may or may not be reflected in the fetched state
+	var pending []UserDataEvent
+	for draining := true; draining; {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return fmt.Errorf("user data stream closed")
+			}
+			if event.Err != nil {
+				return fmt.Errorf("error from user data stream: %w", event.Err)
+			}
+			pending = append(pending, event.UserDataEvent)
+		default:
+			draining = false
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// the pending events are applied to both the local and the fetched state, so that they are not reported as
+	// drift.  Those already reflected in the fetched state are skipped as stale when applied to it.
+	for _, event := range pending {
+		s.apply(event)
+	}
+	localBalances, localOrders := s.balances, s.orders
+	s.replace(info, orders)
+	for _, event := range pending {
+		s.apply(event)
+	}
+
+	drift := &AccountDriftError{}
+	for asset, remote := range s.balances {
+		if local, ok := localBalances[asset]; !ok || !balancesEqual(local, remote) {
+			drift.Assets = append(drift.Assets, asset)
+		}
+	}
+	for asset := range localBalances {
+		if _, ok := s.balances[asset]; !ok {
+			drift.Assets = append(drift.Assets, asset)
+		}
+	}
+	for id, remote := range s.orders {
+		if local, ok := localOrders[id]; !ok || !openOrdersEqual(local, remote) {
+			drift.OrderIDs = append(drift.OrderIDs, id)
+		}
+	}
+	for id := range localOrders {
+		if _, ok := s.orders[id]; !ok {
+			drift.OrderIDs = append(drift.OrderIDs, id)
+		}
+	}
+
+	if len(drift.Assets) == 0 && len(drift.OrderIDs) == 0 {
+		return nil
+	}
+	sort.Strings(drift.Assets)
+	sort.Slice(drift.OrderIDs, func(i, j int) bool {
+		return drift.OrderIDs[i] < drift.OrderIDs[j]
+	})
+	return drift
+}
+
+// replace replaces the local state with state fetched from binance's REST API.  The caller must hold the lock.
+func (s *AccountState) replace(info AccountInformation, orders []SpotOrder) {
+	s.bootstrapTime = info.UpdateTime
+	s.balances = make(map[string]Balance, len(info.Balances))
+	s.balanceTimes = make(map[string]time.Time, len(info.Balances))
+	for asset, b := range info.Balances {
+		s.balances[asset] = b
+		s.balanceTimes[asset] = info.UpdateTime
+	}
+	s.orders = make(map[int64]SpotOrder, len(orders))
+	for _, o := range orders {
+		s.orders[o.OrderID] = o
+		if o.UpdateTime.After(s.bootstrapTime) {
+			s.bootstrapTime = o.UpdateTime
+		}
+	}
+}
+
+// apply applies an event from the user data stream.  Events older than the state they would modify are ignored.
+// The caller must hold the lock.
+func (s *AccountState) apply(event UserDataEvent) {
+	switch {
+	case event.AccountPosition != nil:
+		for _, b := range event.AccountPosition.Balances {
+			if event.AccountPosition.LastUpdateTime.Before(s.balanceTimes[b.Asset]) {
+				continue
+			}
+			s.balances[b.Asset] = b
+			s.balanceTimes[b.Asset] = event.AccountPosition.LastUpdateTime
+		}
+	case event.BalanceUpdate != nil:
+		update := event.BalanceUpdate
+		if !update.Time.After(s.balanceTimes[update.Asset]) {
+			return
+		}
+		b, ok := s.balances[update.Asset]
+		if !ok {
+			b = Balance{Asset: update.Asset, Free: new(big.Float), Locked: new(big.Float)}
+		}
+		b.Free = new(big.Float).Add(b.Free, update.BalanceDelta)
+		s.balances[update.Asset] = b
+		s.balanceTimes[update.Asset] = update.Time
+	case event.ExecutionReport != nil:
+		order := event.ExecutionReport.SpotOrder()
+		existing, ok := s.orders[order.OrderID]
+		if ok && order.UpdateTime.Before(existing.UpdateTime) {
+			return
+		}
+		if !ok && order.UpdateTime.Before(s.bootstrapTime) {
+			// the order was closed before the state was fetched
+			return
+		}
+		if order.Status.IsTerminal() {
+			delete(s.orders, order.OrderID)
+			return
+		}
+		s.orders[order.OrderID] = order
+	}
+}
+
+func balancesEqual(a, b Balance) bool {
+	return bigFloatsEqual(a.Free, b.Free) && bigFloatsEqual(a.Locked, b.Locked)
+}
+
+func openOrdersEqual(a, b SpotOrder) bool {
+	return a.Status == b.Status && bigFloatsEqual(a.ExecutedQty, b.ExecutedQty)
+}
+
+func bigFloatsEqual(a, b *big.Float) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
+
+func copyBalance(b Balance) Balance {
+	out := Balance{Asset: b.Asset}
+	if b.Free != nil {
+		out.Free = new(big.Float).Copy(b.Free)
+	}
+	if b.Locked != nil {
+		out.Locked = new(big.Float).Copy(b.Locked)
+	}
+	return out
+}
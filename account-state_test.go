@@ -0,0 +1,211 @@
+package gobinance_test
+
+import (
+	"context"
+	"errors"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAccountState_Run(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const account = `{"updateTime":1500000000000,"balances":[
+		{"asset":"BTC","free":"1.00000000","locked":"0.00000000"},
+		{"asset":"LTC","free":"5.00000000","locked":"0.00000000"}
+	]}`
+	const openOrders = `[
+		{"symbol":"ETHBTC","orderId":1,"clientOrderId":"one","price":"0.1","origQty":"1","executedQty":"0","status":"NEW","side":"BUY","type":"LIMIT","updateTime":1500000000000},
+		{"symbol":"LTCBTC","orderId":2,"clientOrderId":"two","price":"0.01","origQty":"1","executedQty":"0","status":"NEW","side":"SELL","type":"LIMIT","updateTime":1500000000000}
+	]`
+
+	mockDoer := mockRoutedDoer(t, ctrl, map[string][]string{
+		"/api/v3/account":    {account},
+		"/api/v3/openOrders": {openOrders},
+	})
+	client := newTestUserDataClient(ctrl, mockDoer, mockWebsocketMessages(ctrl, testWebsocketBaseURL+"/ws/test-listen-key",
+		// stale, so ignored
+		`{"e":"outboundAccountPosition","E":1499999999000,"u":1499999999000,"B":[{"a":"BTC","f":"999","l":"0"}]}`,
+		`{"e":"outboundAccountPosition","E":1500000001000,"u":1500000001000,"B":[{"a":"BTC","f":"0.5","l":"0.5"}]}`,
+		`{"e":"balanceUpdate","E":1500000002000,"a":"LTC","d":"1.5","T":1500000002000}`,
+		// replayed, and older than the balance update, so both ignored
+		`{"e":"balanceUpdate","E":1500000002000,"a":"LTC","d":"1.5","T":1500000002000}`,
+		`{"e":"outboundAccountPosition","E":1500000001500,"u":1500000001500,"B":[{"a":"LTC","f":"5","l":"0"}]}`,
+		`{"e":"executionReport","E":1500000003000,"s":"ETHBTC","c":"one","S":"BUY","o":"LIMIT","q":"1","p":"0.1","x":"TRADE","X":"FILLED","i":1,"z":"1","T":1500000003000}`,
+		`{"e":"executionReport","E":1500000004000,"s":"ETHBTC","c":"three","S":"SELL","o":"LIMIT","q":"2","p":"0.2","x":"NEW","X":"NEW","i":3,"z":"0","T":1500000004000}`,
+		// happened before the state was fetched, so ignored
+		`{"e":"executionReport","E":1499999990000,"s":"ETHBTC","c":"four","S":"SELL","o":"LIMIT","q":"2","p":"0.2","x":"NEW","X":"NEW","i":4,"z":"0","T":1499999990000}`,
+	))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var gotErr error
+	uut := client.NewAccountState(gobinance.AccountStateErrorHandler(func(err error) {
+		gotErr = err
+		cancel()
+	}))
+	if err := uut.Run(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled but got %v", err)
+	}
+
+	if gotErr == nil || !strings.Contains(gotErr.Error(), "user data stream") {
+		t.Errorf("expected user data stream error but got %v", gotErr)
+	}
+	if uut.Synced() {
+		t.Errorf("expected state not to be synced after an error")
+	}
+
+	expectedBalances := map[string]gobinance.Balance{
+		"BTC": {Asset: "BTC", Free: mustParseBigFloat(t, "0.5"), Locked: mustParseBigFloat(t, "0.5")},
+		"LTC": {Asset: "LTC", Free: mustParseBigFloat(t, "6.5"), Locked: mustParseBigFloat(t, "0")},
+	}
+	if diff := cmp.Diff(expectedBalances, uut.Balances(), bigFloatComparer); diff != "" {
+		t.Errorf("unexpected balances.\n%s", diff)
+	}
+	if got, ok := uut.Balance("LTC"); !ok || got.Free.Cmp(mustParseBigFloat(t, "6.5")) != 0 {
+		t.Errorf("unexpected LTC balance %v", got)
+	}
+	if _, ok := uut.Balance("ETH"); ok {
+		t.Errorf("expected no ETH balance")
+	}
+
+	var gotIDs []int64
+	for _, o := range uut.AllOpenSpotOrders() {
+		gotIDs = append(gotIDs, o.OrderID)
+	}
+	if diff := cmp.Diff([]int64{2, 3}, gotIDs); diff != "" {
+		t.Errorf("unexpected open orders.\n%s", diff)
+	}
+	ethOrders := uut.OpenSpotOrdersForSymbol("ETHBTC")
+	if len(ethOrders) != 1 || ethOrders[0].ClientOrderID != "three" {
+		t.Errorf("unexpected ETHBTC orders %#v", ethOrders)
+	}
+}
+
+func TestAccountState_Reconcile(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accounts := []string{
+		`{"updateTime":1500000000000,"balances":[{"asset":"BTC","free":"1","locked":"0"}]}`,
+		`{"updateTime":1500000001000,"balances":[{"asset":"BTC","free":"2","locked":"0"},{"asset":"LTC","free":"1","locked":"0"}]}`,
+	}
+	openOrders := []string{
+		`[{"symbol":"ETHBTC","orderId":1,"status":"NEW","executedQty":"0","updateTime":1500000000000}]`,
+		`[{"symbol":"ETHBTC","orderId":2,"status":"NEW","executedQty":"0","updateTime":1500000001000}]`,
+	}
+	mockDoer := mockRoutedDoer(t, ctrl, map[string][]string{
+		"/api/v3/account":    accounts,
+		"/api/v3/openOrders": openOrders,
+	})
+
+	conn := newFakeStreamConnection(ctrl, nil)
+	mockDialer := mock_gobinance.NewMockDialContexter(ctrl)
+	mockDialer.EXPECT().DialContext(gomock.Any(), testWebsocketBaseURL+"/ws/test-listen-key", nil).Return(conn, nil, nil)
+	client := newTestUserDataClient(ctrl, mockDoer, mockDialer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var gotErr error
+	uut := client.NewAccountState(
+		gobinance.AccountStateReconcileInterval(10*time.Millisecond),
+		gobinance.AccountStateErrorHandler(func(err error) {
+			if gotErr == nil {
+				gotErr = err
+			}
+			cancel()
+		}),
+	)
+	if err := uut.Run(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled but got %v", err)
+	}
+
+	var drift *gobinance.AccountDriftError
+	if !errors.As(gotErr, &drift) {
+		t.Fatalf("expected an AccountDriftError but got %v", gotErr)
+	}
+	expected := &gobinance.AccountDriftError{
+		Assets:   []string{"BTC", "LTC"},
+		OrderIDs: []int64{1, 2},
+	}
+	if diff := cmp.Diff(expected, drift); diff != "" {
+		t.Errorf("unexpected drift.\n%s", diff)
+	}
+	if got, ok := uut.Balance("BTC"); !ok || got.Free.Cmp(mustParseBigFloat(t, "2")) != 0 {
+		t.Errorf("expected BTC balance to be corrected but got %v", got)
+	}
+	if orders := uut.AllOpenSpotOrders(); len(orders) != 1 || orders[0].OrderID != 2 {
+		t.Errorf("expected open orders to be corrected but got %#v", orders)
+	}
+}
+
+func TestAccountState_ReconcileWithEventsDuringFetch(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const openOrders = `[{"symbol":"ETHBTC","orderId":1,"status":"NEW","executedQty":"0","updateTime":1500000000000}]`
+	conn := newFakeStreamConnection(ctrl, nil)
+	routed := mockRoutedDoer(t, ctrl, map[string][]string{
+		"/api/v3/account": {
+			`{"updateTime":1500000000000,"balances":[{"asset":"BTC","free":"1","locked":"0"}]}`,
+			// fetched before the event received during the fetch
+			`{"updateTime":1500000000000,"balances":[{"asset":"BTC","free":"1","locked":"0"}]}`,
+			`{"updateTime":1500000003000,"balances":[{"asset":"BTC","free":"2","locked":"0"}]}`,
+		},
+		"/api/v3/openOrders": {openOrders},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mu sync.Mutex
+	var accountFetches int
+	doer := gobinance.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/v3/account" {
+			mu.Lock()
+			accountFetches++
+			n := accountFetches
+			mu.Unlock()
+			switch n {
+			case 2:
+				conn.incoming <- `{"e":"outboundAccountPosition","E":1500000002000,"u":1500000002000,"B":[{"a":"BTC","f":"2","l":"0"}]}`
+				// allow the event to reach the stream's channel before the fetch completes
+				time.Sleep(50 * time.Millisecond)
+			case 4:
+				cancel()
+			}
+		}
+		return routed.Do(req)
+	})
+	mockDialer := mock_gobinance.NewMockDialContexter(ctrl)
+	mockDialer.EXPECT().DialContext(gomock.Any(), testWebsocketBaseURL+"/ws/test-listen-key", nil).Return(conn, nil, nil)
+	client := newTestUserDataClient(ctrl, doer, mockDialer)
+
+	var gotErr error
+	uut := client.NewAccountState(
+		gobinance.AccountStateReconcileInterval(10*time.Millisecond),
+		gobinance.AccountStateErrorHandler(func(err error) {
+			if gotErr == nil {
+				gotErr = err
+			}
+		}),
+	)
+	if err := uut.Run(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled but got %v", err)
+	}
+	if gotErr != nil {
+		t.Errorf("expected no drift but got %v", gotErr)
+	}
+	if got, ok := uut.Balance("BTC"); !ok || got.Free.Cmp(mustParseBigFloat(t, "2")) != 0 {
+		t.Errorf("expected the event received during the fetch to be kept but got %v", got)
+	}
+}
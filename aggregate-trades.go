@@ -0,0 +1,202 @@
+package gobinance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AggTrade is a trade or set of trades that filled at the same time, from the same order, at the same price
+type AggTrade struct {
+	AggTradeID   int64
+	Price        *big.Float
+	Quantity     *big.Float
+	FirstTradeID int64
+	LastTradeID  int64
+	Time         time.Time
+	IsBuyerMaker bool
+	IsBestMatch  bool
+}
+
+// UnmarshalJSON provides custom unmarshalling for AggTrades.
+func (a *AggTrade) UnmarshalJSON(bs []byte) error {
+	var tmp struct {
+		AggTradeID   int64           `json:"a"`
+		Price        *big.Float      `json:"p"`
+		Quantity     *big.Float      `json:"q"`
+		FirstTradeID int64           `json:"f"`
+		LastTradeID  int64           `json:"l"`
+		Time         millisTimestamp `json:"T"`
+		IsBuyerMaker bool            `json:"m"`
+		IsBestMatch  bool            `json:"M"`
+	}
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	*a = AggTrade{
+		AggTradeID:   tmp.AggTradeID,
+		Price:        tmp.Price,
+		Quantity:     tmp.Quantity,
+		FirstTradeID: tmp.FirstTradeID,
+		LastTradeID:  tmp.LastTradeID,
+		Time:         time.Time(tmp.Time),
+		IsBuyerMaker: tmp.IsBuyerMaker,
+		IsBestMatch:  tmp.IsBestMatch,
+	}
+	return nil
+}
+
+// AggregateTradesOption is a function that applies optional parameters to a request for aggregate trades
+type AggregateTradesOption func(input *aggTradesInput)
+
+// AggregateTradesFromID causes aggregate trades to be returned starting from (and including) the aggregate
+// trade with the given ID.
+func AggregateTradesFromID(id int64) AggregateTradesOption {
+	return func(input *aggTradesInput) {
+		input.FromID = &id
+	}
+}
+
+// AggregateTradesStartTime causes only aggregate trades at or after `t` to be returned
+func AggregateTradesStartTime(t time.Time) AggregateTradesOption {
+	return func(input *aggTradesInput) {
+		input.StartTime = t.UnixNano() / int64(time.Millisecond)
+	}
+}
+
+// AggregateTradesEndTime causes only aggregate trades at or before `t` to be returned
+func AggregateTradesEndTime(t time.Time) AggregateTradesOption {
+	return func(input *aggTradesInput) {
+		input.EndTime = t.UnixNano() / int64(time.Millisecond)
+	}
+}
+
+// AggregateTradesLimit sets the maximum number of aggregate trades to be returned.  When unset, binance's
+// default of 500 is used.  The maximum is 1000.
+func AggregateTradesLimit(limit int) AggregateTradesOption {
+	return func(input *aggTradesInput) {
+		input.Limit = limit
+	}
+}
+
+type aggTradesInput struct {
+	Symbol    string `param:"symbol"`
+	FromID    *int64 `param:"fromId,omitempty"`
+	StartTime int64  `param:"startTime,omitempty"`
+	EndTime   int64  `param:"endTime,omitempty"`
+	Limit     int    `param:"limit,omitempty"`
+}
+
+func applyAggTradesOptions(input *aggTradesInput, opts ...AggregateTradesOption) {
+	for _, o := range opts {
+		o(input)
+	}
+}
+
+// AggregateTrades fetches historical aggregate trades for the given symbol.  Results may be paged through
+// using the AggregateTradesFromID option with the ID following the last aggregate trade previously received.
+func (c *Client) AggregateTrades(ctx context.Context, symbol string, opts ...AggregateTradesOption) ([]AggTrade, error) {
+	input := aggTradesInput{
+		Symbol: symbol,
+	}
+	applyAggTradesOptions(&input, opts...)
+	params, err := toURLValues(input)
+	if err != nil {
+		return nil, fmt.Errorf("error building request parameters: %w", err)
+	}
+
+	req, err := c.buildUnsignedRequest(ctx, http.MethodGet, "/api/v3/aggTrades", params, false)
+	if err != nil {
+		return nil, fmt.Errorf("error building request: %w", err)
+	}
+
+	var result []AggTrade
+	err = performRequest(c.Doer, req, &result)
+	return result, err
+}
+
+// AggTradeEvent define websocket aggregate trade event
+type AggTradeEvent struct {
+	Event        string
+	Time         time.Time
+	Symbol       string
+	AggTradeID   int64
+	Price        *big.Float
+	Quantity     *big.Float
+	FirstTradeID int64
+	LastTradeID  int64
+	TradeTime    time.Time
+	IsBuyerMaker bool
+}
+
+// UnmarshalJSON provides custom unmarshalling for AggTradeEvents.
+func (t *AggTradeEvent) UnmarshalJSON(bs []byte) error {
+	var tmp struct {
+		Event        string          `json:"e"`
+		Time         millisTimestamp `json:"E"`
+		Symbol       string          `json:"s"`
+		AggTradeID   int64           `json:"a"`
+		Price        *big.Float      `json:"p"`
+		Quantity     *big.Float      `json:"q"`
+		FirstTradeID int64           `json:"f"`
+		LastTradeID  int64           `json:"l"`
+		TradeTime    millisTimestamp `json:"T"`
+		IsBuyerMaker bool            `json:"m"`
+		Placeholder  bool            `json:"M"` // add this field to avoid case insensitive unmarshaling
+	}
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	*t = AggTradeEvent{
+		Event:        tmp.Event,
+		Time:         time.Time(tmp.Time),
+		Symbol:       tmp.Symbol,
+		AggTradeID:   tmp.AggTradeID,
+		Price:        tmp.Price,
+		Quantity:     tmp.Quantity,
+		FirstTradeID: tmp.FirstTradeID,
+		LastTradeID:  tmp.LastTradeID,
+		TradeTime:    time.Time(tmp.TradeTime),
+		IsBuyerMaker: tmp.IsBuyerMaker,
+	}
+	return nil
+}
+
+// AggTradeEventOrError is a union of AggTradeEvent or error
+type AggTradeEventOrError struct {
+	AggTradeEvent
+	Err error
+}
+
+// AggTrades initiates a websocket connection to binance and returns a channel from which live aggregate trades can
+// be streamed from binance.  The channel is closed when the underlying context is cancelled, or upon a connection
+// error or the server closing the connection.
+func (c *Client) AggTrades(ctx context.Context, symbol string) <-chan AggTradeEventOrError {
+	out := make(chan AggTradeEventOrError, 1)
+	handle := func(reader io.Reader, err error) error {
+		if err != nil {
+			out <- AggTradeEventOrError{Err: err}
+			return nil
+		}
+		var trade AggTradeEvent
+		dec := json.NewDecoder(reader)
+		if err := dec.Decode(&trade); err != nil {
+			out <- AggTradeEventOrError{Err: fmt.Errorf("error decoding aggregate trade event: %w", err)}
+			return err
+		}
+		out <- AggTradeEventOrError{AggTradeEvent: trade}
+		return nil
+	}
+	path := fmt.Sprintf("/ws/%s@aggTrade", url.PathEscape(strings.ToLower(symbol)))
+
+	go c.openWebsocket(ctx, path, handle, func() {
+		close(out)
+	})
+	return out
+}
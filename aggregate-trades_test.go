@@ -0,0 +1,206 @@
+package gobinance_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_AggregateTrades(t *testing.T) {
+	t.Parallel()
+	const testSymbol = "BNBBTC"
+
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mocks *clientMocks)
+		options        []gobinance.AggregateTradesOption
+		errorCheck     errorCheck
+		expectedResult []gobinance.AggTrade
+	}{
+		{
+			name: "request values",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+					if req.Method != http.MethodGet {
+						t.Errorf("unexpected http method: expected %v but got %v", http.MethodGet, req.Method)
+					}
+					if req.URL.Path != "/api/v3/aggTrades" {
+						t.Errorf("unexpected path: expected %v but got %v", "/api/v3/aggTrades", req.URL.Path)
+					}
+					if hdr := req.Header.Get("X-MBX-APIKEY"); hdr != "" {
+						t.Errorf("expected no API key but got %v", hdr)
+					}
+					expected := url.Values{
+						"symbol":    {testSymbol},
+						"fromId":    {"26129"},
+						"startTime": {fmt.Sprint(currentTimeMillis)},
+						"endTime":   {fmt.Sprint(currentTimeMillis + 1000)},
+						"limit":     {"10"},
+					}
+					if diff := cmp.Diff(expected, req.URL.Query()); diff != "" {
+						t.Errorf("unexpected parameters passed to request:\n%v", diff)
+					}
+				}).Return(nil, fmt.Errorf("stop early"))
+			},
+			options: []gobinance.AggregateTradesOption{
+				gobinance.AggregateTradesFromID(26129),
+				gobinance.AggregateTradesStartTime(mockNow()),
+				gobinance.AggregateTradesEndTime(mockNow().Add(time.Second)),
+				gobinance.AggregateTradesLimit(10),
+			},
+			errorCheck: errNotNil,
+		},
+		{
+			name: "from the first aggregate trade",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+					expected := url.Values{
+						"symbol": {testSymbol},
+						"fromId": {"0"},
+					}
+					if diff := cmp.Diff(expected, req.URL.Query()); diff != "" {
+						t.Errorf("unexpected parameters passed to request:\n%v", diff)
+					}
+				}).Return(nil, fmt.Errorf("stop early"))
+			},
+			options: []gobinance.AggregateTradesOption{
+				gobinance.AggregateTradesFromID(0),
+			},
+			errorCheck: errNotNil,
+		},
+		{
+			name: "http error",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: 400,
+					Body:       ioutil.NopCloser(strings.NewReader(`{ "msg":"test message", "code":-1234 }`)),
+				}, nil)
+			},
+			errorCheck: isHttpError(400, -1234),
+		},
+		{
+			name: "success",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: 200,
+					Body: ioutil.NopCloser(strings.NewReader(`[
+					  {"a":26129,"p":"0.01633102","q":"4.70443515","f":27781,"l":27782,"T":1498793709153,"m":true,"M":false}
+					]`)),
+				}, nil)
+			},
+			errorCheck: errNil,
+			expectedResult: []gobinance.AggTrade{
+				{
+					AggTradeID:   26129,
+					Price:        mustParseBigFloat(t, "0.01633102"),
+					Quantity:     mustParseBigFloat(t, "4.70443515"),
+					FirstTradeID: 27781,
+					LastTradeID:  27782,
+					Time:         time.Date(2017, 06, 30, 03, 35, 9, int(153*time.Millisecond), time.UTC),
+					IsBuyerMaker: true,
+					IsBestMatch:  false,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mocks := &clientMocks{
+				MockDoer: mock_gobinance.NewMockDoer(ctrl),
+			}
+
+			u, _ := url.Parse(testBaseURL)
+			uut := &gobinance.Client{
+				HTTPApiURL: u,
+				UserAgent:  testUserAgent,
+				APIKey:     testBinanceApiKey,
+				Doer:       mocks.MockDoer,
+				Now:        mockNow,
+			}
+
+			tc.setup(t, mocks)
+			got, err := uut.AggregateTrades(context.Background(), testSymbol, tc.options...)
+			if cont := tc.errorCheck(t, err); !cont {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expectedResult, got, bigFloatComparer); diff != "" {
+				t.Errorf("unexpected result.\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_AggTrades(t *testing.T) {
+	t.Parallel()
+	const BaseURL = "wss://example.com"
+	testError := fmt.Errorf("test error")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDialer := mock_gobinance.NewMockDialContexter(ctrl)
+	mockNextReader := mock_gobinance.NewMockNextReaderCloser(ctrl)
+	mockDialer.EXPECT().DialContext(
+		gomock.Not(gomock.Nil()),
+		BaseURL+"/ws/bnbbtc@aggTrade",
+		nil).Return(mockNextReader, nil, nil)
+	mockNextReader.EXPECT().Close()
+	first := mockNextReader.EXPECT().NextReader().Return(0,
+		bytes.NewBufferString(`{"e":"aggTrade","E":123456789,"s":"BNBBTC","a":12345,"p":"0.001","q":"100","f":100,"l":105,"T":123456785,"m":true,"M":true}`),
+		nil,
+	)
+	mockNextReader.EXPECT().NextReader().Return(0, nil, testError).After(first)
+
+	baseURL, _ := url.Parse(BaseURL)
+	uut := &gobinance.Client{
+		WebsocketApiURL: baseURL,
+		DialContexter:   mockDialer,
+	}
+
+	var got []gobinance.AggTradeEventOrError
+	for trade := range uut.AggTrades(context.Background(), "BNBBTC") {
+		got = append(got, trade)
+	}
+
+	if l := len(got); l != 2 {
+		t.Fatalf("expected 2 events but got %v", l)
+	}
+	if !errors.Is(got[1].Err, testError) {
+		t.Errorf("expected error to extend from\n\t%#v\nbut got\n\t%#v", testError, got[1].Err)
+	}
+	expected := gobinance.AggTradeEventOrError{
+		AggTradeEvent: gobinance.AggTradeEvent{
+			Event:        "aggTrade",
+			Time:         time.Date(1970, 01, 02, 10, 17, 36, int(789*time.Millisecond), time.UTC),
+			Symbol:       "BNBBTC",
+			AggTradeID:   12345,
+			Price:        mustParseBigFloat(t, "0.001"),
+			Quantity:     mustParseBigFloat(t, "100"),
+			FirstTradeID: 100,
+			LastTradeID:  105,
+			TradeTime:    time.Date(1970, 01, 02, 10, 17, 36, int(785*time.Millisecond), time.UTC),
+			IsBuyerMaker: true,
+		},
+	}
+	if diff := cmp.Diff(expected, got[0], bigFloatComparer); diff != "" {
+		t.Errorf("unexpected event.  %s", diff)
+	}
+}
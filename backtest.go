@@ -0,0 +1,578 @@
+package gobinance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BacktestOption is a function that applies optional configuration to a Backtest
+type BacktestOption func(b *Backtest)
+
+// BacktestSymbol adds a symbol which may be traded, along with its base and quote assets
+func BacktestSymbol(symbol string, baseAsset string, quoteAsset string) BacktestOption {
+	return func(b *Backtest) {
+		b.exchange.AddSymbol(symbol, baseAsset, quoteAsset)
+	}
+}
+
+// BacktestAccount configures the backtest from account information, such as that returned by
+// Client.AccountInformation.  The maker and taker commissions are charged on fills, and the free balances become
+// the initial balances.
+func BacktestAccount(info AccountInformation) BacktestOption {
+	return func(b *Backtest) {
+		b.exchange.makerFee = feeFromCommission(info.MakerCommission)
+		b.exchange.takerFee = feeFromCommission(info.TakerCommission)
+		for asset, balance := range info.Balances {
+			if balance.Free != nil {
+				b.exchange.SetBalance(asset, balance.Free)
+			}
+		}
+	}
+}
+
+// BacktestBalance sets the initial free balance of an asset.  Balances not set are zero.
+func BacktestBalance(asset string, free *big.Float) BacktestOption {
+	return func(b *Backtest) {
+		b.exchange.SetBalance(asset, free)
+	}
+}
+
+// BacktestSlippage sets the fraction of the price, e.g. 0.0005 for 0.05%, by which fills taking liquidity are
+// made worse.  Limit orders never fill beyond their price.  The default is no slippage.
+func BacktestSlippage(fraction *big.Float) BacktestOption {
+	return func(b *Backtest) {
+		b.exchange.slippage = fraction
+	}
+}
+
+// BacktestEquityInterval sets the minimum time between the points of the equity curve.  The default of zero
+// records a point for every event replayed.
+func BacktestEquityInterval(d time.Duration) BacktestOption {
+	return func(b *Backtest) {
+		b.equityInterval = d
+	}
+}
+
+// BacktestKlines adds klines of the symbol to be replayed.  Each kline is replayed as four trades, each of a
+// quarter of its volume, at its open, high, low and close prices, visiting the low before the high if the kline
+// closed up and the high before the low otherwise.
+func BacktestKlines(symbol string, klines []Kline) BacktestOption {
+	return func(b *Backtest) {
+		for _, k := range klines {
+			b.addKline(symbol, k)
+		}
+	}
+}
+
+// BacktestAggTrades adds aggregate trades of the symbol to be replayed.  Each aggregate trade is replayed as a
+// single trade with its aggregate trade ID.
+func BacktestAggTrades(symbol string, trades []AggTrade) BacktestOption {
+	return func(b *Backtest) {
+		for _, t := range trades {
+			b.events = append(b.events, TradeEvent{
+				Event:        "trade",
+				Time:         t.Time,
+				Symbol:       symbol,
+				TradeID:      t.AggTradeID,
+				Price:        t.Price,
+				Quantity:     t.Quantity,
+				TradeTime:    t.Time,
+				IsBuyerMaker: t.IsBuyerMaker,
+			})
+		}
+	}
+}
+
+// BacktestFill is a fill of an order placed during a backtest
+type BacktestFill struct {
+	Time          time.Time
+	Symbol        string
+	OrderID       int64
+	ClientOrderID string
+	Type          OrderType
+	Side          OrderSide
+	IsMaker       bool
+	Fill
+}
+
+// EquityPoint is the value of an account at a point in time
+type EquityPoint struct {
+	Time   time.Time
+	Equity *big.Float
+	// Drawdown is the fall in equity from the highest equity before this point, as a fraction of that equity
+	Drawdown *big.Float
+}
+
+// BacktestReport summarises the performance of a strategy over a backtest
+type BacktestReport struct {
+	Fills       []BacktestFill
+	EquityCurve []EquityPoint
+	// MaxDrawdown is the largest Drawdown of the points of the equity curve
+	MaxDrawdown *big.Float
+	Balances    map[string]Balance
+}
+
+// Backtest replays historical trades through a simulated exchange, so that a strategy can be evaluated against
+// them.  Its order and account methods have the same signatures and results as those of Client, and its Trades
+// method produces the same channel type, so strategies can be run against either.  Limit, limit maker, market,
+// stop loss limit and take profit limit orders are supported, matching as they do for a PaperTrader.  Orders
+// taking liquidity fill at the last replayed price, with slippage applied.
+//
+// Trades are sent on unbuffered channels, and each trade is applied to the simulated exchange once it has been
+// received, before any further requests are handled.  While Run is waiting for a trade to be received, requests
+// made by the strategy are handled against the state after the previous trade.  A strategy which handles trades
+// in a single goroutine therefore sees the orders it places in response to a trade matched against the trades
+// that follow it, and the results of the backtest are repeatable.  Trades of symbols without subscribers are
+// applied without waiting for the strategy.
+//
+// A backtest is typically run by subscribing to trades, starting Run in another goroutine and handling trades
+// until the channel is closed, then calling Report:
+//
+//	bt := gobinance.NewBacktest("USDT", opts...)
+//	trades := bt.Trades(ctx, "BTCUSDT")
+//	go bt.Run(ctx)
+//	for trade := range trades {
+//		// place orders with bt
+//	}
+//	report := bt.Report()
+//
+// All methods are safe to call concurrently.
+type Backtest struct {
+	exchange       *SimulatedExchange
+	valuationAsset string
+	equityInterval time.Duration
+	events         []TradeEvent
+	nextTradeID    map[string]int64
+	requests       chan backtestRequest
+	done           chan struct{}
+
+	mu          sync.Mutex
+	now         time.Time
+	subscribers []*backtestSubscriber
+	running     bool
+	finished    bool
+	fills       []BacktestFill
+	equityCurve []EquityPoint
+	peak        *big.Float
+	maxDrawdown *big.Float
+}
+
+type backtestSubscriber struct {
+	ctx    context.Context
+	symbol string
+	out    chan TradeEventOrError
+}
+
+// backtestRequest is a call to the simulated exchange made while Run is replaying trades
+type backtestRequest struct {
+	fn   func()
+	done chan struct{}
+}
+
+// NewBacktest creates a Backtest whose equity is valued in valuationAsset.  Assets other than the valuation asset
+// are valued at the last replayed price of a symbol trading them against it, and are ignored until such a price
+// is known.
+func NewBacktest(valuationAsset string, opts ...BacktestOption) *Backtest {
+	b := &Backtest{
+		exchange:       NewSimulatedExchange(),
+		valuationAsset: valuationAsset,
+		nextTradeID:    make(map[string]int64),
+		requests:       make(chan backtestRequest),
+		done:           make(chan struct{}),
+		maxDrawdown:    new(big.Float),
+	}
+	b.exchange.onExecution = b.recordExecution
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+// feeFromCommission converts an account commission, given in basis points, into a fee rate
+func feeFromCommission(commission int64) *big.Float {
+	return new(big.Float).Quo(new(big.Float).SetInt64(commission), big.NewFloat(10000))
+}
+
+func (b *Backtest) addKline(symbol string, k Kline) {
+	prices := []*big.Float{k.Open, k.High, k.Low, k.Close}
+	if k.Close.Cmp(k.Open) >= 0 {
+		prices[1], prices[2] = k.Low, k.High
+	}
+	qty := new(big.Float)
+	if k.Volume != nil {
+		qty.Quo(k.Volume, big.NewFloat(float64(len(prices))))
+	}
+	step := k.CloseTime.Sub(k.OpenTime) / time.Duration(len(prices)-1)
+	for i, price := range prices {
+		at := k.OpenTime.Add(time.Duration(i) * step)
+		if i == len(prices)-1 {
+			at = k.CloseTime
+		}
+		b.nextTradeID[symbol]++
+		b.events = append(b.events, TradeEvent{
+			Event:     "trade",
+			Time:      at,
+			Symbol:    symbol,
+			TradeID:   b.nextTradeID[symbol],
+			Price:     price,
+			Quantity:  qty,
+			TradeTime: at,
+		})
+	}
+}
+
+// Trades returns a channel on which the replayed trades of the symbol are sent.  The channel is closed when the
+// backtest finishes.  If the context is cancelled, no more trades are sent and the channel is left open until
+// the backtest finishes.  Subscriptions should be made before Run is called, so that no trades are missed.
+func (b *Backtest) Trades(ctx context.Context, symbol string) <-chan TradeEventOrError {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sub := &backtestSubscriber{ctx: ctx, symbol: symbol, out: make(chan TradeEventOrError)}
+	if b.finished {
+		close(sub.out)
+		return sub.out
+	}
+	b.subscribers = append(b.subscribers, sub)
+	return sub.out
+}
+
+// Run replays the trades in order of trade time, closing the channels returned by Trades once all have been sent.
+// It returns nil once all trades have been replayed, or the context's error if it is cancelled first.  Run
+// should only be called once.
+func (b *Backtest) Run(ctx context.Context) error {
+	b.mu.Lock()
+	b.running = true
+	b.mu.Unlock()
+	defer b.finish()
+
+	sort.SliceStable(b.events, func(i, j int) bool {
+		return b.events[i].TradeTime.Before(b.events[j].TradeTime)
+	})
+	for i, event := range b.events {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, sub := range b.subscriptions(event.Symbol) {
+			if err := b.send(ctx, sub, TradeEventOrError{TradeEvent: event}); err != nil {
+				return err
+			}
+		}
+
+		b.mu.Lock()
+		b.now = event.TradeTime
+		b.mu.Unlock()
+		b.exchange.Trade(event.Symbol, event.Price, event.Quantity, event.TradeTime)
+		b.recordEquity(event.TradeTime, i == len(b.events)-1)
+	}
+	return nil
+}
+
+// send sends an event to a subscriber, handling requests until it is received
+func (b *Backtest) send(ctx context.Context, sub *backtestSubscriber, event TradeEventOrError) error {
+	for {
+		select {
+		case sub.out <- event:
+			return nil
+		case req := <-b.requests:
+			req.fn()
+			close(req.done)
+		case <-sub.ctx.Done():
+			// the subscriber's context may be the backtest's own
+			return ctx.Err()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// do calls fn, in the goroutine running the backtest if it is running
+func (b *Backtest) do(ctx context.Context, fn func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	running := b.running
+	b.mu.Unlock()
+	if running {
+		req := backtestRequest{fn: fn, done: make(chan struct{})}
+		select {
+		case b.requests <- req:
+			<-req.done
+			return nil
+		case <-b.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	fn()
+	return nil
+}
+
+// subscriptions returns the subscribers to the trades of symbol whose context has not been cancelled
+func (b *Backtest) subscriptions(symbol string) []*backtestSubscriber {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []*backtestSubscriber
+	for _, sub := range b.subscribers {
+		if sub.symbol == symbol && sub.ctx.Err() == nil {
+			out = append(out, sub)
+		}
+	}
+	return out
+}
+
+func (b *Backtest) finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.running = false
+	b.finished = true
+	close(b.done)
+	for _, sub := range b.subscribers {
+		close(sub.out)
+	}
+	b.subscribers = nil
+}
+
+// recordExecution is called by the simulated exchange, with its lock held, for each execution, and records those
+// which are fills
+func (b *Backtest) recordExecution(x SimulatedExecution) {
+	if x.Fill == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fills = append(b.fills, BacktestFill{
+		Time:          x.Time,
+		Symbol:        x.Order.Symbol,
+		OrderID:       x.Order.OrderID,
+		ClientOrderID: x.Order.ClientOrderID,
+		Type:          x.Order.Type,
+		Side:          x.Order.Side,
+		IsMaker:       x.IsMaker,
+		Fill:          *x.Fill,
+	})
+}
+
+// recordEquity adds a point to the equity curve, unless one was added within the equity interval and force is
+// false
+func (b *Backtest) recordEquity(at time.Time, force bool) {
+	b.mu.Lock()
+	if n := len(b.equityCurve); !force && n > 0 && at.Sub(b.equityCurve[n-1].Time) < b.equityInterval {
+		b.mu.Unlock()
+		return
+	}
+	b.mu.Unlock()
+
+	equity := b.exchange.equity(b.valuationAsset)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.peak == nil || equity.Cmp(b.peak) > 0 {
+		b.peak = equity
+	}
+	drawdown := new(big.Float)
+	if b.peak.Sign() > 0 {
+		drawdown.Quo(new(big.Float).Sub(b.peak, equity), b.peak)
+	}
+	if drawdown.Cmp(b.maxDrawdown) > 0 {
+		b.maxDrawdown = drawdown
+	}
+	b.equityCurve = append(b.equityCurve, EquityPoint{Time: at, Equity: equity, Drawdown: drawdown})
+}
+
+// Report returns the fills made, the equity curve and the balances held so far
+func (b *Backtest) Report() BacktestReport {
+	balances := b.exchange.AccountInformation().Balances
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BacktestReport{
+		Fills:       append([]BacktestFill{}, b.fills...),
+		EquityCurve: append([]EquityPoint{}, b.equityCurve...),
+		MaxDrawdown: b.maxDrawdown,
+		Balances:    balances,
+	}
+}
+
+func (b *Backtest) clock() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.now
+}
+
+// PlaceLimitOrder places a simulated limit order
+func (b *Backtest) PlaceLimitOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, price *big.Float, tif TimeInForce, opts ...SpotOrderOption) (SpotOrderResult, error) {
+	return b.placeOrder(ctx, spotOrderInput{
+		Type:        OrderTypeLimit,
+		Symbol:      symbol,
+		Side:        side,
+		Quantity:    qty,
+		Price:       price,
+		TimeInForce: tif,
+	}, opts)
+}
+
+// PlaceLimitMakerOrder places a simulated limit order which is rejected if it would take liquidity
+func (b *Backtest) PlaceLimitMakerOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, price *big.Float, opts ...SpotOrderOption) (SpotOrderResult, error) {
+	return b.placeOrder(ctx, spotOrderInput{
+		Type:     OrderTypeLimitMaker,
+		Symbol:   symbol,
+		Side:     side,
+		Quantity: qty,
+		Price:    price,
+	}, opts)
+}
+
+// PlaceSpotMarketOrder places a simulated market order.  See Client.PlaceSpotMarketOrder for the meaning of qty
+// and asset.
+func (b *Backtest) PlaceSpotMarketOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, asset QuantityAsset, opts ...SpotOrderOption) (SpotOrderResult, error) {
+	input := spotOrderInput{
+		Type:   OrderTypeMarket,
+		Symbol: symbol,
+		Side:   side,
+	}
+	switch asset {
+	case QuantityAssetBase:
+		input.Quantity = qty
+	case QuantityAssetQuote:
+		input.QuoteOrderQty = qty
+	default:
+		return SpotOrderResult{}, fmt.Errorf("unknown asset value '%v'", asset)
+	}
+	return b.placeOrder(ctx, input, opts)
+}
+
+// PlaceStopLossLimitOrder places a simulated stop loss limit order, which becomes a limit order once a trade is
+// made at or beyond the stop price.  The order is rejected if the last replayed price has already reached the
+// stop price.
+func (b *Backtest) PlaceStopLossLimitOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, stopPrice *big.Float, limitPrice *big.Float, tif TimeInForce, opts ...SpotOrderOption) (SpotOrderResult, error) {
+	return b.placeOrder(ctx, spotOrderInput{
+		Type:        OrderTypeStopLossLimit,
+		Symbol:      symbol,
+		Side:        side,
+		Quantity:    qty,
+		StopPrice:   stopPrice,
+		Price:       limitPrice,
+		TimeInForce: tif,
+	}, opts)
+}
+
+// PlaceTakeProfitLimitOrder places a simulated take profit limit order, which becomes a limit order once a trade
+// is made at or beyond the stop price.  The order is rejected if the last replayed price has already reached the
+// stop price.
+func (b *Backtest) PlaceTakeProfitLimitOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, stopPrice *big.Float, limitPrice *big.Float, tif TimeInForce, opts ...SpotOrderOption) (SpotOrderResult, error) {
+	return b.placeOrder(ctx, spotOrderInput{
+		Type:        OrderTypeTakeProfitLimit,
+		Symbol:      symbol,
+		Side:        side,
+		Quantity:    qty,
+		StopPrice:   stopPrice,
+		Price:       limitPrice,
+		TimeInForce: tif,
+	}, opts)
+}
+
+func (b *Backtest) placeOrder(ctx context.Context, input spotOrderInput, opts []SpotOrderOption) (SpotOrderResult, error) {
+	applySpotOrderOptions(&input, opts...)
+	var result SpotOrderResult
+	var err error
+	if doErr := b.do(ctx, func() {
+		result, err = b.exchange.place(input, b.clock())
+	}); doErr != nil {
+		return SpotOrderResult{}, doErr
+	}
+	return result, err
+}
+
+// CancelOrderByOrderID cancels a simulated order with the given order ID
+func (b *Backtest) CancelOrderByOrderID(ctx context.Context, symbol string, orderID int64, opts ...CancelSpotOrderOption) (CancelSpotOrderResult, error) {
+	return b.cancelOrder(ctx, cancelSpotOrderInput{
+		Symbol:  symbol,
+		OrderID: orderID,
+	}, opts)
+}
+
+// CancelOrderByClientOrderID cancels a simulated order with the given client order ID
+func (b *Backtest) CancelOrderByClientOrderID(ctx context.Context, symbol string, clientOrderID string, opts ...CancelSpotOrderOption) (CancelSpotOrderResult, error) {
+	return b.cancelOrder(ctx, cancelSpotOrderInput{
+		Symbol:            symbol,
+		OrigClientOrderID: clientOrderID,
+	}, opts)
+}
+
+func (b *Backtest) cancelOrder(ctx context.Context, input cancelSpotOrderInput, opts []CancelSpotOrderOption) (CancelSpotOrderResult, error) {
+	applyCancelSpotOrderOptions(&input, opts...)
+	var result CancelSpotOrderResult
+	var err error
+	if doErr := b.do(ctx, func() {
+		result, err = b.exchange.cancel(input, b.clock())
+	}); doErr != nil {
+		return CancelSpotOrderResult{}, doErr
+	}
+	return result, err
+}
+
+// QueryOrderByID returns the simulated order with the given order ID
+func (b *Backtest) QueryOrderByID(ctx context.Context, symbol string, orderID int64, opts ...QueryOrderOption) (SpotOrder, error) {
+	return b.queryOrder(ctx, queryOrderInput{
+		Symbol:  symbol,
+		OrderID: orderID,
+	}, opts)
+}
+
+// QueryOrderByClientID returns the simulated order with the given client order ID
+func (b *Backtest) QueryOrderByClientID(ctx context.Context, symbol string, clientOrderID string, opts ...QueryOrderOption) (SpotOrder, error) {
+	return b.queryOrder(ctx, queryOrderInput{
+		Symbol:            symbol,
+		OrigClientOrderID: clientOrderID,
+	}, opts)
+}
+
+func (b *Backtest) queryOrder(ctx context.Context, input queryOrderInput, opts []QueryOrderOption) (SpotOrder, error) {
+	applyQueryOrderOptions(&input, opts)
+	var order SpotOrder
+	var err error
+	if doErr := b.do(ctx, func() {
+		order, err = b.exchange.query(input)
+	}); doErr != nil {
+		return SpotOrder{}, doErr
+	}
+	return order, err
+}
+
+// OpenSpotOrdersForSymbol returns the open simulated orders on the symbol
+func (b *Backtest) OpenSpotOrdersForSymbol(ctx context.Context, symbol string, opts ...OpenOrdersOptions) ([]SpotOrder, error) {
+	var orders []SpotOrder
+	if err := b.do(ctx, func() {
+		orders = b.exchange.OpenOrders(symbol)
+	}); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// AllOpenSpotOrders returns all open simulated orders
+func (b *Backtest) AllOpenSpotOrders(ctx context.Context, opts ...OpenOrdersOptions) ([]SpotOrder, error) {
+	var orders []SpotOrder
+	if err := b.do(ctx, func() {
+		orders = b.exchange.OpenOrders("")
+	}); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// AccountInformation returns the simulated balances.  The commission rates are given in basis points, as binance
+// does.
+func (b *Backtest) AccountInformation(ctx context.Context) (AccountInformation, error) {
+	var info AccountInformation
+	if err := b.do(ctx, func() {
+		info = b.exchange.AccountInformation()
+	}); err != nil {
+		return AccountInformation{}, err
+	}
+	return info, nil
+}
@@ -0,0 +1,213 @@
+package gobinance_test
+
+import (
+	"context"
+	"errors"
+	"github.com/beyondallrepair/gobinance"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var backtestStart = time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+
+func backtestKline(start time.Duration, open, high, low, close float64) gobinance.Kline {
+	return gobinance.Kline{
+		OpenTime:  backtestStart.Add(start),
+		CloseTime: backtestStart.Add(start + 3*time.Minute),
+		Open:      big.NewFloat(open),
+		High:      big.NewFloat(high),
+		Low:       big.NewFloat(low),
+		Close:     big.NewFloat(close),
+		Volume:    big.NewFloat(4),
+	}
+}
+
+// runBacktest runs the backtest, calling strategy with each trade of the symbol, and returns the report
+func runBacktest(t *testing.T, bt *gobinance.Backtest, symbol string, strategy func(ctx context.Context, trade gobinance.TradeEvent)) gobinance.BacktestReport {
+	t.Helper()
+	ctx := context.Background()
+	trades := bt.Trades(ctx, symbol)
+	done := make(chan error)
+	go func() {
+		done <- bt.Run(ctx)
+	}()
+	for trade := range trades {
+		if trade.Err != nil {
+			t.Fatalf("unexpected error: %v", trade.Err)
+		}
+		strategy(ctx, trade.TradeEvent)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return bt.Report()
+}
+
+func TestBacktest_Klines(t *testing.T) {
+	t.Parallel()
+	bt := gobinance.NewBacktest("USDT",
+		gobinance.BacktestSymbol("BTCUSDT", "BTC", "USDT"),
+		gobinance.BacktestAccount(gobinance.AccountInformation{
+			MakerCommission: 0,
+			TakerCommission: 100,
+			Balances: map[string]gobinance.Balance{
+				"USDT": {Asset: "USDT", Free: big.NewFloat(1000), Locked: big.NewFloat(0)},
+			},
+		}),
+		gobinance.BacktestKlines("BTCUSDT", []gobinance.Kline{
+			backtestKline(0, 100, 110, 95, 105),
+			backtestKline(4*time.Minute, 105, 106, 80, 85),
+		}),
+	)
+
+	var prices []float64
+	report := runBacktest(t, bt, "BTCUSDT", func(ctx context.Context, trade gobinance.TradeEvent) {
+		price, _ := trade.Price.Float64()
+		prices = append(prices, price)
+		if trade.TradeID != 1 {
+			return
+		}
+		if _, err := bt.PlaceSpotMarketOrder(ctx, "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(1), gobinance.QuantityAssetBase); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := bt.PlaceStopLossLimitOrder(ctx, "BTCUSDT", gobinance.OrderSideSell, big.NewFloat(0.99), big.NewFloat(90), big.NewFloat(75),
+			gobinance.TimeInForceGoodTilCanceled, gobinance.SpotClientOrderID("stop")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err := bt.PlaceTakeProfitLimitOrder(ctx, "BTCUSDT", gobinance.OrderSideSell, big.NewFloat(0.99), big.NewFloat(99), big.NewFloat(99),
+			gobinance.TimeInForceGoodTilCanceled)
+		isHttpError(400, -2010)(t, err)
+		_, err = bt.PlaceLimitMakerOrder(ctx, "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(1), big.NewFloat(101))
+		isHttpError(400, -2010)(t, err)
+	})
+
+	if diff := cmp.Diff([]float64{100, 95, 110, 105, 105, 106, 80, 85}, prices); diff != "" {
+		t.Errorf("unexpected replayed prices.\n%s", diff)
+	}
+	expectedFills := []gobinance.BacktestFill{
+		{
+			Time: backtestStart, Symbol: "BTCUSDT", OrderID: 1, ClientOrderID: "simulated-1", Type: gobinance.OrderTypeMarket, Side: gobinance.OrderSideBuy,
+			Fill: gobinance.Fill{Price: big.NewFloat(100), Qty: big.NewFloat(1), Commission: big.NewFloat(0.01), CommissionAsset: "BTC", TradeID: 1},
+		},
+		{
+			Time: backtestStart.Add(6 * time.Minute), Symbol: "BTCUSDT", OrderID: 2, ClientOrderID: "stop", Type: gobinance.OrderTypeStopLossLimit, Side: gobinance.OrderSideSell,
+			Fill: gobinance.Fill{Price: big.NewFloat(80), Qty: big.NewFloat(0.99), Commission: big.NewFloat(0.792), CommissionAsset: "USDT", TradeID: 2},
+		},
+	}
+	approx := cmp.Comparer(func(a, b *big.Float) bool {
+		diff, _ := new(big.Float).Sub(a, b).Float64()
+		return diff < 1e-9 && diff > -1e-9
+	})
+	if diff := cmp.Diff(expectedFills, report.Fills, approx); diff != "" {
+		t.Errorf("unexpected fills.\n%s", diff)
+	}
+
+	var times []time.Time
+	var equity []float64
+	for _, point := range report.EquityCurve {
+		times = append(times, point.Time)
+		v, _ := point.Equity.Float64()
+		equity = append(equity, v)
+	}
+	if diff := cmp.Diff([]float64{1000, 994.05, 1008.9, 1003.95, 1003.95, 1004.94, 978.408, 978.408}, equity, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("unexpected equity curve.\n%s", diff)
+	}
+	if len(times) != 8 || !times[0].Equal(backtestStart) || !times[7].Equal(backtestStart.Add(7*time.Minute)) {
+		t.Errorf("unexpected equity curve times %v", times)
+	}
+	maxDrawdown, _ := report.MaxDrawdown.Float64()
+	if diff := cmp.Diff((1008.9-978.408)/1008.9, maxDrawdown, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("unexpected max drawdown.\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]gobinance.Balance{
+		"BTC":  {Asset: "BTC", Free: big.NewFloat(0), Locked: big.NewFloat(0)},
+		"USDT": {Asset: "USDT", Free: big.NewFloat(978.408), Locked: big.NewFloat(0)},
+	}, report.Balances, approx); diff != "" {
+		t.Errorf("unexpected balances.\n%s", diff)
+	}
+}
+
+func TestBacktest_AggTrades(t *testing.T) {
+	t.Parallel()
+	aggTrade := func(id int64, offset time.Duration, price float64) gobinance.AggTrade {
+		return gobinance.AggTrade{AggTradeID: id, Price: big.NewFloat(price), Quantity: big.NewFloat(1), Time: backtestStart.Add(offset)}
+	}
+	bt := gobinance.NewBacktest("USDT",
+		gobinance.BacktestSymbol("BTCUSDT", "BTC", "USDT"),
+		gobinance.BacktestSymbol("ETHUSDT", "ETH", "USDT"),
+		gobinance.BacktestBalance("USDT", big.NewFloat(1000)),
+		gobinance.BacktestBalance("ETH", big.NewFloat(1)),
+		gobinance.BacktestEquityInterval(time.Minute),
+		// trades are replayed in order of time across symbols
+		gobinance.BacktestAggTrades("BTCUSDT", []gobinance.AggTrade{
+			aggTrade(10, 0, 100), aggTrade(11, 2*time.Minute, 98), aggTrade(12, 3*time.Minute, 99),
+		}),
+		gobinance.BacktestAggTrades("ETHUSDT", []gobinance.AggTrade{
+			aggTrade(20, 30*time.Second, 10), aggTrade(21, 90*time.Second, 11),
+		}),
+	)
+
+	var ids []int64
+	report := runBacktest(t, bt, "BTCUSDT", func(ctx context.Context, trade gobinance.TradeEvent) {
+		ids = append(ids, trade.TradeID)
+		if trade.TradeID == 10 {
+			if _, err := bt.PlaceLimitMakerOrder(ctx, "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(2), big.NewFloat(98)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	})
+
+	if diff := cmp.Diff([]int64{10, 11, 12}, ids); diff != "" {
+		t.Errorf("unexpected replayed trades.\n%s", diff)
+	}
+	if len(report.Fills) != 1 || !report.Fills[0].IsMaker || report.Fills[0].Qty.Cmp(big.NewFloat(1)) != 0 ||
+		!report.Fills[0].Time.Equal(backtestStart.Add(2*time.Minute)) {
+		t.Errorf("unexpected fills %#v", report.Fills)
+	}
+	var equity []float64
+	for _, point := range report.EquityCurve {
+		v, _ := point.Equity.Float64()
+		equity = append(equity, v)
+	}
+	// the first point is recorded before the ETH price is known, and the last regardless of the interval.  The
+	// default maker fee of 0.1% is charged on the fill.
+	if diff := cmp.Diff([]float64{1000, 1011, 1011.901}, equity, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("unexpected equity curve.\n%s", diff)
+	}
+
+	open, err := bt.OpenSpotOrdersForSymbol(context.Background(), "BTCUSDT")
+	if err != nil || len(open) != 1 || open[0].ExecutedQty.Cmp(big.NewFloat(1)) != 0 {
+		t.Errorf("unexpected open orders %#v, %v", open, err)
+	}
+	if trades := bt.Trades(context.Background(), "BTCUSDT"); func() bool { _, ok := <-trades; return ok }() {
+		t.Errorf("expected trades subscribed after the backtest finished to be closed")
+	}
+}
+
+func TestBacktest_Cancelled(t *testing.T) {
+	t.Parallel()
+	bt := gobinance.NewBacktest("USDT",
+		gobinance.BacktestSymbol("BTCUSDT", "BTC", "USDT"),
+		gobinance.BacktestKlines("BTCUSDT", []gobinance.Kline{backtestKline(0, 100, 110, 95, 105)}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	trades := bt.Trades(ctx, "BTCUSDT")
+	done := make(chan error)
+	go func() {
+		done <- bt.Run(ctx)
+	}()
+	<-trades
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled but got %v", err)
+	}
+	if _, ok := <-trades; ok {
+		t.Errorf("expected the trades channel to be closed")
+	}
+	if _, err := bt.AccountInformation(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled but got %v", err)
+	}
+}
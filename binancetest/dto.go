@@ -0,0 +1,163 @@
+package binancetest
+
+import (
+	"github.com/beyondallrepair/gobinance"
+)
+
+// the types in this file are the JSON representations used by binance
+
+type balanceDTO struct {
+	Asset  string `json:"asset"`
+	Free   string `json:"free"`
+	Locked string `json:"locked"`
+}
+
+type accountDTO struct {
+	MakerCommission  int64        `json:"makerCommission"`
+	TakerCommission  int64        `json:"takerCommission"`
+	BuyerCommission  int64        `json:"buyerCommission"`
+	SellerCommission int64        `json:"sellerCommission"`
+	CanTrade         bool         `json:"canTrade"`
+	CanWithdraw      bool         `json:"canWithdraw"`
+	CanDeposit       bool         `json:"canDeposit"`
+	UpdateTime       int64        `json:"updateTime"`
+	AccountType      string       `json:"accountType"`
+	Balances         []balanceDTO `json:"balances"`
+	Permissions      []string     `json:"permissions"`
+}
+
+type orderAckDTO struct {
+	Symbol        string `json:"symbol"`
+	OrderID       int64  `json:"orderId"`
+	OrderListID   int64  `json:"orderListId"`
+	ClientOrderID string `json:"clientOrderId"`
+	TransactTime  int64  `json:"transactTime"`
+}
+
+type orderResultDTO struct {
+	orderAckDTO
+	Price               string                `json:"price"`
+	OrigQty             string                `json:"origQty"`
+	ExecutedQty         string                `json:"executedQty"`
+	CummulativeQuoteQty string                `json:"cummulativeQuoteQty"`
+	Status              gobinance.OrderStatus `json:"status"`
+	TimeInForce         gobinance.TimeInForce `json:"timeInForce"`
+	Type                gobinance.OrderType   `json:"type"`
+	Side                gobinance.OrderSide   `json:"side"`
+}
+
+type fillDTO struct {
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	TradeID         int64  `json:"tradeId"`
+}
+
+type orderFullDTO struct {
+	orderResultDTO
+	Fills []fillDTO `json:"fills"`
+}
+
+type orderDTO struct {
+	Symbol              string                `json:"symbol"`
+	OrderID             int64                 `json:"orderId"`
+	OrderListID         int64                 `json:"orderListId"`
+	ClientOrderID       string                `json:"clientOrderId"`
+	Price               string                `json:"price"`
+	OrigQty             string                `json:"origQty"`
+	ExecutedQty         string                `json:"executedQty"`
+	CummulativeQuoteQty string                `json:"cummulativeQuoteQty"`
+	Status              gobinance.OrderStatus `json:"status"`
+	TimeInForce         gobinance.TimeInForce `json:"timeInForce"`
+	Type                gobinance.OrderType   `json:"type"`
+	Side                gobinance.OrderSide   `json:"side"`
+	StopPrice           string                `json:"stopPrice"`
+	IcebergQty          string                `json:"icebergQty"`
+	Time                int64                 `json:"time"`
+	UpdateTime          int64                 `json:"updateTime"`
+	IsWorking           bool                  `json:"isWorking"`
+	OrigQuoteOrderQty   string                `json:"origQuoteOrderQty"`
+}
+
+type cancelDTO struct {
+	Symbol              string                `json:"symbol"`
+	OrigClientOrderID   string                `json:"origClientOrderId"`
+	OrderID             int64                 `json:"orderId"`
+	OrderListID         int64                 `json:"orderListId"`
+	ClientOrderID       string                `json:"clientOrderId"`
+	Price               string                `json:"price"`
+	OrigQty             string                `json:"origQty"`
+	ExecutedQty         string                `json:"executedQty"`
+	CummulativeQuoteQty string                `json:"cummulativeQuoteQty"`
+	Status              gobinance.OrderStatus `json:"status"`
+	TimeInForce         gobinance.TimeInForce `json:"timeInForce"`
+	Type                gobinance.OrderType   `json:"type"`
+	Side                gobinance.OrderSide   `json:"side"`
+}
+
+type listenKeyDTO struct {
+	ListenKey string `json:"listenKey"`
+}
+
+type tradeEventDTO struct {
+	Event         string `json:"e"`
+	Time          int64  `json:"E"`
+	Symbol        string `json:"s"`
+	TradeID       int64  `json:"t"`
+	Price         string `json:"p"`
+	Quantity      string `json:"q"`
+	BuyerOrderID  int64  `json:"b"`
+	SellerOrderID int64  `json:"a"`
+	TradeTime     int64  `json:"T"`
+	IsBuyerMaker  bool   `json:"m"`
+	Ignore        bool   `json:"M"`
+}
+
+type accountPositionBalanceDTO struct {
+	Asset  string `json:"a"`
+	Free   string `json:"f"`
+	Locked string `json:"l"`
+}
+
+type accountPositionDTO struct {
+	Event          string                      `json:"e"`
+	Time           int64                       `json:"E"`
+	LastUpdateTime int64                       `json:"u"`
+	Balances       []accountPositionBalanceDTO `json:"B"`
+}
+
+type executionReportDTO struct {
+	Event                 string                  `json:"e"`
+	Time                  int64                   `json:"E"`
+	Symbol                string                  `json:"s"`
+	ClientOrderID         string                  `json:"c"`
+	Side                  gobinance.OrderSide     `json:"S"`
+	Type                  gobinance.OrderType     `json:"o"`
+	TimeInForce           gobinance.TimeInForce   `json:"f"`
+	Quantity              string                  `json:"q"`
+	Price                 string                  `json:"p"`
+	StopPrice             string                  `json:"P"`
+	IcebergQty            string                  `json:"F"`
+	OrderListID           int64                   `json:"g"`
+	OriginalClientOrderID string                  `json:"C"`
+	ExecutionType         gobinance.ExecutionType `json:"x"`
+	Status                gobinance.OrderStatus   `json:"X"`
+	RejectReason          string                  `json:"r"`
+	OrderID               int64                   `json:"i"`
+	LastExecutedQty       string                  `json:"l"`
+	CumulativeFilledQty   string                  `json:"z"`
+	LastExecutedPrice     string                  `json:"L"`
+	Commission            string                  `json:"n"`
+	CommissionAsset       *string                 `json:"N"`
+	TransactionTime       int64                   `json:"T"`
+	TradeID               int64                   `json:"t"`
+	Ignore                int64                   `json:"I"`
+	IsWorking             bool                    `json:"w"`
+	IsMaker               bool                    `json:"m"`
+	Placeholder           bool                    `json:"M"`
+	CreationTime          int64                   `json:"O"`
+	CumulativeQuoteQty    string                  `json:"Z"`
+	LastQuoteQty          string                  `json:"Y"`
+	QuoteOrderQty         string                  `json:"Q"`
+}
@@ -0,0 +1,360 @@
+package binancetest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// precision is the precision of the decimals parsed from requests
+const precision = 128
+
+// AddSymbol adds a symbol which may be traded on the server, with the given base and quote assets
+func (s *Server) AddSymbol(symbol string, baseAsset string, quoteAsset string) {
+	s.exchange.AddSymbol(symbol, baseAsset, quoteAsset)
+}
+
+// SetBalance sets the free balance of an asset, leaving any locked balance unchanged
+func (s *Server) SetBalance(asset string, free *big.Float) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exchange.SetBalance(asset, free)
+	s.updateTime = s.now()
+	s.publishAccountPosition(s.updateTime, s.exchange.Balance(asset))
+}
+
+// Balance returns the balance of an asset
+func (s *Server) Balance(asset string) gobinance.Balance {
+	return s.exchange.Balance(asset)
+}
+
+// SetPrice sets the market price of a symbol.  Market orders are filled at this price, as are limit orders which
+// cross it when placed.  Open limit orders which the new price crosses are filled at their limit price.
+func (s *Server) SetPrice(symbol string, price *big.Float) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exchange.Trade(symbol, price, nil, s.now())
+}
+
+// Trade publishes a trade by another market participant on the symbol's trade stream, then sets the market price
+// to the price of the trade as SetPrice does, except that open orders are only filled up to the trade's quantity
+func (s *Server) Trade(symbol string, price *big.Float, qty *big.Float) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publishTrade(symbol, s.exchange.NewTradeID(), price, qty, nil)
+	s.exchange.Trade(symbol, price, qty, s.now())
+}
+
+// Order returns the order with the given ID, or false if there is no such order
+func (s *Server) Order(orderID int64) (gobinance.SpotOrder, bool) {
+	return s.exchange.Order(orderID)
+}
+
+// OpenOrders returns all open orders, ordered by order ID
+func (s *Server) OpenOrders() []gobinance.SpotOrder {
+	return s.exchange.OpenOrders("")
+}
+
+// handleExecution publishes the events of an execution of an order.  It is called by the exchange, which is only
+// used with s.mu held.
+func (s *Server) handleExecution(x gobinance.SimulatedExecution) {
+	s.publishExecutionReport(x)
+	s.publishAccountPosition(x.Time, x.Balances...)
+	if x.Fill != nil {
+		s.publishTrade(x.Order.Symbol, x.Fill.TradeID, x.Fill.Price, x.Fill.Qty, &x.Order)
+	}
+}
+
+// exchangeError converts an error returned by the exchange into an error response
+func exchangeError(err error) *apiError {
+	var httpErr *gobinance.HttpError
+	if errors.As(err, &httpErr) {
+		return newAPIError(httpErr.StatusCode(), httpErr.ErrorCode(), httpErr.Msg)
+	}
+	return newAPIError(http.StatusInternalServerError, -1000, err.Error())
+}
+
+// validateOrder checks that the parameters required by the order's type have been sent
+func validateOrder(req gobinance.SimulatedOrderRequest) *apiError {
+	if req.Side != gobinance.OrderSideBuy && req.Side != gobinance.OrderSideSell {
+		return mandatoryParam("side")
+	}
+	switch req.Type {
+	case gobinance.OrderTypeLimit, gobinance.OrderTypeLimitMaker, gobinance.OrderTypeStopLossLimit, gobinance.OrderTypeTakeProfitLimit:
+		if req.Price == nil || req.Price.Sign() <= 0 {
+			return mandatoryParam("price")
+		}
+		if req.Quantity == nil || req.Quantity.Sign() <= 0 {
+			return mandatoryParam("quantity")
+		}
+		if req.Type != gobinance.OrderTypeLimitMaker && req.TimeInForce == "" {
+			return mandatoryParam("timeInForce")
+		}
+		if (req.Type == gobinance.OrderTypeStopLossLimit || req.Type == gobinance.OrderTypeTakeProfitLimit) && (req.StopPrice == nil || req.StopPrice.Sign() <= 0) {
+			return mandatoryParam("stopPrice")
+		}
+	case gobinance.OrderTypeMarket:
+		if (req.Quantity == nil || req.Quantity.Sign() <= 0) && (req.QuoteOrderQty == nil || req.QuoteOrderQty.Sign() <= 0) {
+			return mandatoryParam("quantity")
+		}
+	default:
+		return newAPIError(http.StatusBadRequest, -1116, "Invalid orderType.")
+	}
+	return nil
+}
+
+// findOrder finds an order by the orderId or origClientOrderId parameter, returning false if there is no such order
+func (s *Server) findOrder(params url.Values) (gobinance.SpotOrder, bool, *apiError) {
+	symbol := params.Get("symbol")
+	if symbol == "" {
+		return gobinance.SpotOrder{}, false, mandatoryParam("symbol")
+	}
+	var orderID int64
+	if v := params.Get("orderId"); v != "" {
+		var err error
+		if orderID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return gobinance.SpotOrder{}, false, mandatoryParam("orderId")
+		}
+	}
+	clientOrderID := params.Get("origClientOrderId")
+	if orderID == 0 && clientOrderID == "" {
+		return gobinance.SpotOrder{}, false, newAPIError(http.StatusBadRequest, -1102, "Param 'origClientOrderId' or 'orderId' must be sent, but both were empty/null!")
+	}
+	// the most recent order with the client order ID is found, as IDs may be reused once orders are closed
+	o, err := s.exchange.Query(symbol, orderID, clientOrderID)
+	if err != nil {
+		return gobinance.SpotOrder{}, false, nil
+	}
+	return o, true, nil
+}
+
+func (s *Server) handleAccount(params url.Values) (interface{}, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info := s.exchange.AccountInformation()
+	assets := make([]string, 0, len(info.Balances))
+	for asset := range info.Balances {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	balances := make([]balanceDTO, 0, len(assets))
+	for _, asset := range assets {
+		b := info.Balances[asset]
+		balances = append(balances, balanceDTO{Asset: asset, Free: decimal(b.Free), Locked: decimal(b.Locked)})
+	}
+	updateTime := s.updateTime
+	if info.UpdateTime.After(updateTime) {
+		updateTime = info.UpdateTime
+	}
+	return accountDTO{
+		MakerCommission: info.MakerCommission,
+		TakerCommission: info.TakerCommission,
+		CanTrade:        true,
+		CanWithdraw:     true,
+		CanDeposit:      true,
+		UpdateTime:      millis(updateTime),
+		AccountType:     "SPOT",
+		Balances:        balances,
+		Permissions:     []string{"SPOT"},
+	}, nil
+}
+
+func (s *Server) handlePlaceOrder(params url.Values) (interface{}, *apiError) {
+	req := gobinance.SimulatedOrderRequest{
+		Symbol:        params.Get("symbol"),
+		ClientOrderID: params.Get("newClientOrderId"),
+		Side:          gobinance.OrderSide(params.Get("side")),
+		Type:          gobinance.OrderType(params.Get("type")),
+		TimeInForce:   gobinance.TimeInForce(params.Get("timeInForce")),
+	}
+	for _, p := range []struct {
+		name string
+		dst  **big.Float
+	}{
+		{name: "price", dst: &req.Price},
+		{name: "quantity", dst: &req.Quantity},
+		{name: "quoteOrderQty", dst: &req.QuoteOrderQty},
+		{name: "stopPrice", dst: &req.StopPrice},
+	} {
+		if v := params.Get(p.name); v != "" {
+			f, ok := parseDecimal(v)
+			if !ok {
+				return nil, mandatoryParam(p.name)
+			}
+			*p.dst = f
+		}
+	}
+	if apiErr := validateOrder(req); apiErr != nil {
+		return nil, apiErr
+	}
+	if req.ClientOrderID == "" {
+		req.ClientOrderID = randomClientOrderID()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, err := s.exchange.Place(req, s.now())
+	if err != nil {
+		return nil, exchangeError(err)
+	}
+	ack := orderAckDTO{
+		Symbol:        result.Symbol,
+		OrderID:       int64(result.OrderID),
+		OrderListID:   -1,
+		ClientOrderID: result.ClientOrderID,
+		TransactTime:  millis(result.TransactTime),
+	}
+	responseType := gobinance.OrderResponseType(params.Get("newOrderRespType"))
+	if responseType == gobinance.OrderResponseTypeAck {
+		return ack, nil
+	}
+	dto := orderResultDTO{
+		orderAckDTO:         ack,
+		Price:               decimal(result.Price),
+		OrigQty:             decimal(result.OrigQty),
+		ExecutedQty:         decimal(result.ExecutedQty),
+		CummulativeQuoteQty: decimal(result.CumulativeQuoteQty),
+		Status:              result.Status,
+		TimeInForce:         result.TimeInForce,
+		Type:                result.Type,
+		Side:                result.Side,
+	}
+	if responseType == gobinance.OrderResponseTypeResult {
+		return dto, nil
+	}
+	full := orderFullDTO{orderResultDTO: dto, Fills: []fillDTO{}}
+	for _, f := range result.Fills {
+		full.Fills = append(full.Fills, fillDTO{
+			Price:           decimal(f.Price),
+			Qty:             decimal(f.Qty),
+			Commission:      decimal(f.Commission),
+			CommissionAsset: f.CommissionAsset,
+			TradeID:         f.TradeID,
+		})
+	}
+	return full, nil
+}
+
+func (s *Server) handleQueryOrder(params url.Values) (interface{}, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok, apiErr := s.findOrder(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if !ok {
+		return nil, newAPIError(http.StatusBadRequest, -2013, "Order does not exist.")
+	}
+	return newOrderDTO(o), nil
+}
+
+func (s *Server) handleCancelOrder(params url.Values) (interface{}, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok, apiErr := s.findOrder(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if !ok {
+		return nil, newAPIError(http.StatusBadRequest, -2011, "Unknown order sent.")
+	}
+	newClientOrderID := params.Get("newClientOrderId")
+	if newClientOrderID == "" {
+		newClientOrderID = randomClientOrderID()
+	}
+	result, err := s.exchange.Cancel(gobinance.SimulatedCancelRequest{
+		Symbol:           o.Symbol,
+		OrderID:          o.OrderID,
+		NewClientOrderID: newClientOrderID,
+	}, s.now())
+	if err != nil {
+		return nil, exchangeError(err)
+	}
+	return cancelDTO{
+		Symbol:              result.Symbol,
+		OrigClientOrderID:   result.OriginalClientOrderID,
+		OrderID:             result.OrderID,
+		OrderListID:         -1,
+		ClientOrderID:       result.ClientOrderID,
+		Price:               decimal(result.Price),
+		OrigQty:             decimal(result.OriginalQty),
+		ExecutedQty:         decimal(result.ExecutedQty),
+		CummulativeQuoteQty: decimal(result.CumulativeQuoteQty),
+		Status:              result.Status,
+		TimeInForce:         result.TimeInForce,
+		Type:                result.Type,
+		Side:                result.Side,
+	}, nil
+}
+
+func (s *Server) handleOpenOrders(params url.Values) (interface{}, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	symbol := params.Get("symbol")
+	out := []orderDTO{}
+	for _, o := range s.exchange.OpenOrders(symbol) {
+		out = append(out, newOrderDTO(o))
+	}
+	return out, nil
+}
+
+func newOrderDTO(o gobinance.SpotOrder) orderDTO {
+	return orderDTO{
+		Symbol:              o.Symbol,
+		OrderID:             o.OrderID,
+		OrderListID:         -1,
+		ClientOrderID:       o.ClientOrderID,
+		Price:               decimal(o.Price),
+		OrigQty:             decimal(o.OriginalQty),
+		ExecutedQty:         decimal(o.ExecutedQty),
+		CummulativeQuoteQty: decimal(o.CumulativeQuoteQty),
+		Status:              o.Status,
+		TimeInForce:         o.TimeInForce,
+		Type:                o.Type,
+		Side:                o.Side,
+		StopPrice:           decimal(o.StopPrice),
+		IcebergQty:          decimal(o.IcebergQty),
+		Time:                millis(o.Time),
+		UpdateTime:          millis(o.UpdateTime),
+		IsWorking:           o.IsWorking,
+		OrigQuoteOrderQty:   decimal(o.OriginalQuoteOrderQty),
+	}
+}
+
+func mandatoryParam(name string) *apiError {
+	return newAPIError(http.StatusBadRequest, -1102, fmt.Sprintf("Mandatory parameter '%v' was not sent, was empty/null, or malformed.", name))
+}
+
+func randomClientOrderID() string {
+	bs := make([]byte, 11)
+	_, _ = rand.Read(bs)
+	return "binancetest" + hex.EncodeToString(bs)
+}
+
+func parseDecimal(s string) (*big.Float, bool) {
+	f, _, err := big.ParseFloat(s, 10, precision, big.ToNearestEven)
+	if err != nil || f.Sign() < 0 {
+		return nil, false
+	}
+	return f, true
+}
+
+// decimal formats f with 8 decimal places, as binance does.  A nil value is formatted as zero.
+func decimal(f *big.Float) string {
+	if f == nil {
+		f = new(big.Float)
+	}
+	return f.Text('f', 8)
+}
+
+func millis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
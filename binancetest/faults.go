@@ -0,0 +1,96 @@
+package binancetest
+
+import (
+	"net/http"
+	"time"
+)
+
+// Fault describes a failure injected into a request using Server.InjectFault
+type Fault struct {
+	// Status is the HTTP status of the error response.  When zero, the normal response is returned after Delay.
+	Status int
+	// Code is the binance error code of the error response
+	Code int
+	// Message is the message of the error response
+	Message string
+	// Delay is how long the server waits before responding.  A Delay longer than the client's timeout simulates a
+	// request timing out.
+	Delay time.Duration
+	// AfterProcessing causes the request to be processed before the fault is applied, simulating a response lost
+	// after binance has acted on the request, such as an order being placed.
+	AfterProcessing bool
+}
+
+var (
+	// FaultRateLimited rejects a request as exceeding binance's rate limits
+	FaultRateLimited = Fault{
+		Status:  http.StatusTooManyRequests,
+		Code:    -1003,
+		Message: "Too many requests; current limit is 1200 request weight per 1 MINUTE.",
+	}
+	// FaultInsufficientBalance rejects an order as if the account had insufficient balance
+	FaultInsufficientBalance = Fault{
+		Status:  http.StatusBadRequest,
+		Code:    -2010,
+		Message: "Account has insufficient balance for requested action.",
+	}
+	// FaultInternalError fails a request with an unknown error, leaving its outcome ambiguous
+	FaultInternalError = Fault{
+		Status:  http.StatusInternalServerError,
+		Code:    -1000,
+		Message: "An unknown error occurred while processing the request.",
+	}
+)
+
+// FaultTimeout delays the response to a request by d.  When used with a client whose timeout is shorter than d, the
+// request times out.
+func FaultTimeout(d time.Duration) Fault {
+	return Fault{Delay: d}
+}
+
+type queuedFault struct {
+	method string
+	path   string
+	fault  Fault
+}
+
+// InjectFault applies fault to the next request made using method to path, such as "/api/v3/order".  Faults
+// injected for the same method and path are applied to successive requests in the order they were injected.
+func (s *Server) InjectFault(method string, path string, fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, queuedFault{method: method, path: path, fault: fault})
+}
+
+func (s *Server) takeFault(method string, path string) (Fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, f := range s.faults {
+		if f.method == method && f.path == path {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			return f.fault, true
+		}
+	}
+	return Fault{}, false
+}
+
+// wait waits for the fault's delay, returning false if the client gave up or the server closed first
+func (f Fault) wait(r *http.Request, closed <-chan struct{}) bool {
+	if f.Delay <= 0 {
+		return true
+	}
+	t := time.NewTimer(f.Delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	case <-closed:
+		return false
+	}
+}
+
+func (f Fault) apiError() *apiError {
+	return newAPIError(f.Status, f.Code, f.Message)
+}
@@ -0,0 +1,360 @@
+// Package binancetest provides an in-process fake of binance's spot REST API and websocket streams, so that code
+// using gobinance can be tested end-to-end without connecting to binance.
+//
+// The fake holds a single account with balances set by the test, verifies the API key, HMAC signature and
+// timestamp of each request as binance does, and fills orders against prices set by the test using gobinance's
+// SimulatedExchange, as PaperTrader and Backtest do.  Faults such as rate limiting and slow responses may be injected into individual requests.
+package binancetest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/beyondallrepair/gobinance"
+	"github.com/gorilla/websocket"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAPIKey is the API key accepted by a Server created without the ServerCredentials option
+	DefaultAPIKey = "binancetest-api-key"
+	// DefaultSecretKey is the secret key accepted by a Server created without the ServerCredentials option
+	DefaultSecretKey = "binancetest-secret-key"
+
+	defaultRecvWindow = 5000
+	maxRecvWindow     = 60000
+	// maxClockSkew is how far in the future a request's timestamp may be
+	maxClockSkew = time.Second
+
+	apiKeyHeader    = "X-MBX-APIKEY"
+	signatureParam  = "signature"
+	timestampParam  = "timestamp"
+	recvWindowParam = "recvWindow"
+)
+
+// Server is a fake binance spot server.  Servers are created using NewServer, and must be closed using Close.
+type Server struct {
+	// URL is the base URL of the REST API, for use as an Environment's HTTPApiURL
+	URL string
+	// WebsocketURL is the base URL of the websocket streams, for use as an Environment's WebsocketApiURL
+	WebsocketURL string
+
+	apiKey    string
+	secretKey string
+	now       func() time.Time
+	server    *httptest.Server
+	upgrader  websocket.Upgrader
+	routes    map[string]endpoint
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	// exchange holds the account and fills its orders.  It is only used with mu held, as its execution handler
+	// publishes to the subscribers guarded by mu.
+	exchange *gobinance.SimulatedExchange
+
+	mu          sync.Mutex
+	updateTime  time.Time
+	listenKeys  map[string]bool
+	faults      []queuedFault
+	subscribers map[string]map[*subscriber]struct{}
+}
+
+// ServerOption is a function that applies optional configuration to a Server created using NewServer
+type ServerOption func(s *Server)
+
+// ServerCredentials sets the API key and HMAC secret key accepted by the server.  The defaults are DefaultAPIKey and
+// DefaultSecretKey.
+func ServerCredentials(apiKey string, secretKey string) ServerOption {
+	return func(s *Server) {
+		s.apiKey = apiKey
+		s.secretKey = secretKey
+	}
+}
+
+// ServerNow sets the function used by the server to get the current time, which is used to check the timestamps of
+// signed requests and to timestamp orders and events.  The default is time.Now.
+func ServerNow(now func() time.Time) ServerOption {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// ServerSymbol adds a symbol which may be traded on the server.  See Server.AddSymbol.
+func ServerSymbol(symbol string, baseAsset string, quoteAsset string) ServerOption {
+	return func(s *Server) {
+		s.exchange.AddSymbol(symbol, baseAsset, quoteAsset)
+	}
+}
+
+// ServerBalance sets the free balance of an asset.  See Server.SetBalance.
+func ServerBalance(asset string, free *big.Float) ServerOption {
+	return func(s *Server) {
+		s.exchange.SetBalance(asset, free)
+	}
+}
+
+// NewServer starts a fake binance server listening on a local port
+func NewServer(opts ...ServerOption) *Server {
+	s := &Server{
+		apiKey:      DefaultAPIKey,
+		secretKey:   DefaultSecretKey,
+		now:         time.Now,
+		exchange:    gobinance.NewSimulatedExchange(),
+		listenKeys:  make(map[string]bool),
+		subscribers: make(map[string]map[*subscriber]struct{}),
+		closed:      make(chan struct{}),
+	}
+	// binance's default commission is not charged, so that tests need not account for it
+	s.exchange.SetFees(new(big.Float), new(big.Float))
+	s.exchange.SetExecutionHandler(s.handleExecution)
+	for _, o := range opts {
+		o(s)
+	}
+	s.updateTime = s.now()
+	s.routes = map[string]endpoint{
+		"GET /api/v3/account":           {security: securitySigned, handle: s.handleAccount},
+		"POST /api/v3/order":            {security: securitySigned, handle: s.handlePlaceOrder},
+		"GET /api/v3/order":             {security: securitySigned, handle: s.handleQueryOrder},
+		"DELETE /api/v3/order":          {security: securitySigned, handle: s.handleCancelOrder},
+		"GET /api/v3/openOrders":        {security: securitySigned, handle: s.handleOpenOrders},
+		"POST /api/v3/userDataStream":   {security: securityAPIKey, handle: s.handleStartUserDataStream},
+		"PUT /api/v3/userDataStream":    {security: securityAPIKey, handle: s.handleKeepAliveUserDataStream},
+		"DELETE /api/v3/userDataStream": {security: securityAPIKey, handle: s.handleCloseUserDataStream},
+	}
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	s.WebsocketURL = "ws" + strings.TrimPrefix(s.server.URL, "http")
+	return s
+}
+
+// Close closes all websocket connections and shuts down the server
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+	s.mu.Lock()
+	for _, subs := range s.subscribers {
+		for sub := range subs {
+			sub.close()
+		}
+	}
+	s.subscribers = make(map[string]map[*subscriber]struct{})
+	s.mu.Unlock()
+	s.server.Close()
+}
+
+// Environment returns an Environment which directs clients to the server
+func (s *Server) Environment() gobinance.Environment {
+	httpURL, _ := url.Parse(s.URL)
+	wsURL, _ := url.Parse(s.WebsocketURL)
+	return gobinance.Environment{HTTPApiURL: httpURL, WebsocketApiURL: wsURL}
+}
+
+// NewClient creates a gobinance.Client which connects to the server using the server's credentials.  Options are
+// applied after those, so may override them.
+func (s *Server) NewClient(opts ...gobinance.ClientOption) (*gobinance.Client, error) {
+	opts = append([]gobinance.ClientOption{
+		gobinance.ClientEnvironment(s.Environment()),
+		gobinance.ClientCredentials(s.apiKey, s.secretKey),
+	}, opts...)
+	return gobinance.NewClient(opts...)
+}
+
+type security int
+
+const (
+	securityNone security = iota
+	securityAPIKey
+	securitySigned
+)
+
+// endpoint handles requests to a REST endpoint, returning the value to encode as the JSON response
+type endpoint struct {
+	security security
+	handle   func(params url.Values) (interface{}, *apiError)
+}
+
+// apiError is an error response in the format returned by binance
+type apiError struct {
+	status int
+	code   int
+	msg    string
+}
+
+func newAPIError(status int, code int, msg string) *apiError {
+	return &apiError{status: status, code: code, msg: msg}
+}
+
+// ServeHTTP handles requests to the REST API and websocket streams
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/ws/") {
+		s.serveWebsocket(w, r)
+		return
+	}
+	e, ok := s.routes[r.Method+" "+r.URL.Path]
+	if !ok {
+		writeError(w, newAPIError(http.StatusNotFound, -1, "Unknown endpoint."))
+		return
+	}
+
+	fault, hasFault := s.takeFault(r.Method, r.URL.Path)
+	if hasFault && !fault.AfterProcessing {
+		if !fault.wait(r, s.closed) {
+			return
+		}
+		if fault.Status != 0 {
+			writeError(w, fault.apiError())
+			return
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	s.serveEndpoint(rec, r, e)
+
+	if hasFault && fault.AfterProcessing {
+		if !fault.wait(r, s.closed) {
+			return
+		}
+		if fault.Status != 0 {
+			writeError(w, fault.apiError())
+			return
+		}
+	}
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.Code)
+	_, _ = w.Write(rec.Body.Bytes())
+}
+
+func (s *Server) serveEndpoint(w http.ResponseWriter, r *http.Request, e endpoint) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, newAPIError(http.StatusBadRequest, -1, "Unable to read request body."))
+		return
+	}
+	rawBody := ""
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		rawBody = string(body)
+	}
+	params, apiErr := parseParams(r.URL.RawQuery, rawBody)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+
+	if e.security >= securityAPIKey {
+		if apiErr := s.checkAPIKey(r.Header.Get(apiKeyHeader)); apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+	}
+	if e.security >= securitySigned {
+		if apiErr := s.checkSignature(r.URL.RawQuery, rawBody, params); apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+	}
+
+	out, apiErr := e.handle(params)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// parseParams combines the parameters in the query string and body, rejecting parameters sent in both
+func parseParams(rawQuery string, rawBody string) (url.Values, *apiError) {
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, newAPIError(http.StatusBadRequest, -1100, "Illegal characters found in a parameter.")
+	}
+	bodyParams, err := url.ParseQuery(rawBody)
+	if err != nil {
+		return nil, newAPIError(http.StatusBadRequest, -1100, "Illegal characters found in a parameter.")
+	}
+	for k, v := range bodyParams {
+		if _, ok := params[k]; ok {
+			return nil, newAPIError(http.StatusBadRequest, -1101, "Duplicate values for parameter '"+k+"'.")
+		}
+		params[k] = v
+	}
+	return params, nil
+}
+
+func (s *Server) checkAPIKey(apiKey string) *apiError {
+	if apiKey == "" {
+		return newAPIError(http.StatusUnauthorized, -2014, "API-key format invalid.")
+	}
+	if apiKey != s.apiKey {
+		return newAPIError(http.StatusUnauthorized, -2015, "Invalid API-key, IP, or permissions for action.")
+	}
+	return nil
+}
+
+// checkSignature verifies the signature and timestamp of a signed request.  As documented by binance, the signature
+// is calculated from the query string followed immediately by the body, each excluding the signature itself.
+func (s *Server) checkSignature(rawQuery string, rawBody string, params url.Values) *apiError {
+	signature := params.Get(signatureParam)
+	if signature == "" {
+		return newAPIError(http.StatusBadRequest, -1102, "Mandatory parameter 'signature' was not sent, was empty/null, or malformed.")
+	}
+	mac := hmac.New(sha256.New, []byte(s.secretKey))
+	_, _ = mac.Write([]byte(withoutSignature(rawQuery) + withoutSignature(rawBody)))
+	if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(strings.ToLower(signature))) {
+		return newAPIError(http.StatusBadRequest, -1022, "Signature for this request is not valid.")
+	}
+
+	timestamp, err := strconv.ParseInt(params.Get(timestampParam), 10, 64)
+	if err != nil {
+		return newAPIError(http.StatusBadRequest, -1102, "Mandatory parameter 'timestamp' was not sent, was empty/null, or malformed.")
+	}
+	recvWindow := int64(defaultRecvWindow)
+	if v := params.Get(recvWindowParam); v != "" {
+		if recvWindow, err = strconv.ParseInt(v, 10, 64); err != nil || recvWindow <= 0 || recvWindow > maxRecvWindow {
+			return newAPIError(http.StatusBadRequest, -1131, "recvWindow must be less than 60000")
+		}
+	}
+	now := s.now().UnixNano() / int64(time.Millisecond)
+	if timestamp > now+maxClockSkew.Milliseconds() || now-timestamp > recvWindow {
+		return newAPIError(http.StatusBadRequest, -1021, "Timestamp for this request is outside of the recvWindow.")
+	}
+	return nil
+}
+
+// withoutSignature removes the signature parameter from a raw query string or body, preserving the order of the
+// remaining parameters
+func withoutSignature(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	parts := strings.Split(raw, "&")
+	out := parts[:0]
+	for _, p := range parts {
+		if !strings.HasPrefix(p, signatureParam+"=") {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, "&")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err *apiError) {
+	writeJSON(w, err.status, struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}{Code: err.code, Msg: err.msg})
+}
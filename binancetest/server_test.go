@@ -0,0 +1,343 @@
+package binancetest_test
+
+import (
+	"context"
+	"errors"
+	"github.com/beyondallrepair/gobinance"
+	"github.com/beyondallrepair/gobinance/binancetest"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, opts ...binancetest.ServerOption) *binancetest.Server {
+	opts = append([]binancetest.ServerOption{
+		binancetest.ServerSymbol("BTCUSDT", "BTC", "USDT"),
+		binancetest.ServerBalance("USDT", big.NewFloat(1000)),
+		binancetest.ServerBalance("BTC", big.NewFloat(1)),
+	}, opts...)
+	s := binancetest.NewServer(opts...)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestClient(t *testing.T, s *binancetest.Server, opts ...gobinance.ClientOption) *gobinance.Client {
+	c, err := s.NewClient(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	return c
+}
+
+func expectBalance(t *testing.T, s *binancetest.Server, asset string, free float64, locked float64) {
+	t.Helper()
+	b := s.Balance(asset)
+	if b.Free.Cmp(big.NewFloat(free)) != 0 || b.Locked.Cmp(big.NewFloat(locked)) != 0 {
+		t.Errorf("unexpected %v balance. expected %v free and %v locked but got %v and %v", asset, free, locked, b.Free, b.Locked)
+	}
+}
+
+func expectHTTPError(t *testing.T, err error, status int, code int) {
+	t.Helper()
+	var httpErr *gobinance.HttpError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected an HttpError but got %v", err)
+	}
+	if httpErr.StatusCode() != status || httpErr.ErrorCode() != code {
+		t.Errorf("expected status %v and code %v but got %v", status, code, httpErr)
+	}
+}
+
+func TestServer_AccountInformation(t *testing.T) {
+	t.Parallel()
+	s := newTestServer(t)
+	c := newTestClient(t, s)
+
+	got, err := c.AccountInformation(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.CanTrade || len(got.Balances) != 2 || got.Balances["USDT"].Free.Cmp(big.NewFloat(1000)) != 0 {
+		t.Errorf("unexpected account information %#v", got)
+	}
+}
+
+func TestServer_Authentication(t *testing.T) {
+	t.Parallel()
+	s := newTestServer(t)
+	testCases := []struct {
+		name         string
+		options      []gobinance.ClientOption
+		expectedCode int
+	}{
+		{
+			name:         "invalid signature",
+			options:      []gobinance.ClientOption{gobinance.ClientCredentials(binancetest.DefaultAPIKey, "wrong")},
+			expectedCode: -1022,
+		},
+		{
+			name:         "invalid api key",
+			options:      []gobinance.ClientOption{gobinance.ClientCredentials("wrong", binancetest.DefaultSecretKey)},
+			expectedCode: -2015,
+		},
+		{
+			name: "timestamp outside recv window",
+			options: []gobinance.ClientOption{gobinance.ClientNow(func() time.Time {
+				return time.Now().Add(-time.Minute)
+			})},
+			expectedCode: -1021,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			c := newTestClient(t, s, tc.options...)
+			_, err := c.AccountInformation(context.Background())
+			status := http.StatusBadRequest
+			if tc.expectedCode == -2015 {
+				status = http.StatusUnauthorized
+			}
+			expectHTTPError(t, err, status, tc.expectedCode)
+		})
+	}
+}
+
+func TestServer_LimitOrder(t *testing.T) {
+	t.Parallel()
+	s := newTestServer(t)
+	c := newTestClient(t, s)
+	ctx := context.Background()
+
+	result, err := c.PlaceLimitOrder(ctx, "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(2), big.NewFloat(100), gobinance.TimeInForceGoodTilCanceled, gobinance.SpotClientOrderID("buy-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != gobinance.OrderStatusNew || result.ClientOrderID != "buy-1" || len(result.Fills) != 0 {
+		t.Errorf("unexpected result %#v", result)
+	}
+	expectBalance(t, s, "USDT", 800, 200)
+
+	open, err := c.AllOpenSpotOrders(ctx)
+	if err != nil || len(open) != 1 || open[0].OrderID != int64(result.OrderID) {
+		t.Fatalf("unexpected open orders %#v, %v", open, err)
+	}
+
+	// the price falling to the limit fills the order at its limit price
+	s.SetPrice("BTCUSDT", big.NewFloat(99))
+	order, err := c.QueryOrderByClientID(ctx, "BTCUSDT", "buy-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.Status != gobinance.OrderStatusFilled || order.ExecutedQty.Cmp(big.NewFloat(2)) != 0 || order.CumulativeQuoteQty.Cmp(big.NewFloat(200)) != 0 {
+		t.Errorf("unexpected order %#v", order)
+	}
+	expectBalance(t, s, "USDT", 800, 0)
+	expectBalance(t, s, "BTC", 3, 0)
+
+	// a limit order crossing the market price fills immediately at the market price
+	result, err = c.PlaceLimitOrder(ctx, "BTCUSDT", gobinance.OrderSideSell, big.NewFloat(1), big.NewFloat(90), gobinance.TimeInForceGoodTilCanceled)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != gobinance.OrderStatusFilled || len(result.Fills) != 1 || result.Fills[0].Price.Cmp(big.NewFloat(99)) != 0 {
+		t.Errorf("unexpected result %#v", result)
+	}
+	expectBalance(t, s, "USDT", 899, 0)
+	expectBalance(t, s, "BTC", 2, 0)
+
+	// limit maker orders which would take are rejected
+	_, err = c.PlaceLimitMakerOrder(ctx, "BTCUSDT", gobinance.OrderSideSell, big.NewFloat(1), big.NewFloat(90))
+	expectHTTPError(t, err, http.StatusBadRequest, -2010)
+}
+
+func TestServer_MarketOrder(t *testing.T) {
+	t.Parallel()
+	s := newTestServer(t)
+	c := newTestClient(t, s)
+	ctx := context.Background()
+
+	_, err := c.PlaceSpotMarketOrder(ctx, "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(1), gobinance.QuantityAssetBase)
+	expectHTTPError(t, err, http.StatusBadRequest, -2010)
+
+	s.SetPrice("BTCUSDT", big.NewFloat(250))
+	result, err := c.PlaceSpotMarketOrder(ctx, "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(500), gobinance.QuantityAssetQuote)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != gobinance.OrderStatusFilled || result.ExecutedQty.Cmp(big.NewFloat(2)) != 0 || len(result.Fills) != 1 {
+		t.Errorf("unexpected result %#v", result)
+	}
+	expectBalance(t, s, "USDT", 500, 0)
+	expectBalance(t, s, "BTC", 3, 0)
+
+	_, err = c.PlaceSpotMarketOrder(ctx, "BTCUSDT", gobinance.OrderSideSell, big.NewFloat(4), gobinance.QuantityAssetBase)
+	expectHTTPError(t, err, http.StatusBadRequest, -2010)
+
+	_, err = c.PlaceSpotMarketOrder(ctx, "ETHUSDT", gobinance.OrderSideSell, big.NewFloat(1), gobinance.QuantityAssetBase)
+	expectHTTPError(t, err, http.StatusBadRequest, -1121)
+}
+
+func TestServer_CancelOrder(t *testing.T) {
+	t.Parallel()
+	s := newTestServer(t)
+	c := newTestClient(t, s)
+	ctx := context.Background()
+
+	result, err := c.PlaceLimitOrder(ctx, "BTCUSDT", gobinance.OrderSideSell, big.NewFloat(0.5), big.NewFloat(100), gobinance.TimeInForceGoodTilCanceled)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectBalance(t, s, "BTC", 0.5, 0.5)
+
+	cancelled, err := c.CancelOrderByOrderID(ctx, "BTCUSDT", int64(result.OrderID))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cancelled.Status != gobinance.OrderStatusCanceled || cancelled.OriginalClientOrderID != result.ClientOrderID {
+		t.Errorf("unexpected result %#v", cancelled)
+	}
+	expectBalance(t, s, "BTC", 1, 0)
+
+	_, err = c.CancelOrderByOrderID(ctx, "BTCUSDT", int64(result.OrderID))
+	expectHTTPError(t, err, http.StatusBadRequest, -2011)
+	_, err = c.QueryOrderByID(ctx, "BTCUSDT", 1234)
+	expectHTTPError(t, err, http.StatusBadRequest, -2013)
+}
+
+func TestServer_SignedRequestEncoding(t *testing.T) {
+	t.Parallel()
+	s := newTestServer(t)
+	c := newTestClient(t, s, gobinance.ClientSignedRequestEncoding(gobinance.SignedRequestEncoding{
+		Body:            true,
+		QueryParameters: []string{"symbol"},
+	}))
+
+	result, err := c.PlaceLimitOrder(context.Background(), "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(1), big.NewFloat(10), gobinance.TimeInForceGoodTilCanceled)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := s.Order(int64(result.OrderID)); !ok {
+		t.Errorf("expected order %v to exist", result.OrderID)
+	}
+}
+
+func TestServer_InjectFault(t *testing.T) {
+	t.Parallel()
+	t.Run("rate limited", func(t *testing.T) {
+		t.Parallel()
+		s := newTestServer(t)
+		c := newTestClient(t, s)
+		s.InjectFault(http.MethodGet, "/api/v3/account", binancetest.FaultRateLimited)
+
+		_, err := c.AccountInformation(context.Background())
+		expectHTTPError(t, err, http.StatusTooManyRequests, -1003)
+		if _, err := c.AccountInformation(context.Background()); err != nil {
+			t.Errorf("expected the fault to apply to a single request but got %v", err)
+		}
+	})
+	t.Run("insufficient balance", func(t *testing.T) {
+		t.Parallel()
+		s := newTestServer(t)
+		c := newTestClient(t, s)
+		s.InjectFault(http.MethodPost, "/api/v3/order", binancetest.FaultInsufficientBalance)
+
+		_, err := c.PlaceLimitOrder(context.Background(), "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(1), big.NewFloat(10), gobinance.TimeInForceGoodTilCanceled)
+		expectHTTPError(t, err, http.StatusBadRequest, -2010)
+		if open := s.OpenOrders(); len(open) != 0 {
+			t.Errorf("expected no orders to be placed but got %#v", open)
+		}
+	})
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		s := newTestServer(t)
+		c := newTestClient(t, s, gobinance.ClientDoer(&http.Client{Timeout: 20 * time.Millisecond}))
+		s.InjectFault(http.MethodGet, "/api/v3/account", binancetest.FaultTimeout(time.Minute))
+
+		if _, err := c.AccountInformation(context.Background()); err == nil {
+			t.Errorf("expected the request to time out")
+		}
+	})
+	t.Run("ambiguous failure resolved by idempotent order", func(t *testing.T) {
+		t.Parallel()
+		s := newTestServer(t)
+		c := newTestClient(t, s)
+		fault := binancetest.FaultInternalError
+		fault.AfterProcessing = true
+		s.InjectFault(http.MethodPost, "/api/v3/order", fault)
+
+		result, err := c.PlaceLimitOrder(context.Background(), "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(1), big.NewFloat(10), gobinance.TimeInForceGoodTilCanceled,
+			gobinance.SpotIdempotent(), gobinance.SpotOrderRecvWindow(10*time.Millisecond))
+		if err != nil {
+			t.Fatalf("expected the order to be resolved but got %v", err)
+		}
+		if open := s.OpenOrders(); len(open) != 1 || open[0].OrderID != int64(result.OrderID) {
+			t.Errorf("expected a single order to be placed but got %#v", open)
+		}
+	})
+}
+
+func TestServer_Streams(t *testing.T) {
+	t.Parallel()
+	s := newTestServer(t)
+	c := newTestClient(t, s)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	trades := c.Trades(ctx, "BTCUSDT")
+	userData := c.UserData(ctx)
+	// wait for both streams to connect by publishing until events arrive
+	var trade gobinance.TradeEventOrError
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+waitForTrades:
+	for {
+		select {
+		case trade = <-trades:
+			break waitForTrades
+		case <-ticker.C:
+			s.Trade("BTCUSDT", big.NewFloat(100), big.NewFloat(0.1))
+		}
+	}
+	if trade.Err != nil || trade.Symbol != "BTCUSDT" || trade.Price.Cmp(big.NewFloat(100)) != 0 {
+		t.Errorf("unexpected trade %#v", trade)
+	}
+waitForUserData:
+	for {
+		select {
+		case event := <-userData:
+			if event.Err != nil {
+				t.Fatalf("unexpected error: %v", event.Err)
+			}
+			if event.AccountPosition != nil {
+				break waitForUserData
+			}
+		case <-ticker.C:
+			s.SetBalance("USDT", big.NewFloat(1000))
+		}
+	}
+
+	result, err := c.PlaceLimitOrder(ctx, "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(1), big.NewFloat(100), gobinance.TimeInForceGoodTilCanceled)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var executionTypes []gobinance.ExecutionType
+	for len(executionTypes) < 2 {
+		event := <-userData
+		if event.Err != nil {
+			t.Fatalf("unexpected error: %v", event.Err)
+		}
+		if report := event.ExecutionReport; report != nil && report.OrderID == int64(result.OrderID) {
+			executionTypes = append(executionTypes, report.ExecutionType)
+		}
+	}
+	if executionTypes[0] != gobinance.ExecutionTypeNew || executionTypes[1] != gobinance.ExecutionTypeTrade {
+		t.Errorf("unexpected execution types %v", executionTypes)
+	}
+	for trade := range trades {
+		if trade.BuyerOrderID == int64(result.OrderID) {
+			break
+		}
+	}
+}
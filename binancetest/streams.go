@@ -0,0 +1,232 @@
+package binancetest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/beyondallrepair/gobinance"
+	"github.com/gorilla/websocket"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is the number of messages buffered for a websocket connection.  Connections which fall further
+// behind are closed, as binance does for slow consumers.
+const subscriberBuffer = 1024
+
+type subscriber struct {
+	conn      *websocket.Conn
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (sub *subscriber) close() {
+	sub.closeOnce.Do(func() {
+		close(sub.done)
+		_ = sub.conn.Close()
+	})
+}
+
+// serveWebsocket serves the raw streams at /ws/<stream name>.  The supported streams are <symbol>@trade and the
+// user data stream of a listen key.
+func (s *Server) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/ws/")
+	s.mu.Lock()
+	_, isListenKey := s.listenKeys[name]
+	s.mu.Unlock()
+	if !isListenKey && !strings.HasSuffix(name, "@trade") {
+		http.NotFound(w, r)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	sub := &subscriber{
+		conn: conn,
+		send: make(chan []byte, subscriberBuffer),
+		done: make(chan struct{}),
+	}
+	s.mu.Lock()
+	if s.subscribers[name] == nil {
+		s.subscribers[name] = make(map[*subscriber]struct{})
+	}
+	s.subscribers[name][sub] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers[name], sub)
+		s.mu.Unlock()
+		sub.close()
+	}()
+
+	// read until the client closes the connection, so that closure is detected
+	go func() {
+		defer sub.close()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case msg := <-sub.send:
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// publish sends v to every connection subscribed to the stream.  It must be called with s.mu held.
+func (s *Server) publish(stream string, v interface{}) {
+	subs := s.subscribers[stream]
+	if len(subs) == 0 {
+		return
+	}
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	for sub := range subs {
+		select {
+		case sub.send <- bs:
+		default:
+			sub.close()
+		}
+	}
+}
+
+// publishUserData sends v to the user data stream of every listen key
+func (s *Server) publishUserData(v interface{}) {
+	for listenKey := range s.listenKeys {
+		s.publish(listenKey, v)
+	}
+}
+
+// publishTrade publishes a trade on the symbol's trade stream.  o is the order filled by the trade, or nil for a
+// trade by another market participant.
+func (s *Server) publishTrade(symbol string, tradeID int64, price *big.Float, qty *big.Float, o *gobinance.SpotOrder) {
+	now := millis(s.now())
+	event := tradeEventDTO{
+		Event:     "trade",
+		Time:      now,
+		Symbol:    symbol,
+		TradeID:   tradeID,
+		Price:     decimal(price),
+		Quantity:  decimal(qty),
+		TradeTime: now,
+		Ignore:    true,
+	}
+	if o != nil {
+		if o.Side == gobinance.OrderSideBuy {
+			event.BuyerOrderID = o.OrderID
+		} else {
+			event.SellerOrderID = o.OrderID
+		}
+	}
+	s.publish(strings.ToLower(symbol)+"@trade", event)
+}
+
+func (s *Server) publishAccountPosition(updateTime time.Time, balances ...gobinance.Balance) {
+	event := accountPositionDTO{
+		Event:          "outboundAccountPosition",
+		Time:           millis(s.now()),
+		LastUpdateTime: millis(updateTime),
+	}
+	for _, b := range balances {
+		event.Balances = append(event.Balances, accountPositionBalanceDTO{Asset: b.Asset, Free: decimal(b.Free), Locked: decimal(b.Locked)})
+	}
+	s.publishUserData(event)
+}
+
+func (s *Server) publishExecutionReport(x gobinance.SimulatedExecution) {
+	o := x.Order
+	event := executionReportDTO{
+		Event:               "executionReport",
+		Time:                millis(s.now()),
+		Symbol:              o.Symbol,
+		ClientOrderID:       o.ClientOrderID,
+		Side:                o.Side,
+		Type:                o.Type,
+		TimeInForce:         o.TimeInForce,
+		Quantity:            decimal(o.OriginalQty),
+		Price:               decimal(o.Price),
+		StopPrice:           decimal(o.StopPrice),
+		IcebergQty:          decimal(o.IcebergQty),
+		OrderListID:         -1,
+		ExecutionType:       x.ExecutionType,
+		Status:              o.Status,
+		RejectReason:        "NONE",
+		OrderID:             o.OrderID,
+		LastExecutedQty:     decimal(nil),
+		CumulativeFilledQty: decimal(o.ExecutedQty),
+		LastExecutedPrice:   decimal(nil),
+		Commission:          decimal(nil),
+		TransactionTime:     millis(x.Time),
+		TradeID:             -1,
+		IsWorking:           o.IsWorking,
+		IsMaker:             x.IsMaker,
+		CreationTime:        millis(o.Time),
+		CumulativeQuoteQty:  decimal(o.CumulativeQuoteQty),
+		LastQuoteQty:        decimal(nil),
+		QuoteOrderQty:       decimal(o.OriginalQuoteOrderQty),
+	}
+	if x.ExecutionType == gobinance.ExecutionTypeCanceled {
+		// binance reports the client order ID of the cancel request in c, and the order's in C
+		event.ClientOrderID, event.OriginalClientOrderID = "", o.ClientOrderID
+	}
+	if f := x.Fill; f != nil {
+		asset := f.CommissionAsset
+		event.LastExecutedQty = decimal(f.Qty)
+		event.LastExecutedPrice = decimal(f.Price)
+		event.LastQuoteQty = decimal(new(big.Float).Mul(f.Qty, f.Price))
+		event.Commission = decimal(f.Commission)
+		event.CommissionAsset = &asset
+		event.TradeID = f.TradeID
+	}
+	s.publishUserData(event)
+}
+
+func (s *Server) handleStartUserDataStream(params url.Values) (interface{}, *apiError) {
+	bs := make([]byte, 30)
+	_, _ = rand.Read(bs)
+	listenKey := hex.EncodeToString(bs)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listenKeys[listenKey] = true
+	return listenKeyDTO{ListenKey: listenKey}, nil
+}
+
+func (s *Server) handleKeepAliveUserDataStream(params url.Values) (interface{}, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.listenKeys[params.Get("listenKey")] {
+		return nil, newAPIError(http.StatusBadRequest, -1125, "This listenKey does not exist.")
+	}
+	return struct{}{}, nil
+}
+
+func (s *Server) handleCloseUserDataStream(params url.Values) (interface{}, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	listenKey := params.Get("listenKey")
+	if !s.listenKeys[listenKey] {
+		return nil, newAPIError(http.StatusBadRequest, -1125, "This listenKey does not exist.")
+	}
+	delete(s.listenKeys, listenKey)
+	for sub := range s.subscribers[listenKey] {
+		sub.close()
+	}
+	return struct{}{}, nil
+}
@@ -0,0 +1,533 @@
+package gobinance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// redacted replaces API keys and signatures in recorded cassettes
+const redacted = "REDACTED"
+
+// maxCassetteLine is the maximum length of a single entry in a cassette read by NewReplayer
+const maxCassetteLine = 64 * 1024 * 1024
+
+// CassetteEntryType is an enumeration of the types of entry recorded in a cassette
+type CassetteEntryType string
+
+const (
+	// CassetteEntryHTTP is an HTTP request and its response
+	CassetteEntryHTTP CassetteEntryType = "http"
+	// CassetteEntryDial is the opening of a websocket connection
+	CassetteEntryDial CassetteEntryType = "dial"
+	// CassetteEntryRead is a message read from a websocket connection, or the error returned in place of one
+	CassetteEntryRead CassetteEntryType = "read"
+	// CassetteEntryWrite is a message written to a websocket connection
+	CassetteEntryWrite CassetteEntryType = "write"
+	// CassetteEntryClose is the closing of a websocket connection by the client
+	CassetteEntryClose CassetteEntryType = "close"
+)
+
+// CassetteEntry is a single line of a cassette written by a Recorder
+type CassetteEntry struct {
+	Type CassetteEntryType `json:"type"`
+	// Time is when the entry was recorded
+	Time time.Time `json:"time"`
+	// Connection identifies the websocket connection of dial, read, write and close entries
+	Connection int `json:"connection,omitempty"`
+	// Method is the method of an HTTP request
+	Method string `json:"method,omitempty"`
+	// URL is the URL of an HTTP request or websocket connection, with any signature redacted
+	URL string `json:"url,omitempty"`
+	// RequestHeader holds the headers of an HTTP request or websocket handshake, with the API key redacted
+	RequestHeader http.Header `json:"requestHeader,omitempty"`
+	// RequestBody is the body of an HTTP request, with any signature redacted
+	RequestBody string `json:"requestBody,omitempty"`
+	// StatusCode is the status of an HTTP response or websocket handshake response
+	StatusCode int `json:"statusCode,omitempty"`
+	// ResponseHeader holds the headers of an HTTP response or websocket handshake response
+	ResponseHeader http.Header `json:"responseHeader,omitempty"`
+	// ResponseBody is the body of an HTTP response
+	ResponseBody string `json:"responseBody,omitempty"`
+	// MessageType is the type of a websocket message, as defined in RFC 6455
+	MessageType int `json:"messageType,omitempty"`
+	// Data is the content of a websocket message
+	Data string `json:"data,omitempty"`
+	// Error is the message of the error returned by the request, dial or read, if any
+	Error string `json:"error,omitempty"`
+}
+
+// Recorder is a Doer and DialContexter which records the HTTP requests and websocket messages passing through it
+// to a cassette, which may be replayed using a Replayer.  A cassette holds one JSON encoded CassetteEntry per line.
+//
+// API keys and signatures are redacted from the cassette, but other data such as balances and orders is recorded
+// as is.
+//
+// To record a Client's traffic, replace its Doer and DialContexter with a Recorder wrapping them:
+//
+//	rec := gobinance.NewRecorder(f, client.Doer, client.DialContexter)
+//	client.Doer, client.DialContexter = rec, rec
+type Recorder struct {
+	// Doer performs the HTTP requests being recorded
+	Doer Doer
+	// DialContexter makes the websocket connections being recorded
+	DialContexter DialContexter
+
+	mu             sync.Mutex
+	enc            *json.Encoder
+	err            error
+	nextConnection int
+}
+
+// NewRecorder returns a Recorder which writes a cassette to w, recording the requests made using doer and the
+// websocket connections made using dialer
+func NewRecorder(w io.Writer, doer Doer, dialer DialContexter) *Recorder {
+	return &Recorder{
+		Doer:          doer,
+		DialContexter: dialer,
+		enc:           json.NewEncoder(w),
+	}
+}
+
+// Err returns the first error encountered writing to the cassette, if any.  Requests are passed through even
+// when they can't be recorded.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(entry CassetteEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	entry.Time = time.Now()
+	if err := r.enc.Encode(entry); err != nil {
+		r.err = fmt.Errorf("unable to write cassette entry: %w", err)
+	}
+}
+
+// Do performs the request using the wrapped Doer, recording the request and its response
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	entry := CassetteEntry{
+		Type:          CassetteEntryHTTP,
+		Method:        req.Method,
+		URL:           redactURL(req.URL),
+		RequestHeader: redactHeader(req.Header),
+	}
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read request body: %w", err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		entry.RequestBody = redactParameters(string(body))
+	}
+
+	resp, err := r.Doer.Do(req)
+	if err != nil {
+		entry.Error = err.Error()
+		r.record(entry)
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		entry.Error = err.Error()
+		r.record(entry)
+		return nil, fmt.Errorf("unable to read response body: %w", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	entry.StatusCode = resp.StatusCode
+	entry.ResponseHeader = resp.Header
+	entry.ResponseBody = string(body)
+	r.record(entry)
+	return resp, nil
+}
+
+// DialContext makes a websocket connection using the wrapped DialContexter, recording the messages read from
+// and written to it.  The connection returned implements NextReaderWriterCloser when the wrapped connection does.
+func (r *Recorder) DialContext(ctx context.Context, rawURL string, hdr http.Header) (NextReaderCloser, *http.Response, error) {
+	r.mu.Lock()
+	r.nextConnection++
+	id := r.nextConnection
+	r.mu.Unlock()
+
+	entry := CassetteEntry{
+		Type:          CassetteEntryDial,
+		Connection:    id,
+		URL:           rawURL,
+		RequestHeader: redactHeader(hdr),
+	}
+	if u, err := url.Parse(rawURL); err == nil {
+		entry.URL = redactURL(u)
+	}
+	con, resp, err := r.DialContexter.DialContext(ctx, rawURL, hdr)
+	if resp != nil {
+		entry.StatusCode = resp.StatusCode
+		entry.ResponseHeader = resp.Header
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		r.record(entry)
+		return nil, resp, err
+	}
+	r.record(entry)
+
+	rc := &recordingConnection{NextReaderCloser: con, recorder: r, id: id}
+	if w, ok := con.(MessageWriter); ok {
+		return &recordingWriterConnection{recordingConnection: rc, writer: w}, resp, nil
+	}
+	return rc, resp, nil
+}
+
+type recordingConnection struct {
+	NextReaderCloser
+	recorder *Recorder
+	id       int
+	// closed is set to 1 once Close has been called, after which read errors are caused by the closure
+	// and so aren't recorded
+	closed int32
+}
+
+func (c *recordingConnection) NextReader() (int, io.Reader, error) {
+	messageType, msg, err := c.NextReaderCloser.NextReader()
+	var data []byte
+	if err == nil {
+		data, err = ioutil.ReadAll(msg)
+	}
+	if err != nil {
+		if atomic.LoadInt32(&c.closed) == 0 {
+			c.recorder.record(CassetteEntry{Type: CassetteEntryRead, Connection: c.id, Error: err.Error()})
+		}
+		return messageType, nil, err
+	}
+	c.recorder.record(CassetteEntry{Type: CassetteEntryRead, Connection: c.id, MessageType: messageType, Data: string(data)})
+	return messageType, bytes.NewReader(data), nil
+}
+
+func (c *recordingConnection) Close() error {
+	if atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		c.recorder.record(CassetteEntry{Type: CassetteEntryClose, Connection: c.id})
+	}
+	return c.NextReaderCloser.Close()
+}
+
+type recordingWriterConnection struct {
+	*recordingConnection
+	writer MessageWriter
+}
+
+func (c *recordingWriterConnection) WriteMessage(messageType int, data []byte) error {
+	err := c.writer.WriteMessage(messageType, data)
+	entry := CassetteEntry{Type: CassetteEntryWrite, Connection: c.id, MessageType: messageType, Data: string(data)}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	c.recorder.record(entry)
+	return err
+}
+
+var signatureParameter = regexp.MustCompile(`(^|&)` + signatureQuery + `=[^&]*`)
+
+// redactParameters replaces the value of the signature parameter in URL encoded parameters, leaving the
+// encoding of the other parameters untouched
+func redactParameters(encoded string) string {
+	return signatureParameter.ReplaceAllString(encoded, "${1}"+signatureQuery+"="+redacted)
+}
+
+func redactURL(u *url.URL) string {
+	cp := *u
+	cp.RawQuery = redactParameters(cp.RawQuery)
+	return cp.String()
+}
+
+func redactHeader(hdr http.Header) http.Header {
+	if hdr == nil {
+		return nil
+	}
+	cp := hdr.Clone()
+	if cp.Get(apiKeyHeader) != "" {
+		cp.Set(apiKeyHeader, redacted)
+	}
+	return cp
+}
+
+// ErrNotRecorded is matched by errors returned by a Replayer when a request or connection does not match any
+// unused entry in the cassette
+var ErrNotRecorded = errors.New("not recorded in cassette")
+
+// errReplayConnectionClosed is returned when reading from a replayed connection after it has been closed
+var errReplayConnectionClosed = errors.New("websocket connection closed")
+
+// ReplayerOption is a function that applies optional configuration to a Replayer
+type ReplayerOption func(r *Replayer)
+
+// ReplayIgnoreParameters excludes the named parameters, in addition to timestamp and signature, when matching
+// requests against the cassette.  This is useful for parameters which differ between runs, such as generated
+// client order IDs.
+func ReplayIgnoreParameters(names ...string) ReplayerOption {
+	return func(r *Replayer) {
+		for _, name := range names {
+			r.ignoredParameters[name] = true
+		}
+	}
+}
+
+// Replayer is a Doer and DialContexter which serves the HTTP responses and websocket messages recorded in a
+// cassette by a Recorder, allowing recorded sessions to be reproduced without connecting to binance.
+//
+// HTTP requests are matched to the first unused entry with the same method, path and parameters, where the
+// parameters of the query and form body are compared regardless of their order or location, ignoring the
+// timestamp and signature.  Websocket connections are matched to the first unused connection with the same URL.
+// Messages are replayed on a connection in the order they were recorded, with each read waiting until the
+// messages recorded as written before it have been written.  Once all messages have been read, reads block until
+// the connection is closed, unless the recording ended with an error.
+//
+// Errors are replayed with their recorded message but not their original type.
+type Replayer struct {
+	ignoredParameters map[string]bool
+
+	mu          sync.Mutex
+	http        []*CassetteEntry
+	dials       []*CassetteEntry
+	connections map[int][]CassetteEntry
+}
+
+// NewReplayer returns a Replayer serving the cassette read from r
+func NewReplayer(r io.Reader, opts ...ReplayerOption) (*Replayer, error) {
+	rp := &Replayer{
+		ignoredParameters: map[string]bool{
+			timestampQuery: true,
+			signatureQuery: true,
+		},
+		connections: make(map[int][]CassetteEntry),
+	}
+	for _, o := range opts {
+		o(rp)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxCassetteLine)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry CassetteEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("unable to parse cassette line %v: %w", line, err)
+		}
+		switch entry.Type {
+		case CassetteEntryHTTP:
+			rp.http = append(rp.http, &entry)
+		case CassetteEntryDial:
+			rp.dials = append(rp.dials, &entry)
+		case CassetteEntryRead, CassetteEntryWrite:
+			rp.connections[entry.Connection] = append(rp.connections[entry.Connection], entry)
+		case CassetteEntryClose:
+		default:
+			return nil, fmt.Errorf("unknown entry type %q on cassette line %v", entry.Type, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read cassette: %w", err)
+	}
+	return rp, nil
+}
+
+// Do returns the response recorded for the first unused entry matching the request
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	key, err := r.requestKey(req.Method, req.URL, req.Header.Get(contentTypeHeader), req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	var entry *CassetteEntry
+	for i, e := range r.http {
+		u, err := url.Parse(e.URL)
+		if err != nil {
+			continue
+		}
+		candidate, err := r.requestKey(e.Method, u, e.RequestHeader.Get(contentTypeHeader), strings.NewReader(e.RequestBody))
+		if err == nil && candidate == key {
+			entry = e
+			r.http = append(r.http[:i:i], r.http[i+1:]...)
+			break
+		}
+	}
+	r.mu.Unlock()
+	if entry == nil {
+		return nil, fmt.Errorf("%w: %v %v", ErrNotRecorded, req.Method, req.URL)
+	}
+
+	if entry.Error != "" {
+		return nil, errors.New(entry.Error)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+		StatusCode:    entry.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        entry.ResponseHeader,
+		Body:          ioutil.NopCloser(strings.NewReader(entry.ResponseBody)),
+		ContentLength: int64(len(entry.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// requestKey returns the method, path and normalised parameters of a request, excluding ignored parameters
+func (r *Replayer) requestKey(method string, u *url.URL, contentType string, body io.Reader) (string, error) {
+	params, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse query: %w", err)
+	}
+	if body != nil && strings.HasPrefix(contentType, formContentType) {
+		bs, err := ioutil.ReadAll(body)
+		if err != nil {
+			return "", fmt.Errorf("unable to read request body: %w", err)
+		}
+		bodyParams, err := url.ParseQuery(string(bs))
+		if err != nil {
+			return "", fmt.Errorf("unable to parse request body: %w", err)
+		}
+		for k, vs := range bodyParams {
+			params[k] = append(params[k], vs...)
+		}
+	}
+	for k := range r.ignoredParameters {
+		params.Del(k)
+	}
+	return method + " " + u.Path + "?" + params.Encode(), nil
+}
+
+// DialContext returns a connection replaying the messages of the first unused recorded connection to rawURL
+func (r *Replayer) DialContext(ctx context.Context, rawURL string, hdr http.Header) (NextReaderCloser, *http.Response, error) {
+	key := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		key = redactURL(u)
+	}
+
+	r.mu.Lock()
+	var entry *CassetteEntry
+	var messages []CassetteEntry
+	for i, e := range r.dials {
+		if e.URL == key {
+			entry = e
+			messages = r.connections[e.Connection]
+			delete(r.connections, e.Connection)
+			r.dials = append(r.dials[:i:i], r.dials[i+1:]...)
+			break
+		}
+	}
+	r.mu.Unlock()
+	if entry == nil {
+		return nil, nil, fmt.Errorf("%w: websocket connection to %v", ErrNotRecorded, rawURL)
+	}
+
+	var resp *http.Response
+	if entry.StatusCode != 0 {
+		resp = &http.Response{
+			Status:     fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+			StatusCode: entry.StatusCode,
+			Header:     entry.ResponseHeader,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+	}
+	if entry.Error != "" {
+		return nil, resp, errors.New(entry.Error)
+	}
+	return &replayConnection{
+		messages: messages,
+		written:  make(chan struct{}, 1),
+		closed:   make(chan struct{}),
+	}, resp, nil
+}
+
+type replayConnection struct {
+	mu       sync.Mutex
+	messages []CassetteEntry
+	// writes is the number of messages written which haven't yet been matched to a recorded write
+	writes    int
+	written   chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *replayConnection) NextReader() (int, io.Reader, error) {
+	for {
+		c.mu.Lock()
+		if len(c.messages) == 0 {
+			c.mu.Unlock()
+			<-c.closed
+			return 0, nil, errReplayConnectionClosed
+		}
+		select {
+		case <-c.closed:
+			c.mu.Unlock()
+			return 0, nil, errReplayConnectionClosed
+		default:
+		}
+		msg := c.messages[0]
+		if msg.Type == CassetteEntryWrite {
+			if c.writes > 0 {
+				c.writes--
+				c.messages = c.messages[1:]
+				c.mu.Unlock()
+				continue
+			}
+			c.mu.Unlock()
+			// wait for the client to write the message before replaying the rest of the connection
+			select {
+			case <-c.written:
+			case <-c.closed:
+			}
+			continue
+		}
+		c.messages = c.messages[1:]
+		c.mu.Unlock()
+		if msg.Error != "" {
+			return 0, nil, errors.New(msg.Error)
+		}
+		return msg.MessageType, strings.NewReader(msg.Data), nil
+	}
+}
+
+func (c *replayConnection) WriteMessage(messageType int, data []byte) error {
+	select {
+	case <-c.closed:
+		return errReplayConnectionClosed
+	default:
+	}
+	c.mu.Lock()
+	c.writes++
+	c.mu.Unlock()
+	select {
+	case c.written <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (c *replayConnection) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	return nil
+}
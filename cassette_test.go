@@ -0,0 +1,298 @@
+package gobinance_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testCassetteAccount = `{"updateTime":1500000000000,"canTrade":true,"balances":[{"asset":"BTC","free":"1.00000000","locked":"0.00000000"}]}`
+	testCassetteOrder   = `{"symbol":"BTCUSDT","orderId":7,"clientOrderId":"abc","transactTime":1500000000000,"price":"2","origQty":"1","executedQty":"0","status":"NEW","side":"BUY","type":"LIMIT"}`
+	testCassetteTrade   = `{"e":"trade","E":1500000000000,"s":"BTCUSDT","t":1,"p":"2","q":"1","T":1500000000000}`
+)
+
+// cassetteSession is the result of the requests made by recordSession
+type cassetteSession struct {
+	Account   gobinance.AccountInformation
+	Order     gobinance.SpotOrderResult
+	Trades    []gobinance.TradeEvent
+	StreamErr string
+}
+
+// recordSession makes some requests and opens a stream, returning the results
+func recordSession(t *testing.T, c *gobinance.Client) cassetteSession {
+	ctx := context.Background()
+	var session cassetteSession
+	var err error
+	if session.Account, err = c.AccountInformation(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	session.Order, err = c.PlaceLimitOrder(ctx, "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(1), big.NewFloat(2), gobinance.TimeInForceGoodTilCanceled, gobinance.SpotClientOrderID("abc"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for event := range c.Trades(ctx, "BTCUSDT") {
+		if event.Err != nil {
+			session.StreamErr = event.Err.Error()
+			continue
+		}
+		session.Trades = append(session.Trades, event.TradeEvent)
+	}
+	return session
+}
+
+func readCassette(t *testing.T, cassette []byte) []gobinance.CassetteEntry {
+	var entries []gobinance.CassetteEntry
+	scanner := bufio.NewScanner(bytes.NewReader(cassette))
+	for scanner.Scan() {
+		var entry gobinance.CassetteEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("unable to parse cassette line %q: %v", scanner.Text(), err)
+		}
+		entry.Time = time.Time{}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var cassette bytes.Buffer
+	doer := mockRoutedDoer(t, ctrl, map[string][]string{
+		"/api/v3/account": {testCassetteAccount},
+		"/api/v3/order":   {testCassetteOrder},
+	})
+	dialer := mockWebsocketMessages(ctrl, testWebsocketBaseURL+"/ws/btcusdt@trade", testCassetteTrade)
+	rec := gobinance.NewRecorder(&cassette, doer, dialer)
+	client := newTestUserDataClient(ctrl, rec, rec)
+	client.SignedRequestEncoding = gobinance.SignedRequestEncoding{Body: true}
+
+	session := recordSession(t, client)
+	if err := rec.Err(); err != nil {
+		t.Fatalf("unexpected error recording: %v", err)
+	}
+	if len(session.Trades) != 1 || session.StreamErr == "" {
+		t.Errorf("unexpected session %#v", session)
+	}
+	if strings.Contains(cassette.String(), testBinanceApiKey) || strings.Contains(cassette.String(), mockSignature) {
+		t.Errorf("expected the API key and signature to be redacted from the cassette\n%s", cassette.String())
+	}
+
+	entries := readCassette(t, cassette.Bytes())
+	if len(entries) != 6 {
+		t.Fatalf("expected 6 cassette entries but got %v\n%s", len(entries), cassette.String())
+	}
+	if e := entries[0]; e.Type != gobinance.CassetteEntryHTTP || e.Method != "GET" ||
+		e.URL != testBaseURL+"/api/v3/account?signature=REDACTED&timestamp=1234567890123" ||
+		e.RequestHeader.Get("X-MBX-APIKEY") != "REDACTED" || e.StatusCode != 200 || e.ResponseBody != testCassetteAccount {
+		t.Errorf("unexpected account entry %#v", e)
+	}
+	if e := entries[1]; e.Type != gobinance.CassetteEntryHTTP || e.Method != "POST" ||
+		e.RequestBody != "newClientOrderId=abc&newOrderRespType=FULL&price=2&quantity=1&side=BUY&symbol=BTCUSDT&timeInForce=GTC&timestamp=1234567890123&type=LIMIT&signature=REDACTED" ||
+		e.ResponseBody != testCassetteOrder {
+		t.Errorf("unexpected order entry %#v", e)
+	}
+	expectedStream := []gobinance.CassetteEntry{
+		{Type: gobinance.CassetteEntryDial, Connection: 1, URL: testWebsocketBaseURL + "/ws/btcusdt@trade"},
+		{Type: gobinance.CassetteEntryRead, Connection: 1, Data: testCassetteTrade},
+		{Type: gobinance.CassetteEntryRead, Connection: 1, Error: errStreamEnded.Error()},
+		{Type: gobinance.CassetteEntryClose, Connection: 1},
+	}
+	if diff := cmp.Diff(expectedStream, entries[2:]); diff != "" {
+		t.Errorf("unexpected stream entries.\n%s", diff)
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var cassette bytes.Buffer
+	doer := mockRoutedDoer(t, ctrl, map[string][]string{
+		"/api/v3/account": {testCassetteAccount},
+		"/api/v3/order":   {testCassetteOrder},
+	})
+	dialer := mockWebsocketMessages(ctrl, testWebsocketBaseURL+"/ws/btcusdt@trade", testCassetteTrade)
+	rec := gobinance.NewRecorder(&cassette, doer, dialer)
+	recorded := recordSession(t, newTestUserDataClient(ctrl, rec, rec))
+
+	replayer, err := gobinance.NewReplayer(bytes.NewReader(cassette.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := newTestUserDataClient(ctrl, replayer, replayer)
+	// the timestamp, signature and location of the parameters differ from the recording
+	client.Now = func() time.Time {
+		return mockNow().Add(time.Hour)
+	}
+	client.Signer = &gobinance.HMACSigner{Secret: "other"}
+	client.SignedRequestEncoding = gobinance.SignedRequestEncoding{Body: true}
+
+	replayed := recordSession(t, client)
+	if diff := cmp.Diff(recorded, replayed, bigFloatComparer); diff != "" {
+		t.Errorf("unexpected replayed session.\n%s", diff)
+	}
+
+	// each recorded exchange is replayed once
+	if _, err := client.AccountInformation(context.Background()); !errors.Is(err, gobinance.ErrNotRecorded) {
+		t.Errorf("expected ErrNotRecorded but got %v", err)
+	}
+	if event := <-client.Trades(context.Background(), "BTCUSDT"); !errors.Is(event.Err, gobinance.ErrNotRecorded) {
+		t.Errorf("expected ErrNotRecorded but got %v", event.Err)
+	}
+}
+
+func TestReplayer_Do(t *testing.T) {
+	t.Parallel()
+	const cassette = `{"type":"http","method":"GET","url":"https://example.com/api/v3/order?orderId=1&symbol=BTCUSDT&timestamp=1&signature=REDACTED","statusCode":200,"responseBody":"{\"orderId\":1}"}
+{"type":"http","method":"GET","url":"https://example.com/api/v3/order?orderId=2&symbol=BTCUSDT&timestamp=1&signature=REDACTED","statusCode":400,"responseBody":"{\"code\":-2013,\"msg\":\"Order does not exist.\"}"}
+{"type":"http","method":"DELETE","url":"https://example.com/api/v3/order?symbol=BTCUSDT","requestHeader":{"Content-Type":["application/x-www-form-urlencoded"]},"requestBody":"origClientOrderId=abc&timestamp=1&signature=REDACTED","statusCode":200,"responseBody":"{\"orderId\":1}"}
+{"type":"http","method":"GET","url":"https://example.com/api/v3/account?timestamp=1&signature=REDACTED","error":"connection reset"}
+`
+	testCases := []struct {
+		name         string
+		options      []gobinance.ReplayerOption
+		request      func(ctx context.Context, c *gobinance.Client) (interface{}, error)
+		errorCheck   errorCheck
+		expectedBody interface{}
+	}{
+		{
+			name: "parameters in any order",
+			request: func(ctx context.Context, c *gobinance.Client) (interface{}, error) {
+				return c.QueryOrderByID(ctx, "BTCUSDT", 1)
+			},
+			errorCheck:   errNil,
+			expectedBody: gobinance.SpotOrder{OrderID: 1},
+		},
+		{
+			name: "error responses",
+			request: func(ctx context.Context, c *gobinance.Client) (interface{}, error) {
+				return c.QueryOrderByID(ctx, "BTCUSDT", 2)
+			},
+			errorCheck: isHttpError(400, -2013),
+		},
+		{
+			name: "parameters in the query instead of the body",
+			request: func(ctx context.Context, c *gobinance.Client) (interface{}, error) {
+				return c.CancelOrderByClientOrderID(ctx, "BTCUSDT", "abc")
+			},
+			errorCheck:   errNil,
+			expectedBody: gobinance.CancelSpotOrderResult{OrderID: 1},
+		},
+		{
+			name: "ignored parameters",
+			request: func(ctx context.Context, c *gobinance.Client) (interface{}, error) {
+				return c.CancelOrderByClientOrderID(ctx, "BTCUSDT", "generated")
+			},
+			options:      []gobinance.ReplayerOption{gobinance.ReplayIgnoreParameters("origClientOrderId")},
+			errorCheck:   errNil,
+			expectedBody: gobinance.CancelSpotOrderResult{OrderID: 1},
+		},
+		{
+			name: "recorded errors",
+			request: func(ctx context.Context, c *gobinance.Client) (interface{}, error) {
+				return c.AccountInformation(ctx)
+			},
+			errorCheck: func(t *testing.T, err error) bool {
+				if err == nil || !strings.Contains(err.Error(), "connection reset") {
+					t.Errorf("expected the recorded error but got %v", err)
+				}
+				return false
+			},
+		},
+		{
+			name: "not recorded",
+			request: func(ctx context.Context, c *gobinance.Client) (interface{}, error) {
+				return c.QueryOrderByID(ctx, "BTCUSDT", 3)
+			},
+			errorCheck: func(t *testing.T, err error) bool {
+				if !errors.Is(err, gobinance.ErrNotRecorded) {
+					t.Errorf("expected ErrNotRecorded but got %v", err)
+				}
+				return false
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			replayer, err := gobinance.NewReplayer(strings.NewReader(cassette), tc.options...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			baseURL, _ := url.Parse(testBaseURL)
+			client := &gobinance.Client{
+				HTTPApiURL: baseURL,
+				Signer:     &gobinance.HMACSigner{Secret: "secret"},
+				Doer:       replayer,
+				Now:        mockNow,
+			}
+			got, err := tc.request(context.Background(), client)
+			if !tc.errorCheck(t, err) {
+				return
+			}
+			if diff := cmp.Diff(tc.expectedBody, got, bigFloatComparer); diff != "" {
+				t.Errorf("unexpected result.\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReplayer_StreamManager(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fake := newFakeStreamConnection(ctrl, func(method string, params []interface{}, id uint64) []string {
+		return []string{
+			fmt.Sprintf(`{"result":null,"id":%v}`, id),
+			fmt.Sprintf(`{"stream":"%v","data":{"e":"trade","s":"BTCUSDT"}}`, params[0]),
+		}
+	})
+	dialer := mock_gobinance.NewMockDialContexter(ctrl)
+	dialer.EXPECT().DialContext(gomock.Any(), testWebsocketBaseURL+"/stream", nil).Return(fake, nil, nil)
+
+	session := func(c *gobinance.Client) gobinance.StreamEventOrError {
+		ctx := context.Background()
+		m, err := c.NewStreamManager(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer m.Close()
+		if err := m.Subscribe(ctx, "btcusdt@trade"); err != nil {
+			t.Fatalf("unexpected error subscribing: %v", err)
+		}
+		return <-m.Events()
+	}
+
+	var cassette bytes.Buffer
+	rec := gobinance.NewRecorder(&cassette, nil, dialer)
+	recorded := session(newTestUserDataClient(ctrl, nil, rec))
+
+	replayer, err := gobinance.NewReplayer(bytes.NewReader(cassette.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	replayed := session(newTestUserDataClient(ctrl, nil, replayer))
+	if diff := cmp.Diff(recorded, replayed); diff != "" {
+		t.Errorf("unexpected replayed event.\n%s", diff)
+	}
+}
@@ -0,0 +1,103 @@
+package gobinance
+
+import (
+	"context"
+	"math/big"
+)
+
+//go:generate mockgen -destination=mocks/mock_client-interfaces.go . SpotTrader,StopLimitOrderPlacer,StopOrderPlacer,OrderQuerier,AccountReader,SpotAccount,MarketDataReader,TradeStreamer,MarketStreamer,UserDataStreamer
+
+// SpotTrader provides methods for placing and cancelling spot orders.  It is implemented by Client, PaperTrader
+// and Backtest.
+type SpotTrader interface {
+	PlaceLimitOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, price *big.Float, tif TimeInForce, opts ...SpotOrderOption) (SpotOrderResult, error)
+	PlaceLimitMakerOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, price *big.Float, opts ...SpotOrderOption) (SpotOrderResult, error)
+	PlaceSpotMarketOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, asset QuantityAsset, opts ...SpotOrderOption) (SpotOrderResult, error)
+	CancelOrderByOrderID(ctx context.Context, symbol string, orderID int64, opts ...CancelSpotOrderOption) (CancelSpotOrderResult, error)
+	CancelOrderByClientOrderID(ctx context.Context, symbol string, clientOrderID string, opts ...CancelSpotOrderOption) (CancelSpotOrderResult, error)
+}
+
+// StopLimitOrderPlacer provides methods for placing stop loss limit and take profit limit orders.  It is
+// implemented by Client and Backtest.
+type StopLimitOrderPlacer interface {
+	PlaceStopLossLimitOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, stopPrice *big.Float, limitPrice *big.Float, tif TimeInForce, opts ...SpotOrderOption) (SpotOrderResult, error)
+	PlaceTakeProfitLimitOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, stopPrice *big.Float, limitPrice *big.Float, tif TimeInForce, opts ...SpotOrderOption) (SpotOrderResult, error)
+}
+
+// StopOrderPlacer provides methods for placing stop loss and take profit orders, which become market orders once
+// triggered.  It is implemented by Client.
+type StopOrderPlacer interface {
+	PlaceStopLossOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, stopPrice *big.Float, opts ...SpotOrderOption) (SpotOrderResult, error)
+	PlaceTakeProfitOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, stopPrice *big.Float, opts ...SpotOrderOption) (SpotOrderResult, error)
+}
+
+// OrderQuerier provides methods for querying spot orders.  It is implemented by Client, PaperTrader and Backtest.
+type OrderQuerier interface {
+	QueryOrderByID(ctx context.Context, symbol string, orderID int64, opts ...QueryOrderOption) (SpotOrder, error)
+	QueryOrderByClientID(ctx context.Context, symbol string, clientOrderID string, opts ...QueryOrderOption) (SpotOrder, error)
+	OpenSpotOrdersForSymbol(ctx context.Context, symbol string, opts ...OpenOrdersOptions) ([]SpotOrder, error)
+	AllOpenSpotOrders(ctx context.Context, opts ...OpenOrdersOptions) ([]SpotOrder, error)
+}
+
+// AccountReader provides a method for reading the balances and commissions of an account.  It is implemented by
+// Client, PaperTrader and Backtest.
+type AccountReader interface {
+	AccountInformation(ctx context.Context) (AccountInformation, error)
+}
+
+// SpotAccount groups the methods needed to trade on a spot account, so that strategies written against it can be
+// run live with a Client, or simulated with a PaperTrader or Backtest
+type SpotAccount interface {
+	SpotTrader
+	OrderQuerier
+	AccountReader
+}
+
+// MarketDataReader provides methods for requesting market data.  It is implemented by Client.
+type MarketDataReader interface {
+	OrderBookDepth(ctx context.Context, symbol string, opts ...OrderBookDepthOption) (OrderBookDepth, error)
+	AggregateTrades(ctx context.Context, symbol string, opts ...AggregateTradesOption) ([]AggTrade, error)
+}
+
+// TradeStreamer provides a method for streaming the trades of a symbol.  It is implemented by Client and Backtest.
+type TradeStreamer interface {
+	Trades(ctx context.Context, symbol string) <-chan TradeEventOrError
+}
+
+// MarketStreamer provides methods for streaming market data.  It is implemented by Client.
+type MarketStreamer interface {
+	TradeStreamer
+	AggTrades(ctx context.Context, symbol string) <-chan AggTradeEventOrError
+	KlineStream(ctx context.Context, symbol string, interval KlineInterval) <-chan KlineEventOrError
+	PartialDepth(ctx context.Context, symbol string, levels DepthLevels, speed DepthUpdateSpeed) <-chan PartialDepthEventOrError
+	DepthUpdates(ctx context.Context, symbol string, speed DepthUpdateSpeed) <-chan DepthUpdateEventOrError
+	Ticker(ctx context.Context, symbol string) <-chan TickerEventOrError
+	AllTickers(ctx context.Context) <-chan TickerEventsOrError
+	RollingWindowTicker(ctx context.Context, symbol string, window TickerWindowSize) <-chan TickerEventOrError
+	AllRollingWindowTickers(ctx context.Context, window TickerWindowSize) <-chan TickerEventsOrError
+	MiniTicker(ctx context.Context, symbol string) <-chan MiniTickerEventOrError
+	AllMiniTickers(ctx context.Context) <-chan MiniTickerEventsOrError
+	BookTicker(ctx context.Context, symbol string) <-chan BookTickerEventOrError
+}
+
+// UserDataStreamer provides methods for managing and streaming the user data stream.  It is implemented by
+// Client.
+type UserDataStreamer interface {
+	StartUserDataStream(ctx context.Context) (string, error)
+	KeepAliveUserDataStream(ctx context.Context, listenKey string) error
+	CloseUserDataStream(ctx context.Context, listenKey string) error
+	UserData(ctx context.Context) <-chan UserDataEventOrError
+}
+
+var (
+	_ SpotAccount          = (*Client)(nil)
+	_ StopLimitOrderPlacer = (*Client)(nil)
+	_ StopOrderPlacer      = (*Client)(nil)
+	_ MarketDataReader     = (*Client)(nil)
+	_ MarketStreamer       = (*Client)(nil)
+	_ UserDataStreamer     = (*Client)(nil)
+	_ SpotAccount          = (*PaperTrader)(nil)
+	_ SpotAccount          = (*Backtest)(nil)
+	_ StopLimitOrderPlacer = (*Backtest)(nil)
+	_ TradeStreamer        = (*Backtest)(nil)
+)
@@ -0,0 +1,120 @@
+package gobinance
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxClientOrderIDLength is the maximum length of a client order ID accepted by binance
+	maxClientOrderIDLength = 36
+	// strategyTagSeparator separates the tag from the nonce in IDs generated by StrategyTagGenerator
+	strategyTagSeparator = ":"
+	// strategyNonceLength is the length of the nonce in IDs generated by StrategyTagGenerator
+	strategyNonceLength = 13
+	// MaxStrategyTagLength is the maximum length of the tag of a StrategyTagGenerator
+	MaxStrategyTagLength = maxClientOrderIDLength - len(strategyTagSeparator) - strategyNonceLength
+)
+
+var clientOrderIDPattern = regexp.MustCompile(`^[.A-Z:/a-z0-9_-]{1,36}$`)
+
+// sortableEncoding is Crockford's base32 alphabet, which sorts in the same order as the encoded bytes
+var sortableEncoding = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+// ValidateClientOrderID returns nil if id is acceptable to binance as a client order ID, or an error if not
+func ValidateClientOrderID(id string) error {
+	if !clientOrderIDPattern.MatchString(id) {
+		return fmt.Errorf("client order id %q must be 1 to %v characters from A-Z, a-z, 0-9, '.', ':', '/', '_' and '-'", id, maxClientOrderIDLength)
+	}
+	return nil
+}
+
+// ULIDGenerator generates 26 character client order IDs made from the current time in milliseconds followed by
+// 80 random bits, such that IDs generated in different milliseconds sort in the order they were generated.
+type ULIDGenerator struct {
+	// Now returns the current time.  When nil, time.Now is used.
+	Now func() time.Time
+}
+
+// NewClientOrderID returns a new client order ID
+func (u *ULIDGenerator) NewClientOrderID() (string, error) {
+	now := time.Now
+	if u.Now != nil {
+		now = u.Now
+	}
+	bs := make([]byte, 16)
+	binary.BigEndian.PutUint64(bs, uint64(now().UnixNano()/int64(time.Millisecond))<<16)
+	if _, err := rand.Read(bs[6:]); err != nil {
+		return "", fmt.Errorf("error generating random bits: %w", err)
+	}
+	return sortableEncoding.EncodeToString(bs), nil
+}
+
+// PrefixCounterGenerator generates client order IDs made from Prefix followed by a counter, starting at Start.
+//
+// Binance rejects a client order ID which is already in use by an open order, so when IDs are generated by several
+// processes each should use a different Prefix, and Start should be set so that IDs are not reused upon restart.
+type PrefixCounterGenerator struct {
+	// n is the number of IDs generated so far.  It is the first field to ensure 64-bit alignment.
+	n      uint64
+	Prefix string
+	Start  uint64
+}
+
+// NewClientOrderID returns a new client order ID
+func (p *PrefixCounterGenerator) NewClientOrderID() (string, error) {
+	next := atomic.AddUint64(&p.n, 1) - 1
+	id := p.Prefix + strconv.FormatUint(p.Start+next, 10)
+	if err := ValidateClientOrderID(id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// StrategyTagGenerator generates client order IDs made from Tag followed by a random nonce, allowing orders and
+// their fills to be attributed to the strategy that placed them using ParseStrategyTag.
+//
+// Tag may be up to MaxStrategyTagLength characters, and must not contain ':'.
+type StrategyTagGenerator struct {
+	Tag string
+}
+
+// NewClientOrderID returns a new client order ID
+func (s *StrategyTagGenerator) NewClientOrderID() (string, error) {
+	if len(s.Tag) > MaxStrategyTagLength || strings.Contains(s.Tag, strategyTagSeparator) {
+		return "", fmt.Errorf("strategy tag %q must be at most %v characters and not contain %q", s.Tag, MaxStrategyTagLength, strategyTagSeparator)
+	}
+	bs := make([]byte, 8)
+	if _, err := rand.Read(bs); err != nil {
+		return "", fmt.Errorf("error generating nonce: %w", err)
+	}
+	id := s.Tag + strategyTagSeparator + sortableEncoding.EncodeToString(bs)
+	if err := ValidateClientOrderID(id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// ParseStrategyTag returns the tag of a client order ID generated by a StrategyTagGenerator, or false if the ID
+// was not generated by a StrategyTagGenerator.
+func ParseStrategyTag(clientOrderID string) (string, bool) {
+	i := strings.LastIndex(clientOrderID, strategyTagSeparator)
+	if i < 0 {
+		return "", false
+	}
+	nonce := clientOrderID[i+len(strategyTagSeparator):]
+	if len(nonce) != strategyNonceLength {
+		return "", false
+	}
+	if _, err := sortableEncoding.DecodeString(nonce); err != nil {
+		return "", false
+	}
+	return clientOrderID[:i], true
+}
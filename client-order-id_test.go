@@ -0,0 +1,200 @@
+package gobinance_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValidateClientOrderID(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		id    string
+		valid bool
+	}{
+		{id: "abc-DEF_123.:/", valid: true},
+		{id: strings.Repeat("a", 36), valid: true},
+		{id: strings.Repeat("a", 37), valid: false},
+		{id: "", valid: false},
+		{id: "has space", valid: false},
+		{id: "emoji🙂", valid: false},
+	}
+	for _, tc := range testCases {
+		if err := gobinance.ValidateClientOrderID(tc.id); (err == nil) != tc.valid {
+			t.Errorf("unexpected result validating %q: %v", tc.id, err)
+		}
+	}
+}
+
+func TestULIDGenerator_NewClientOrderID(t *testing.T) {
+	t.Parallel()
+	now := mockNow()
+	uut := &gobinance.ULIDGenerator{Now: func() time.Time { return now }}
+
+	var prev string
+	for i := 0; i < 10; i++ {
+		id, err := uut.NewClientOrderID()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(id) != 26 {
+			t.Errorf("expected a 26 character id but got %q", id)
+		}
+		if err := gobinance.ValidateClientOrderID(id); err != nil {
+			t.Errorf("unexpected invalid id: %v", err)
+		}
+		if id <= prev {
+			t.Errorf("expected %q to sort after %q", id, prev)
+		}
+		prev = id
+		now = now.Add(time.Millisecond)
+	}
+}
+
+func TestPrefixCounterGenerator_NewClientOrderID(t *testing.T) {
+	t.Parallel()
+	t.Run("sequence", func(t *testing.T) {
+		t.Parallel()
+		uut := &gobinance.PrefixCounterGenerator{Prefix: "bot1-", Start: 100}
+		for _, expected := range []string{"bot1-100", "bot1-101", "bot1-102"} {
+			if got, err := uut.NewClientOrderID(); err != nil || got != expected {
+				t.Errorf("expected %q but got %q, %v", expected, got, err)
+			}
+		}
+	})
+	t.Run("concurrent ids are unique", func(t *testing.T) {
+		t.Parallel()
+		uut := &gobinance.PrefixCounterGenerator{}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		seen := make(map[string]bool)
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				id, _ := uut.NewClientOrderID()
+				mu.Lock()
+				seen[id] = true
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		if len(seen) != 100 {
+			t.Errorf("expected 100 unique ids but got %v", len(seen))
+		}
+	})
+	t.Run("invalid prefix", func(t *testing.T) {
+		t.Parallel()
+		uut := &gobinance.PrefixCounterGenerator{Prefix: "not valid"}
+		if _, err := uut.NewClientOrderID(); err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
+
+func TestStrategyTagGenerator_NewClientOrderID(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		tag      string
+		expectOK bool
+	}{
+		{tag: "momentum-v2", expectOK: true},
+		{tag: "", expectOK: true},
+		{tag: strings.Repeat("a", gobinance.MaxStrategyTagLength), expectOK: true},
+		{tag: strings.Repeat("a", gobinance.MaxStrategyTagLength+1), expectOK: false},
+		{tag: "has:colon", expectOK: false},
+		{tag: "has space", expectOK: false},
+	}
+	for _, tc := range testCases {
+		uut := &gobinance.StrategyTagGenerator{Tag: tc.tag}
+		id, err := uut.NewClientOrderID()
+		if !tc.expectOK {
+			if err == nil {
+				t.Errorf("expected an error for tag %q but got id %q", tc.tag, id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for tag %q: %v", tc.tag, err)
+			continue
+		}
+		if tag, ok := gobinance.ParseStrategyTag(id); !ok || tag != tc.tag {
+			t.Errorf("expected to parse tag %q from %q but got %q, %v", tc.tag, id, tag, ok)
+		}
+	}
+}
+
+func TestParseStrategyTag(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		id          string
+		expectedTag string
+		expectedOK  bool
+	}{
+		{id: "momentum:0123456789ABC", expectedTag: "momentum", expectedOK: true},
+		{id: "momentum:0123456789AB", expectedOK: false},
+		{id: "momentum:0123456789ABU", expectedOK: false},
+		{id: "web_EhBZVz8SJ8iJgpYzrVpWhh", expectedOK: false},
+	}
+	for _, tc := range testCases {
+		tag, ok := gobinance.ParseStrategyTag(tc.id)
+		if tag != tc.expectedTag || ok != tc.expectedOK {
+			t.Errorf("unexpected result parsing %q. expected %q, %v but got %q, %v", tc.id, tc.expectedTag, tc.expectedOK, tag, ok)
+		}
+	}
+}
+
+func TestClient_ClientOrderIDGenerator(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		options    []gobinance.SpotOrderOption
+		expectedID string
+	}{
+		{
+			name:       "generated",
+			expectedID: "test-7",
+		},
+		{
+			name:       "option takes precedence",
+			options:    []gobinance.SpotOrderOption{gobinance.SpotClientOrderID("explicit")},
+			expectedID: "explicit",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSigner := mock_gobinance.NewMockSigner(ctrl)
+			mockSigner.EXPECT().Sign(gomock.Any()).Return(mockSignature)
+			mockDoer := mock_gobinance.NewMockDoer(ctrl)
+			mockDoer.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+				if got := req.URL.Query().Get("newClientOrderId"); got != tc.expectedID {
+					t.Errorf("unexpected client order id. expected %q but got %q", tc.expectedID, got)
+				}
+			}).Return(nil, fmt.Errorf("stop early"))
+
+			u, _ := url.Parse(testBaseURL)
+			uut := &gobinance.Client{
+				HTTPApiURL:             u,
+				Signer:                 mockSigner,
+				Doer:                   mockDoer,
+				Now:                    mockNow,
+				ClientOrderIDGenerator: &gobinance.PrefixCounterGenerator{Prefix: "test-", Start: 7},
+			}
+			_, _ = uut.PlaceLimitOrder(context.Background(), "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(1), big.NewFloat(2), gobinance.TimeInForceGoodTilCanceled, tc.options...)
+		})
+	}
+}
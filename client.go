@@ -9,14 +9,15 @@ import (
 )
 
 //go:generate mockgen -destination=mocks/mock_http-client.go . Doer,Signer
+//go:generate mockgen -destination=mocks/mock_websockets.go . NextReaderCloser,DialContexter,NextReaderWriterCloser
 
 // Doer provides a Do method to perform an HTTP request
 type Doer interface {
 	Do(r *http.Request) (*http.Response, error)
 }
 
-// Signer provides a method to return an HMAC SHA256 hashed version of the
-// input string encoded as a hexadecimal string
+// Signer provides a method to return the signature of the input string, in
+// the encoding expected by binance for the type of API key in use
 type Signer interface {
 	Sign(input string) string
 }
@@ -28,11 +29,29 @@ type NextReaderCloser interface {
 	Close() error
 }
 
+// MessageWriter provides a method for writing messages to a WebSocket connection
+type MessageWriter interface {
+	WriteMessage(messageType int, data []byte) error
+}
+
+// NextReaderWriterCloser is a NextReaderCloser which also allows messages to be written
+// to the connection.  Connections returned by a DialContexter must implement this
+// interface in order to be used with a StreamManager.
+type NextReaderWriterCloser interface {
+	NextReaderCloser
+	MessageWriter
+}
+
 // DialContexter provides methods for initiating a websocket stream
 type DialContexter interface {
 	DialContext(ctx context.Context, url string, hdr http.Header) (NextReaderCloser, *http.Response, error)
 }
 
+// ClientOrderIDGenerator provides a method for generating client order IDs
+type ClientOrderIDGenerator interface {
+	NewClientOrderID() (string, error)
+}
+
 // Client provides methods for interacting with the binance API
 type Client struct {
 	// HTTPApiURL is the scheme and domain portion of the Binance HTTP API
@@ -43,19 +62,36 @@ type Client struct {
 	WebsocketApiURL *url.URL
 	// UserAgent is passed in HTTP requests as the `User-Agent` header
 	UserAgent string
-	// APIKey is the API key to use in authenticated requests to the binance API
+	// APIKey is the API key to use in authenticated requests to the binance API.  Ignored when
+	// CredentialsProvider is set.
 	APIKey string
 	// RecvWindow is the maximum duration allowed between the client making a request
 	// and binance receiving it.  Requests that take longer than this duration are rejected
 	// by binance. When this value is 0, binance's default value is used (see their official
 	// docs for information)
 	RecvWindow time.Duration
-	// Signer provides a method for signing requests before sending them to binance
+	// Signer provides a method for signing requests before sending them to binance.  Ignored when
+	// CredentialsProvider is set.
 	Signer Signer
+	// CredentialsProvider, when set, provides the API key and Signer for each authenticated request, in place of
+	// the APIKey and Signer fields
+	CredentialsProvider CredentialsProvider
+	// SignedRequestEncoding controls where the parameters of signed POST, PUT and DELETE requests are sent.  It
+	// may be overridden for individual requests using WithSignedRequestEncoding.
+	SignedRequestEncoding SignedRequestEncoding
 	// Doer provides a method for performing HTTP requests
 	Doer Doer
 	// DialContexter provides a method for making websocket connections
 	DialContexter DialContexter
+	// StreamObserver, when set, is notified of the activity of websocket streams
+	StreamObserver StreamObserver
+	// StreamBuffer controls how websocket streams are buffered while their consumers are busy.  It may be
+	// overridden for individual streams using WithStreamBuffer.
+	StreamBuffer StreamBuffer
 	// Now returns the current time
 	Now func() time.Time
+	// ClientOrderIDGenerator generates the client order ID of orders placed without the SpotClientOrderID option.
+	// When nil, binance generates the ID, unless the SpotIdempotent option is used, in which case a ULIDGenerator
+	// is used.
+	ClientOrderIDGenerator ClientOrderIDGenerator
 }
@@ -0,0 +1,399 @@
+package gobinance
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -destination=mocks/mock_credentials.go . CredentialsProvider,Keyring
+
+const (
+	// DefaultAPIKeyEnvVar is the environment variable read by EnvCredentials when APIKeyVar is empty
+	DefaultAPIKeyEnvVar = "BINANCE_API_KEY"
+	// DefaultSecretKeyEnvVar is the environment variable read by EnvCredentials when SecretKeyVar is empty
+	DefaultSecretKeyEnvVar = "BINANCE_SECRET_KEY"
+
+	defaultKeyringAPIKeyUser    = "api-key"
+	defaultKeyringSecretKeyUser = "secret-key"
+
+	keystoreVersion           = 1
+	defaultKeystoreIterations = 600000
+	// keystoreSaltLength is the length of the salt generated by WriteKeystore, and the minimum accepted when reading
+	keystoreSaltLength = 16
+)
+
+// Credentials are the API key and Signer used to authenticate requests
+type Credentials struct {
+	APIKey string
+	Signer Signer
+}
+
+// CredentialsProvider provides the credentials used to authenticate requests.  When set on a Client, it is called
+// for every authenticated request, so implementations which reload their source allow keys to be rotated without
+// restarting long-lived clients.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// NewCredentials creates Credentials from an API key and secret key.  If the secret key is a PEM encoded private
+// key, an RSASigner or Ed25519Signer is used, decrypting the key using passphrase if it is encrypted.  Otherwise the
+// secret key is used with an HMACSigner.
+func NewCredentials(apiKey string, secretKey string, passphrase []byte) (Credentials, error) {
+	if apiKey == "" {
+		return Credentials{}, fmt.Errorf("API key is empty")
+	}
+	if secretKey == "" {
+		return Credentials{}, fmt.Errorf("secret key is empty")
+	}
+	if !strings.HasPrefix(secretKey, "-----BEGIN ") {
+		return Credentials{APIKey: apiKey, Signer: &HMACSigner{Secret: secretKey}}, nil
+	}
+	key, err := parsePEMPrivateKey([]byte(secretKey), passphrase)
+	if err != nil {
+		return Credentials{}, err
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if err := k.Validate(); err != nil {
+			return Credentials{}, fmt.Errorf("invalid RSA private key: %w", err)
+		}
+		return Credentials{APIKey: apiKey, Signer: &RSASigner{PrivateKey: k}}, nil
+	case ed25519.PrivateKey:
+		return Credentials{APIKey: apiKey, Signer: &Ed25519Signer{PrivateKey: k}}, nil
+	default:
+		return Credentials{}, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// credentialsCache holds the Credentials created from the last API key and secret key seen by a provider, so that
+// signers are only recreated when the keys change
+type credentialsCache struct {
+	mu        sync.Mutex
+	apiKey    string
+	secretKey string
+	creds     Credentials
+}
+
+func (c *credentialsCache) get(apiKey string, secretKey string, passphrase []byte) (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.creds.Signer != nil && apiKey == c.apiKey && secretKey == c.secretKey {
+		return c.creds, nil
+	}
+	creds, err := NewCredentials(apiKey, secretKey, passphrase)
+	if err != nil {
+		return Credentials{}, err
+	}
+	c.apiKey, c.secretKey, c.creds = apiKey, secretKey, creds
+	return creds, nil
+}
+
+// EnvCredentials is a CredentialsProvider which reads the API key and secret key from environment variables.  The
+// variables are read on every call, so changes made to the environment of the process are picked up.
+type EnvCredentials struct {
+	// APIKeyVar is the name of the variable holding the API key.  When empty, DefaultAPIKeyEnvVar is used.
+	APIKeyVar string
+	// SecretKeyVar is the name of the variable holding the secret key, or a PEM encoded private key.  When empty,
+	// DefaultSecretKeyEnvVar is used.
+	SecretKeyVar string
+
+	cache credentialsCache
+}
+
+// Credentials returns the credentials held in the environment
+func (e *EnvCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	apiKeyVar, secretKeyVar := e.APIKeyVar, e.SecretKeyVar
+	if apiKeyVar == "" {
+		apiKeyVar = DefaultAPIKeyEnvVar
+	}
+	if secretKeyVar == "" {
+		secretKeyVar = DefaultSecretKeyEnvVar
+	}
+	creds, err := e.cache.get(os.Getenv(apiKeyVar), os.Getenv(secretKeyVar), nil)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("error reading credentials from %v and %v: %w", apiKeyVar, secretKeyVar, err)
+	}
+	return creds, nil
+}
+
+// FileCredentials is a CredentialsProvider which reads the API key and secret key from files, such as Docker
+// secrets (`/run/secrets/<name>`) or Kubernetes secrets mounted as a volume.  Leading and trailing whitespace is
+// removed from the contents of each file.
+//
+// The files are read again whenever their modification time or size changes.  Kubernetes updates mounted secrets
+// by atomically replacing a symlink, which is detected as a change, so rotated keys are picked up without a
+// restart.
+type FileCredentials struct {
+	// APIKeyFile is the path of the file holding the API key
+	APIKeyFile string
+	// SecretKeyFile is the path of the file holding the secret key, or a PEM encoded private key
+	SecretKeyFile string
+	// Passphrase is used to decrypt the private key in SecretKeyFile, if it is encrypted
+	Passphrase []byte
+
+	mu        sync.Mutex
+	apiKey    watchedFile
+	secretKey watchedFile
+	cache     credentialsCache
+}
+
+// Credentials returns the credentials held in the files, reading them if they have changed
+func (f *FileCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	f.mu.Lock()
+	apiKey, err := f.apiKey.read(f.APIKeyFile)
+	if err != nil {
+		f.mu.Unlock()
+		return Credentials{}, err
+	}
+	secretKey, err := f.secretKey.read(f.SecretKeyFile)
+	f.mu.Unlock()
+	if err != nil {
+		return Credentials{}, err
+	}
+	creds, err := f.cache.get(string(bytes.TrimSpace(apiKey)), string(bytes.TrimSpace(secretKey)), f.Passphrase)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("error reading credentials from %v and %v: %w", f.APIKeyFile, f.SecretKeyFile, err)
+	}
+	return creds, nil
+}
+
+// watchedFile holds the contents of a file, which is only read again when its modification time or size changes
+type watchedFile struct {
+	path     string
+	modTime  time.Time
+	size     int64
+	contents []byte
+}
+
+func (w *watchedFile) read(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %v: %w", path, err)
+	}
+	if w.contents != nil && path == w.path && info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return w.contents, nil
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %v: %w", path, err)
+	}
+	w.path, w.modTime, w.size, w.contents = path, info.ModTime(), info.Size(), contents
+	return contents, nil
+}
+
+// Keyring provides access to secrets held in a keyring, such as the macOS keychain, Windows credential manager, or
+// the freedesktop secret service.  The signature matches github.com/zalando/go-keyring, so a small adapter around
+// that package satisfies this interface.
+type Keyring interface {
+	Get(service string, user string) (string, error)
+}
+
+// KeyringCredentials is a CredentialsProvider which reads the API key and secret key from a Keyring.  The keyring
+// is read on every call, so keys updated in the keyring are picked up without a restart.
+type KeyringCredentials struct {
+	// Keyring holds the keys
+	Keyring Keyring
+	// Service is the name of the service the keys are stored under
+	Service string
+	// APIKeyUser is the user the API key is stored under.  When empty, "api-key" is used.
+	APIKeyUser string
+	// SecretKeyUser is the user the secret key, or PEM encoded private key, is stored under.  When empty,
+	// "secret-key" is used.
+	SecretKeyUser string
+
+	cache credentialsCache
+}
+
+// Credentials returns the credentials held in the keyring
+func (k *KeyringCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	apiKeyUser, secretKeyUser := k.APIKeyUser, k.SecretKeyUser
+	if apiKeyUser == "" {
+		apiKeyUser = defaultKeyringAPIKeyUser
+	}
+	if secretKeyUser == "" {
+		secretKeyUser = defaultKeyringSecretKeyUser
+	}
+	apiKey, err := k.Keyring.Get(k.Service, apiKeyUser)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("error reading API key from keyring: %w", err)
+	}
+	secretKey, err := k.Keyring.Get(k.Service, secretKeyUser)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("error reading secret key from keyring: %w", err)
+	}
+	creds, err := k.cache.get(apiKey, secretKey, nil)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("error reading credentials from keyring: %w", err)
+	}
+	return creds, nil
+}
+
+// keystoreFile is the format of a keystore written by WriteKeystore.  The keys are encrypted using AES-256-GCM with
+// a key derived from the passphrase using PBKDF2 with HMAC-SHA256.
+type keystoreFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// keystoreContents is the plaintext held in a keystore
+type keystoreContents struct {
+	APIKey    string `json:"apiKey"`
+	SecretKey string `json:"secretKey"`
+}
+
+// KeystoreOption is a function that applies optional configuration to a keystore written by WriteKeystore
+type KeystoreOption func(ks *keystoreFile)
+
+// KeystoreIterations sets the number of PBKDF2 iterations used to derive the encryption key from the passphrase.
+// The default is 600000, and at most 10000000 are allowed.  Reading the keystore takes longer with more iterations, which also slows down attempts
+// to guess the passphrase.
+func KeystoreIterations(n int) KeystoreOption {
+	return func(ks *keystoreFile) {
+		ks.Iterations = n
+	}
+}
+
+// WriteKeystore encrypts the API key and secret key using passphrase, and writes them to the file at path for use
+// with KeystoreCredentials.  The file is replaced atomically, so a KeystoreCredentials reading the same file sees
+// either the old or the new keys.
+func WriteKeystore(path string, apiKey string, secretKey string, passphrase []byte, opts ...KeystoreOption) error {
+	plaintext, err := json.Marshal(keystoreContents{APIKey: apiKey, SecretKey: secretKey})
+	if err != nil {
+		return err
+	}
+	ks := keystoreFile{
+		Version:    keystoreVersion,
+		Iterations: defaultKeystoreIterations,
+		Salt:       make([]byte, keystoreSaltLength),
+	}
+	for _, o := range opts {
+		o(&ks)
+	}
+	if _, err := rand.Read(ks.Salt); err != nil {
+		return fmt.Errorf("error generating salt: %w", err)
+	}
+	aead, err := keystoreCipher(passphrase, ks.Salt, ks.Iterations)
+	if err != nil {
+		return err
+	}
+	ks.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(ks.Nonce); err != nil {
+		return fmt.Errorf("error generating nonce: %w", err)
+	}
+	ks.Ciphertext = aead.Seal(nil, ks.Nonce, plaintext, nil)
+	bs, err := json.Marshal(ks)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("error writing keystore: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(bs); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("error writing keystore: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing keystore: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing keystore: %w", err)
+	}
+	return nil
+}
+
+// KeystoreCredentials is a CredentialsProvider which reads the API key and secret key from an encrypted keystore
+// file written by WriteKeystore.  The file is decrypted again whenever its modification time or size changes, so
+// keys rotated using WriteKeystore are picked up without a restart.
+type KeystoreCredentials struct {
+	// Path is the path of the keystore file
+	Path string
+	// Passphrase is used to decrypt the keystore
+	Passphrase []byte
+
+	mu       sync.Mutex
+	file     watchedFile
+	contents []byte
+	creds    Credentials
+}
+
+// Credentials returns the credentials held in the keystore, decrypting it if it has changed
+func (k *KeystoreCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	bs, err := k.file.read(k.Path)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if k.creds.Signer != nil && bytes.Equal(bs, k.contents) {
+		return k.creds, nil
+	}
+	creds, err := decryptKeystore(bs, k.Passphrase)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("error reading credentials from %v: %w", k.Path, err)
+	}
+	k.contents, k.creds = bs, creds
+	return creds, nil
+}
+
+func decryptKeystore(bs []byte, passphrase []byte) (Credentials, error) {
+	var ks keystoreFile
+	if err := json.Unmarshal(bs, &ks); err != nil {
+		return Credentials{}, fmt.Errorf("error parsing keystore: %w", err)
+	}
+	if ks.Version != keystoreVersion {
+		return Credentials{}, fmt.Errorf("unsupported keystore version %v", ks.Version)
+	}
+	aead, err := keystoreCipher(passphrase, ks.Salt, ks.Iterations)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if len(ks.Nonce) != aead.NonceSize() {
+		return Credentials{}, fmt.Errorf("invalid keystore nonce")
+	}
+	plaintext, err := aead.Open(nil, ks.Nonce, ks.Ciphertext, nil)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("incorrect passphrase")
+	}
+	var contents keystoreContents
+	if err := json.Unmarshal(plaintext, &contents); err != nil {
+		return Credentials{}, fmt.Errorf("error parsing keystore contents: %w", err)
+	}
+	return NewCredentials(contents.APIKey, contents.SecretKey, nil)
+}
+
+// keystoreCipher derives the keystore's encryption key from the passphrase, once the parameters of the derivation
+// have been validated, so that a malicious keystore cannot make reading it take an unbounded amount of time
+func keystoreCipher(passphrase []byte, salt []byte, iterations int) (cipher.AEAD, error) {
+	if iterations < 1 || iterations > maxPBKDF2Iterations {
+		return nil, fmt.Errorf("keystore iterations, %v, must be between 1 and %v", iterations, maxPBKDF2Iterations)
+	}
+	if len(salt) < keystoreSaltLength {
+		return nil, fmt.Errorf("keystore salt must be at least %v bytes", keystoreSaltLength)
+	}
+	block, err := aes.NewCipher(pbkdf2.Key(passphrase, salt, iterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
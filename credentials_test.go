@@ -0,0 +1,330 @@
+package gobinance_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewCredentials(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name           string
+		apiKey         string
+		secretKey      string
+		passphrase     string
+		errorCheck     errorCheck
+		expectedSigner string
+	}{
+		{name: "hmac", apiKey: "key", secretKey: "secret", errorCheck: errNil, expectedSigner: "*gobinance.HMACSigner"},
+		{name: "rsa", apiKey: "key", secretKey: testRSAKeyPKCS8, errorCheck: errNil, expectedSigner: "*gobinance.RSASigner"},
+		{name: "encrypted rsa", apiKey: "key", secretKey: testRSAKeyEncryptedPKCS8, passphrase: "secret", errorCheck: errNil, expectedSigner: "*gobinance.RSASigner"},
+		{name: "ed25519", apiKey: "key", secretKey: testEd25519Key, errorCheck: errNil, expectedSigner: "*gobinance.Ed25519Signer"},
+		{name: "unsupported key type", apiKey: "key", secretKey: testECDSAKey, errorCheck: errNotNil},
+		{name: "wrong passphrase", apiKey: "key", secretKey: testRSAKeyEncryptedPKCS8, passphrase: "wrong", errorCheck: errNotNil},
+		{name: "missing api key", secretKey: "secret", errorCheck: errNotNil},
+		{name: "missing secret key", apiKey: "key", errorCheck: errNotNil},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := gobinance.NewCredentials(tc.apiKey, tc.secretKey, []byte(tc.passphrase))
+			if !tc.errorCheck(t, err) {
+				return
+			}
+			if got.APIKey != tc.apiKey {
+				t.Errorf("unexpected API key %q", got.APIKey)
+			}
+			if signer := fmt.Sprintf("%T", got.Signer); signer != tc.expectedSigner {
+				t.Errorf("expected a %v but got %v", tc.expectedSigner, signer)
+			}
+		})
+	}
+}
+
+func TestEnvCredentials_Credentials(t *testing.T) {
+	t.Parallel()
+	const apiKeyVar, secretKeyVar = "GOBINANCE_TEST_ENV_API_KEY", "GOBINANCE_TEST_ENV_SECRET_KEY"
+	uut := &gobinance.EnvCredentials{APIKeyVar: apiKeyVar, SecretKeyVar: secretKeyVar}
+
+	if _, err := uut.Credentials(context.Background()); err == nil {
+		t.Errorf("expected an error when the variables are not set")
+	}
+
+	setenv(t, apiKeyVar, "key-1")
+	setenv(t, secretKeyVar, "secret-1")
+	first, err := uut.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.APIKey != "key-1" || first.Signer.(*gobinance.HMACSigner).Secret != "secret-1" {
+		t.Errorf("unexpected credentials %#v", first)
+	}
+	if again, _ := uut.Credentials(context.Background()); again.Signer != first.Signer {
+		t.Errorf("expected the signer to be reused while the keys are unchanged")
+	}
+
+	setenv(t, apiKeyVar, "key-2")
+	setenv(t, secretKeyVar, "secret-2")
+	rotated, err := uut.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotated.APIKey != "key-2" || rotated.Signer.(*gobinance.HMACSigner).Secret != "secret-2" {
+		t.Errorf("expected rotated credentials but got %#v", rotated)
+	}
+}
+
+func TestFileCredentials_Credentials(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	uut := &gobinance.FileCredentials{
+		APIKeyFile:    filepath.Join(dir, "api-key"),
+		SecretKeyFile: filepath.Join(dir, "secret-key"),
+		Passphrase:    []byte("secret"),
+	}
+
+	if _, err := uut.Credentials(context.Background()); err == nil {
+		t.Errorf("expected an error when the files do not exist")
+	}
+
+	modTime := time.Now().Add(-time.Hour)
+	writeTestFile(t, uut.APIKeyFile, "key-1\n", modTime)
+	writeTestFile(t, uut.SecretKeyFile, testRSAKeyEncryptedPKCS8+"\n", modTime)
+	first, err := uut.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := first.Signer.(*gobinance.RSASigner); !ok || first.APIKey != "key-1" {
+		t.Errorf("unexpected credentials %#v", first)
+	}
+	if again, _ := uut.Credentials(context.Background()); again.Signer != first.Signer {
+		t.Errorf("expected the signer to be reused while the files are unchanged")
+	}
+
+	// secrets mounted by kubernetes are rotated by replacing a symlink to the data
+	writeTestFile(t, filepath.Join(dir, "api-key-2"), "key-2", modTime.Add(time.Minute))
+	if err := os.Symlink(filepath.Join(dir, "api-key-2"), uut.APIKeyFile+".new"); err != nil {
+		t.Fatalf("unable to create symlink: %v", err)
+	}
+	if err := os.Rename(uut.APIKeyFile+".new", uut.APIKeyFile); err != nil {
+		t.Fatalf("unable to replace file: %v", err)
+	}
+	writeTestFile(t, uut.SecretKeyFile, "hmac-secret", modTime.Add(time.Minute))
+	rotated, err := uut.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := rotated.Signer.(*gobinance.HMACSigner); !ok || rotated.APIKey != "key-2" {
+		t.Errorf("expected rotated credentials but got %#v", rotated)
+	}
+}
+
+func TestKeyringCredentials_Credentials(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		uut        func(keyring gobinance.Keyring) *gobinance.KeyringCredentials
+		setup      func(keyring *mock_gobinance.MockKeyring)
+		errorCheck errorCheck
+	}{
+		{
+			name: "default users",
+			uut: func(keyring gobinance.Keyring) *gobinance.KeyringCredentials {
+				return &gobinance.KeyringCredentials{Keyring: keyring, Service: "binance"}
+			},
+			setup: func(keyring *mock_gobinance.MockKeyring) {
+				keyring.EXPECT().Get("binance", "api-key").Return("key", nil)
+				keyring.EXPECT().Get("binance", "secret-key").Return("secret", nil)
+			},
+			errorCheck: errNil,
+		},
+		{
+			name: "custom users",
+			uut: func(keyring gobinance.Keyring) *gobinance.KeyringCredentials {
+				return &gobinance.KeyringCredentials{Keyring: keyring, Service: "binance", APIKeyUser: "a", SecretKeyUser: "s"}
+			},
+			setup: func(keyring *mock_gobinance.MockKeyring) {
+				keyring.EXPECT().Get("binance", "a").Return("key", nil)
+				keyring.EXPECT().Get("binance", "s").Return("secret", nil)
+			},
+			errorCheck: errNil,
+		},
+		{
+			name: "keyring error",
+			uut: func(keyring gobinance.Keyring) *gobinance.KeyringCredentials {
+				return &gobinance.KeyringCredentials{Keyring: keyring, Service: "binance"}
+			},
+			setup: func(keyring *mock_gobinance.MockKeyring) {
+				keyring.EXPECT().Get("binance", "api-key").Return("", fmt.Errorf("locked"))
+			},
+			errorCheck: errNotNil,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			keyring := mock_gobinance.NewMockKeyring(ctrl)
+			tc.setup(keyring)
+
+			got, err := tc.uut(keyring).Credentials(context.Background())
+			if !tc.errorCheck(t, err) {
+				return
+			}
+			if got.APIKey != "key" || got.Signer.(*gobinance.HMACSigner).Secret != "secret" {
+				t.Errorf("unexpected credentials %#v", got)
+			}
+		})
+	}
+}
+
+func TestKeystoreCredentials_Credentials(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	if err := gobinance.WriteKeystore(path, "key-1", testEd25519Key, []byte("passphrase"), gobinance.KeystoreIterations(1000)); err != nil {
+		t.Fatalf("unexpected error writing keystore: %v", err)
+	}
+
+	wrong := &gobinance.KeystoreCredentials{Path: path, Passphrase: []byte("wrong")}
+	if _, err := wrong.Credentials(context.Background()); err == nil {
+		t.Errorf("expected an error with the wrong passphrase")
+	}
+
+	uut := &gobinance.KeystoreCredentials{Path: path, Passphrase: []byte("passphrase")}
+	first, err := uut.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := first.Signer.(*gobinance.Ed25519Signer); !ok || first.APIKey != "key-1" {
+		t.Errorf("unexpected credentials %#v", first)
+	}
+
+	if err := gobinance.WriteKeystore(path, "key-2", "hmac-secret", []byte("passphrase"), gobinance.KeystoreIterations(1000)); err != nil {
+		t.Fatalf("unexpected error writing keystore: %v", err)
+	}
+	rotated, err := uut.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := rotated.Signer.(*gobinance.HMACSigner); !ok || rotated.APIKey != "key-2" {
+		t.Errorf("expected rotated credentials but got %#v", rotated)
+	}
+}
+
+func TestClient_CredentialsProvider(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider := mock_gobinance.NewMockCredentialsProvider(ctrl)
+	signer1 := mock_gobinance.NewMockSigner(ctrl)
+	signer1.EXPECT().Sign(gomock.Any()).Return("signature-1")
+	signer2 := mock_gobinance.NewMockSigner(ctrl)
+	signer2.EXPECT().Sign(gomock.Any()).Return("signature-2")
+	gomock.InOrder(
+		provider.EXPECT().Credentials(gomock.Any()).Return(gobinance.Credentials{APIKey: "key-1", Signer: signer1}, nil),
+		provider.EXPECT().Credentials(gomock.Any()).Return(gobinance.Credentials{APIKey: "key-2", Signer: signer2}, nil),
+		provider.EXPECT().Credentials(gomock.Any()).Return(gobinance.Credentials{}, fmt.Errorf("test error")),
+	)
+
+	var gotKeys, gotSignatures []string
+	mockDoer := mock_gobinance.NewMockDoer(ctrl)
+	mockDoer.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+		gotKeys = append(gotKeys, req.Header.Get("X-MBX-APIKEY"))
+		gotSignatures = append(gotSignatures, req.URL.Query().Get("signature"))
+	}).Return(nil, fmt.Errorf("stop early")).Times(2)
+
+	u, _ := url.Parse(testBaseURL)
+	uut := &gobinance.Client{
+		HTTPApiURL:          u,
+		APIKey:              "ignored",
+		CredentialsProvider: provider,
+		Doer:                mockDoer,
+		Now:                 mockNow,
+	}
+	for i := 0; i < 3; i++ {
+		_, _ = uut.AccountInformation(context.Background())
+	}
+	if fmt.Sprint(gotKeys) != "[key-1 key-2]" || fmt.Sprint(gotSignatures) != "[signature-1 signature-2]" {
+		t.Errorf("expected rotated credentials to be used but got keys %v and signatures %v", gotKeys, gotSignatures)
+	}
+}
+
+func setenv(t *testing.T, key string, value string) {
+	prev, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatalf("unable to set %v: %v", key, err)
+	}
+	t.Cleanup(func() {
+		if ok {
+			_ = os.Setenv(key, prev)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	})
+}
+
+func writeTestFile(t *testing.T, path string, contents string, modTime time.Time) {
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("unable to write %v: %v", path, err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("unable to set modification time of %v: %v", path, err)
+	}
+}
+
+func TestKeystore_KeyDerivationParameters(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, iterations := range []int{0, 10000001} {
+		if err := gobinance.WriteKeystore(filepath.Join(dir, "invalid.json"), "key", "secret", []byte("passphrase"), gobinance.KeystoreIterations(iterations)); err == nil {
+			t.Errorf("expected an error writing a keystore with %v iterations", iterations)
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		keystore string
+	}{
+		{
+			name:     "too many iterations",
+			keystore: `{"version":1,"iterations":2000000000,"salt":"AAAAAAAAAAAAAAAAAAAAAA==","nonce":"AAAAAAAAAAAAAAAA","ciphertext":"AA=="}`,
+		},
+		{
+			name:     "no iterations",
+			keystore: `{"version":1,"iterations":0,"salt":"AAAAAAAAAAAAAAAAAAAAAA==","nonce":"AAAAAAAAAAAAAAAA","ciphertext":"AA=="}`,
+		},
+		{
+			name:     "short salt",
+			keystore: `{"version":1,"iterations":1000,"salt":"AA==","nonce":"AAAAAAAAAAAAAAAA","ciphertext":"AA=="}`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(dir, strings.Replace(tc.name, " ", "-", -1)+".json")
+			if err := ioutil.WriteFile(path, []byte(tc.keystore), 0600); err != nil {
+				t.Fatalf("unexpected error writing keystore: %v", err)
+			}
+			uut := &gobinance.KeystoreCredentials{Path: path, Passphrase: []byte("passphrase")}
+			if _, err := uut.Credentials(context.Background()); err == nil || !strings.Contains(err.Error(), "keystore") {
+				t.Errorf("expected the keystore to be rejected but got %v", err)
+			}
+		})
+	}
+}
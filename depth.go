@@ -0,0 +1,267 @@
+package gobinance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PriceLevel is the total quantity of orders at a single price on one side of the order book.  In depth updates,
+// a quantity of zero indicates that the price level should be removed.
+type PriceLevel struct {
+	Price    *big.Float
+	Quantity *big.Float
+}
+
+// UnmarshalJSON converts a `[price, quantity]` pair as sent by binance into a PriceLevel
+func (p *PriceLevel) UnmarshalJSON(bs []byte) error {
+	var tmp []*big.Float
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	if len(tmp) != 2 {
+		return fmt.Errorf("expected a price level of 2 values but got %v", len(tmp))
+	}
+	*p = PriceLevel{
+		Price:    tmp[0],
+		Quantity: tmp[1],
+	}
+	return nil
+}
+
+// OrderBookDepth is a snapshot of the order book of a symbol, as returned by binance's depth endpoint
+type OrderBookDepth struct {
+	LastUpdateID int64
+	Bids         []PriceLevel
+	Asks         []PriceLevel
+}
+
+// UnmarshalJSON provides custom unmarshalling for OrderBookDepth.
+func (o *OrderBookDepth) UnmarshalJSON(bs []byte) error {
+	var tmp struct {
+		LastUpdateID int64        `json:"lastUpdateId"`
+		Bids         []PriceLevel `json:"bids"`
+		Asks         []PriceLevel `json:"asks"`
+	}
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	*o = OrderBookDepth{
+		LastUpdateID: tmp.LastUpdateID,
+		Bids:         tmp.Bids,
+		Asks:         tmp.Asks,
+	}
+	return nil
+}
+
+// OrderBookDepthOption is a function that applies optional parameters to a request for the order book
+type OrderBookDepthOption func(input *orderBookDepthInput)
+
+// OrderBookDepthLimit sets the number of levels on each side of the book to return.  When unset, binance's default
+// of 100 is used.  The maximum is 5000.
+func OrderBookDepthLimit(limit int) OrderBookDepthOption {
+	return func(input *orderBookDepthInput) {
+		input.Limit = limit
+	}
+}
+
+type orderBookDepthInput struct {
+	Symbol string `param:"symbol"`
+	Limit  int    `param:"limit,omitempty"`
+}
+
+func applyOrderBookDepthOptions(input *orderBookDepthInput, opts ...OrderBookDepthOption) {
+	for _, o := range opts {
+		o(input)
+	}
+}
+
+// OrderBookDepth fetches a snapshot of the order book for the given symbol
+func (c *Client) OrderBookDepth(ctx context.Context, symbol string, opts ...OrderBookDepthOption) (OrderBookDepth, error) {
+	input := orderBookDepthInput{
+		Symbol: symbol,
+	}
+	applyOrderBookDepthOptions(&input, opts...)
+	params, err := toURLValues(input)
+	if err != nil {
+		return OrderBookDepth{}, fmt.Errorf("error building request parameters: %w", err)
+	}
+
+	req, err := c.buildUnsignedRequest(ctx, http.MethodGet, "/api/v3/depth", params, false)
+	if err != nil {
+		return OrderBookDepth{}, fmt.Errorf("error building request: %w", err)
+	}
+
+	var result OrderBookDepth
+	err = performRequest(c.Doer, req, &result)
+	return result, err
+}
+
+// PartialDepthEvent define websocket partial book depth event, holding the top levels of the order book
+type PartialDepthEvent struct {
+	LastUpdateID int64
+	Bids         []PriceLevel
+	Asks         []PriceLevel
+}
+
+// UnmarshalJSON provides custom unmarshalling for PartialDepthEvents.
+func (p *PartialDepthEvent) UnmarshalJSON(bs []byte) error {
+	var tmp struct {
+		LastUpdateID int64        `json:"lastUpdateId"`
+		Bids         []PriceLevel `json:"bids"`
+		Asks         []PriceLevel `json:"asks"`
+	}
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	*p = PartialDepthEvent{
+		LastUpdateID: tmp.LastUpdateID,
+		Bids:         tmp.Bids,
+		Asks:         tmp.Asks,
+	}
+	return nil
+}
+
+// PartialDepthEventOrError is a union of PartialDepthEvent or error
+type PartialDepthEventOrError struct {
+	PartialDepthEvent
+	Err error
+}
+
+// DepthUpdateEvent define websocket diff depth event, holding the changes made to the order book between
+// FirstUpdateID and FinalUpdateID inclusive.
+type DepthUpdateEvent struct {
+	Event         string
+	Time          time.Time
+	Symbol        string
+	FirstUpdateID int64
+	FinalUpdateID int64
+	Bids          []PriceLevel
+	Asks          []PriceLevel
+}
+
+// UnmarshalJSON provides custom unmarshalling for DepthUpdateEvents.
+func (d *DepthUpdateEvent) UnmarshalJSON(bs []byte) error {
+	var tmp struct {
+		Event         string          `json:"e"`
+		Time          millisTimestamp `json:"E"`
+		Symbol        string          `json:"s"`
+		FirstUpdateID int64           `json:"U"`
+		FinalUpdateID int64           `json:"u"`
+		Bids          []PriceLevel    `json:"b"`
+		Asks          []PriceLevel    `json:"a"`
+	}
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	*d = DepthUpdateEvent{
+		Event:         tmp.Event,
+		Time:          time.Time(tmp.Time),
+		Symbol:        tmp.Symbol,
+		FirstUpdateID: tmp.FirstUpdateID,
+		FinalUpdateID: tmp.FinalUpdateID,
+		Bids:          tmp.Bids,
+		Asks:          tmp.Asks,
+	}
+	return nil
+}
+
+// DepthUpdateEventOrError is a union of DepthUpdateEvent or error
+type DepthUpdateEventOrError struct {
+	DepthUpdateEvent
+	Err error
+}
+
+// PartialDepth initiates a websocket connection to binance and returns a channel from which the top `levels` bids
+// and asks of the symbol's order book are streamed at the given speed.  The channel is closed when the underlying
+// context is cancelled, or upon a connection error or the server closing the connection.
+func (c *Client) PartialDepth(ctx context.Context, symbol string, levels DepthLevels, speed DepthUpdateSpeed) <-chan PartialDepthEventOrError {
+	out := make(chan PartialDepthEventOrError, 1)
+	if err := levels.Validate(); err != nil {
+		out <- PartialDepthEventOrError{Err: err}
+		close(out)
+		return out
+	}
+	suffix, err := depthSpeedSuffix(speed)
+	if err != nil {
+		out <- PartialDepthEventOrError{Err: err}
+		close(out)
+		return out
+	}
+
+	handle := func(reader io.Reader, err error) error {
+		if err != nil {
+			out <- PartialDepthEventOrError{Err: err}
+			return nil
+		}
+		var depth PartialDepthEvent
+		dec := json.NewDecoder(reader)
+		if err := dec.Decode(&depth); err != nil {
+			out <- PartialDepthEventOrError{Err: fmt.Errorf("error decoding partial depth event: %w", err)}
+			return err
+		}
+		out <- PartialDepthEventOrError{PartialDepthEvent: depth}
+		return nil
+	}
+	path := fmt.Sprintf("/ws/%s@depth%d%s", url.PathEscape(strings.ToLower(symbol)), levels, suffix)
+
+	go c.openWebsocket(ctx, path, handle, func() {
+		close(out)
+	})
+	return out
+}
+
+// DepthUpdates initiates a websocket connection to binance and returns a channel from which changes to the symbol's
+// order book are streamed at the given speed.  The channel is closed when the underlying context is cancelled, or
+// upon a connection error or the server closing the connection.
+//
+// Each update is a diff of the previous one, so the stream should not be buffered using a StreamBuffer policy which
+// drops messages.
+func (c *Client) DepthUpdates(ctx context.Context, symbol string, speed DepthUpdateSpeed) <-chan DepthUpdateEventOrError {
+	out := make(chan DepthUpdateEventOrError, 1)
+	suffix, err := depthSpeedSuffix(speed)
+	if err != nil {
+		out <- DepthUpdateEventOrError{Err: err}
+		close(out)
+		return out
+	}
+
+	handle := func(reader io.Reader, err error) error {
+		if err != nil {
+			out <- DepthUpdateEventOrError{Err: err}
+			return nil
+		}
+		var update DepthUpdateEvent
+		dec := json.NewDecoder(reader)
+		if err := dec.Decode(&update); err != nil {
+			out <- DepthUpdateEventOrError{Err: fmt.Errorf("error decoding depth update event: %w", err)}
+			return err
+		}
+		out <- DepthUpdateEventOrError{DepthUpdateEvent: update}
+		return nil
+	}
+	path := fmt.Sprintf("/ws/%s@depth%s", url.PathEscape(strings.ToLower(symbol)), suffix)
+
+	go c.openWebsocket(ctx, path, handle, func() {
+		close(out)
+	})
+	return out
+}
+
+// depthSpeedSuffix returns the suffix to append to a depth stream's name in order to receive updates at the given
+// speed.  The default speed of 1000ms requires no suffix.
+func depthSpeedSuffix(speed DepthUpdateSpeed) (string, error) {
+	if err := speed.Validate(); err != nil {
+		return "", err
+	}
+	if speed == DepthUpdateSpeed1000ms {
+		return "", nil
+	}
+	return "@" + string(speed), nil
+}
@@ -0,0 +1,223 @@
+package gobinance_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_PartialDepth(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name        string
+		levels      gobinance.DepthLevels
+		speed       gobinance.DepthUpdateSpeed
+		expectedURL string
+	}{
+		{
+			name:        "default speed",
+			levels:      gobinance.DepthLevels5,
+			speed:       gobinance.DepthUpdateSpeed1000ms,
+			expectedURL: testWebsocketBaseURL + "/ws/bnbbtc@depth5",
+		},
+		{
+			name:        "100ms",
+			levels:      gobinance.DepthLevels20,
+			speed:       gobinance.DepthUpdateSpeed100ms,
+			expectedURL: testWebsocketBaseURL + "/ws/bnbbtc@depth20@100ms",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			uut := newTestStreamClient(t, tc.expectedURL,
+				`{"lastUpdateId":160,"bids":[["0.0024","10"],["0.0023","1"]],"asks":[["0.0026","100"]]}`)
+
+			var got []gobinance.PartialDepthEventOrError
+			for e := range uut.PartialDepth(context.Background(), "BNBBTC", tc.levels, tc.speed) {
+				got = append(got, e)
+			}
+			expected := []gobinance.PartialDepthEventOrError{
+				{
+					PartialDepthEvent: gobinance.PartialDepthEvent{
+						LastUpdateID: 160,
+						Bids: []gobinance.PriceLevel{
+							{Price: mustParseBigFloat(t, "0.0024"), Quantity: mustParseBigFloat(t, "10")},
+							{Price: mustParseBigFloat(t, "0.0023"), Quantity: mustParseBigFloat(t, "1")},
+						},
+						Asks: []gobinance.PriceLevel{
+							{Price: mustParseBigFloat(t, "0.0026"), Quantity: mustParseBigFloat(t, "100")},
+						},
+					},
+				},
+				{Err: errStreamEnded},
+			}
+			if diff := cmp.Diff(expected, got, bigFloatComparer, cmp.Comparer(errors.Is)); diff != "" {
+				t.Errorf("unexpected events.  %s", diff)
+			}
+		})
+	}
+
+	t.Run("invalid levels", func(t *testing.T) {
+		t.Parallel()
+		uut := &gobinance.Client{}
+		var got []gobinance.PartialDepthEventOrError
+		for e := range uut.PartialDepth(context.Background(), "BNBBTC", 15, gobinance.DepthUpdateSpeed100ms) {
+			got = append(got, e)
+		}
+		if len(got) != 1 || got[0].Err == nil {
+			t.Errorf("expected a single error but got %#v", got)
+		}
+	})
+}
+
+func TestClient_DepthUpdates(t *testing.T) {
+	t.Parallel()
+	t.Run("events received", func(t *testing.T) {
+		t.Parallel()
+		uut := newTestStreamClient(t, testWebsocketBaseURL+"/ws/bnbbtc@depth@100ms",
+			`{"e":"depthUpdate","E":123456789,"s":"BNBBTC","U":157,"u":160,"b":[["0.0024","10"]],"a":[["0.0026","0"]]}`,
+			`{"e":"depthUpdate","E":123456789,"s":"BNBBTC","U":161,"u":161,"b":[["0.0024"]],"a":[]}`,
+		)
+
+		var got []gobinance.DepthUpdateEventOrError
+		for e := range uut.DepthUpdates(context.Background(), "BNBBTC", gobinance.DepthUpdateSpeed100ms) {
+			got = append(got, e)
+		}
+		if l := len(got); l != 3 {
+			t.Fatalf("expected 3 events but got %v", l)
+		}
+		if got[1].Err == nil {
+			t.Errorf("expected malformed price level to cause an error")
+		}
+		expected := gobinance.DepthUpdateEvent{
+			Event:         "depthUpdate",
+			Time:          time.Date(1970, 01, 02, 10, 17, 36, int(789*time.Millisecond), time.UTC),
+			Symbol:        "BNBBTC",
+			FirstUpdateID: 157,
+			FinalUpdateID: 160,
+			Bids: []gobinance.PriceLevel{
+				{Price: mustParseBigFloat(t, "0.0024"), Quantity: mustParseBigFloat(t, "10")},
+			},
+			Asks: []gobinance.PriceLevel{
+				{Price: mustParseBigFloat(t, "0.0026"), Quantity: mustParseBigFloat(t, "0")},
+			},
+		}
+		if diff := cmp.Diff(expected, got[0].DepthUpdateEvent, bigFloatComparer); diff != "" {
+			t.Errorf("unexpected event.  %s", diff)
+		}
+	})
+	t.Run("invalid speed", func(t *testing.T) {
+		t.Parallel()
+		uut := &gobinance.Client{}
+		var got []gobinance.DepthUpdateEventOrError
+		for e := range uut.DepthUpdates(context.Background(), "BNBBTC", "10ms") {
+			got = append(got, e)
+		}
+		if len(got) != 1 || got[0].Err == nil {
+			t.Errorf("expected a single error but got %#v", got)
+		}
+	})
+}
+
+func TestClient_OrderBookDepth(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mocks *clientMocks)
+		options        []gobinance.OrderBookDepthOption
+		errorCheck     errorCheck
+		expectedResult gobinance.OrderBookDepth
+	}{
+		{
+			name: "request values",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+					if req.Method != http.MethodGet {
+						t.Errorf("unexpected http method: expected %v but got %v", http.MethodGet, req.Method)
+					}
+					if req.URL.Path != "/api/v3/depth" {
+						t.Errorf("unexpected path: expected %v but got %v", "/api/v3/depth", req.URL.Path)
+					}
+					expected := url.Values{"symbol": {"BNBBTC"}, "limit": {"5000"}}
+					if diff := cmp.Diff(expected, req.URL.Query()); diff != "" {
+						t.Errorf("unexpected parameters passed to request:\n%v", diff)
+					}
+				}).Return(nil, fmt.Errorf("stop early"))
+			},
+			options:    []gobinance.OrderBookDepthOption{gobinance.OrderBookDepthLimit(5000)},
+			errorCheck: errNotNil,
+		},
+		{
+			name: "http error",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: 400,
+					Body:       ioutil.NopCloser(strings.NewReader(`{ "msg":"test message", "code":-1234 }`)),
+				}, nil)
+			},
+			errorCheck: isHttpError(400, -1234),
+		},
+		{
+			name: "success",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(strings.NewReader(`{"lastUpdateId":1027024,"bids":[["4.00000000","431.00000000"]],"asks":[["4.00000200","12.00000000"]]}`)),
+				}, nil)
+			},
+			errorCheck: errNil,
+			expectedResult: gobinance.OrderBookDepth{
+				LastUpdateID: 1027024,
+				Bids: []gobinance.PriceLevel{
+					{Price: mustParseBigFloat(t, "4.00000000"), Quantity: mustParseBigFloat(t, "431.00000000")},
+				},
+				Asks: []gobinance.PriceLevel{
+					{Price: mustParseBigFloat(t, "4.00000200"), Quantity: mustParseBigFloat(t, "12.00000000")},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mocks := &clientMocks{
+				MockDoer: mock_gobinance.NewMockDoer(ctrl),
+			}
+
+			u, _ := url.Parse(testBaseURL)
+			uut := &gobinance.Client{
+				HTTPApiURL: u,
+				UserAgent:  testUserAgent,
+				Doer:       mocks.MockDoer,
+			}
+
+			tc.setup(t, mocks)
+			got, err := uut.OrderBookDepth(context.Background(), "BNBBTC", tc.options...)
+			if cont := tc.errorCheck(t, err); !cont {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expectedResult, got, bigFloatComparer); diff != "" {
+				t.Errorf("unexpected result.\n%s", diff)
+			}
+		})
+	}
+}
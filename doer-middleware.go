@@ -0,0 +1,188 @@
+package gobinance
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -destination=mocks/mock_doer-middleware.go . RequestMetrics
+
+const (
+	usedWeightHeaderPrefix = "X-MBX-USED-WEIGHT-"
+	orderCountHeaderPrefix = "X-MBX-ORDER-COUNT-"
+)
+
+// DoerFunc is an adapter allowing an ordinary function to be used as a Doer
+type DoerFunc func(r *http.Request) (*http.Response, error)
+
+// Do calls f(r)
+func (f DoerFunc) Do(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Middleware wraps a Doer, returning a Doer which may act upon requests before passing them to next, and upon the
+// responses returned
+type Middleware func(next Doer) Doer
+
+// ChainDoer wraps doer with the middlewares.  The first middleware is the outermost, so sees requests first and
+// responses last.
+func ChainDoer(doer Doer, middlewares ...Middleware) Doer {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		doer = middlewares[i](doer)
+	}
+	return doer
+}
+
+// ClientMiddleware wraps the client's Doer with the middlewares, as ChainDoer does.  The Doer set by options
+// given before this one is wrapped, so ClientDoer must be given first when used.
+func ClientMiddleware(middlewares ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.Doer = ChainDoer(c.Doer, middlewares...)
+	}
+}
+
+// RequestLog describes an HTTP request made to binance and its outcome.  Secrets are redacted.
+type RequestLog struct {
+	Method string
+	// URL is the URL requested, with any signature redacted
+	URL string
+	// RequestHeader holds the headers of the request, with the API key redacted
+	RequestHeader http.Header
+	// RequestBody is the form encoded body of the request, if any, with any signature redacted
+	RequestBody string
+	// StatusCode is the status of the response, or zero if the request failed without a response
+	StatusCode int
+	// ErrorCode is the binance error code given in an unsuccessful response, or zero
+	ErrorCode      int
+	ResponseHeader http.Header
+	Duration       time.Duration
+	// Err is the error returned when the request failed without a response
+	Err error
+}
+
+// LoggingMiddleware returns a Middleware which calls log with a description of each request once it completes
+func LoggingMiddleware(log func(entry RequestLog)) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(r *http.Request) (*http.Response, error) {
+			entry := RequestLog{
+				Method:        r.Method,
+				URL:           redactURL(r.URL),
+				RequestHeader: redactHeader(r.Header),
+			}
+			if r.GetBody != nil {
+				if body, err := r.GetBody(); err == nil {
+					bs, _ := ioutil.ReadAll(body)
+					body.Close()
+					entry.RequestBody = redactParameters(string(bs))
+				}
+			}
+
+			start := time.Now()
+			resp, err := next.Do(r)
+			entry.Duration = time.Since(start)
+			entry.Err = err
+			if resp != nil {
+				entry.StatusCode = resp.StatusCode
+				entry.ResponseHeader = resp.Header.Clone()
+				entry.ErrorCode = responseErrorCode(resp)
+			}
+			log(entry)
+			return resp, err
+		})
+	}
+}
+
+// RequestMetrics receives measurements of the HTTP requests made to binance, so that they can be exported to a
+// metrics system without this package depending upon it.  With Prometheus, for example, latencies would be
+// observed by a histogram, responses and errors counted by counters and usage set on gauges.  Endpoints are the
+// paths requested, such as /api/v3/order.
+type RequestMetrics interface {
+	// ObserveLatency is called with the duration of each request, whether or not it succeeded
+	ObserveLatency(method string, endpoint string, d time.Duration)
+	// CountResponse is called for each response received.  errorCode is the binance error code given in an
+	// unsuccessful response, or zero.
+	CountResponse(method string, endpoint string, status int, errorCode int)
+	// CountError is called for each request which failed without a response
+	CountError(method string, endpoint string)
+	// SetWeightUsed is called with the request weight used in each rate limit interval reported in a response.
+	// Intervals are given as reported by binance, such as 1m.
+	SetWeightUsed(interval string, weight int64)
+	// SetOrderCount is called with the number of orders placed in each rate limit interval reported in a
+	// response.  Intervals are given as reported by binance, such as 10s or 1d.
+	SetOrderCount(interval string, count int64)
+}
+
+// MetricsMiddleware returns a Middleware which records the latency, outcome and rate limit usage of each request
+// with metrics
+func MetricsMiddleware(metrics RequestMetrics) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(r *http.Request) (*http.Response, error) {
+			endpoint := r.URL.Path
+			start := time.Now()
+			resp, err := next.Do(r)
+			metrics.ObserveLatency(r.Method, endpoint, time.Since(start))
+			if err != nil {
+				metrics.CountError(r.Method, endpoint)
+				return resp, err
+			}
+			metrics.CountResponse(r.Method, endpoint, resp.StatusCode, responseErrorCode(resp))
+			for name, values := range resp.Header {
+				if len(values) == 0 {
+					continue
+				}
+				name = strings.ToUpper(name)
+				value, err := strconv.ParseInt(values[0], 10, 64)
+				if err != nil {
+					continue
+				}
+				switch {
+				case strings.HasPrefix(name, usedWeightHeaderPrefix):
+					metrics.SetWeightUsed(strings.ToLower(strings.TrimPrefix(name, usedWeightHeaderPrefix)), value)
+				case strings.HasPrefix(name, orderCountHeaderPrefix):
+					metrics.SetOrderCount(strings.ToLower(strings.TrimPrefix(name, orderCountHeaderPrefix)), value)
+				}
+			}
+			return resp, nil
+		})
+	}
+}
+
+// RequestTracer is called before each request with the request to be sent, whose context carries the caller's
+// trace.  It returns the request to send, such as a clone with a traceparent header added to propagate the trace,
+// and a function to be called with the outcome of the request, such as to end a span.
+type RequestTracer func(r *http.Request) (*http.Request, func(resp *http.Response, err error))
+
+// TracingMiddleware returns a Middleware which calls tracer around each request
+func TracingMiddleware(tracer RequestTracer) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(r *http.Request) (*http.Response, error) {
+			r, end := tracer(r)
+			resp, err := next.Do(r)
+			if end != nil {
+				end(resp, err)
+			}
+			return resp, err
+		})
+	}
+}
+
+// responseErrorCode returns the binance error code given in the body of an unsuccessful response, or zero.  The
+// body is replaced so that it can be read again.
+func responseErrorCode(resp *http.Response) int {
+	if resp.StatusCode == http.StatusOK || resp.Body == nil {
+		return 0
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	var errorBody errorDTO
+	if err := json.Unmarshal(body, &errorBody); err != nil {
+		return 0
+	}
+	return errorBody.Code
+}
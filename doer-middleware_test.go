@@ -0,0 +1,223 @@
+package gobinance_test
+
+import (
+	"context"
+	"errors"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// recordingMiddleware returns a middleware which appends name to calls before and after each request
+func recordingMiddleware(name string, calls *[]string) gobinance.Middleware {
+	return func(next gobinance.Doer) gobinance.Doer {
+		return gobinance.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			*calls = append(*calls, "before "+name)
+			resp, err := next.Do(r)
+			*calls = append(*calls, "after "+name)
+			return resp, err
+		})
+	}
+}
+
+func newTestResponse(status int, body string, hdr http.Header) *http.Response {
+	if hdr == nil {
+		hdr = make(http.Header)
+	}
+	return &http.Response{StatusCode: status, Header: hdr, Body: ioutil.NopCloser(strings.NewReader(body))}
+}
+
+func TestChainDoer(t *testing.T) {
+	t.Parallel()
+	var calls []string
+	doer := gobinance.ChainDoer(gobinance.DoerFunc(func(r *http.Request) (*http.Response, error) {
+		calls = append(calls, "request")
+		return newTestResponse(http.StatusOK, "{}", nil), nil
+	}), recordingMiddleware("outer", &calls), recordingMiddleware("inner", &calls))
+
+	req, _ := http.NewRequest(http.MethodGet, testBaseURL+"/api/v3/time", nil)
+	if _, err := doer.Do(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"before outer", "before inner", "request", "after inner", "after outer"}
+	if diff := cmp.Diff(expected, calls); diff != "" {
+		t.Errorf("unexpected calls.\n%s", diff)
+	}
+}
+
+func TestClientMiddleware(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	doer := mock_gobinance.NewMockDoer(ctrl)
+	doer.EXPECT().Do(gomock.Any()).Return(newTestResponse(http.StatusOK, `{"bids":[],"asks":[]}`, nil), nil)
+
+	var calls []string
+	client, err := gobinance.NewClient(
+		gobinance.ClientDoer(doer),
+		gobinance.ClientMiddleware(recordingMiddleware("middleware", &calls)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.OrderBookDepth(context.Background(), "BTCUSDT"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"before middleware", "after middleware"}, calls); diff != "" {
+		t.Errorf("unexpected calls.\n%s", diff)
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		response *http.Response
+		err      error
+		expected gobinance.RequestLog
+	}{
+		{
+			name:     "error response",
+			response: newTestResponse(http.StatusBadRequest, `{"code":-2010,"msg":"Account has insufficient balance for requested action."}`, http.Header{"X-Mbx-Used-Weight-1m": {"3"}}),
+			expected: gobinance.RequestLog{
+				Method:         http.MethodPost,
+				URL:            testBaseURL + "/api/v3/order?timestamp=1&signature=REDACTED",
+				RequestHeader:  http.Header{"X-Mbx-Apikey": {"REDACTED"}, "Content-Type": {"application/x-www-form-urlencoded"}},
+				RequestBody:    "symbol=BTCUSDT&signature=REDACTED",
+				StatusCode:     http.StatusBadRequest,
+				ErrorCode:      -2010,
+				ResponseHeader: http.Header{"X-Mbx-Used-Weight-1m": {"3"}},
+			},
+		},
+		{
+			name: "request error",
+			err:  errors.New("connection reset"),
+			expected: gobinance.RequestLog{
+				Method:        http.MethodPost,
+				URL:           testBaseURL + "/api/v3/order?timestamp=1&signature=REDACTED",
+				RequestHeader: http.Header{"X-Mbx-Apikey": {"REDACTED"}, "Content-Type": {"application/x-www-form-urlencoded"}},
+				RequestBody:   "symbol=BTCUSDT&signature=REDACTED",
+				Err:           errors.New("connection reset"),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			doer := mock_gobinance.NewMockDoer(ctrl)
+			doer.EXPECT().Do(gomock.Any()).Return(tc.response, tc.err)
+
+			var entries []gobinance.RequestLog
+			uut := gobinance.LoggingMiddleware(func(entry gobinance.RequestLog) {
+				entries = append(entries, entry)
+			})(doer)
+			req, _ := http.NewRequest(http.MethodPost, testBaseURL+"/api/v3/order?timestamp=1&signature=abc", strings.NewReader("symbol=BTCUSDT&signature=def"))
+			req.Header.Set("X-MBX-APIKEY", testBinanceApiKey)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			resp, err := uut.Do(req)
+			if err != tc.err {
+				t.Errorf("expected error %v but got %v", tc.err, err)
+			}
+			if resp != nil {
+				// the body is still readable after the error code has been read from it
+				if body, _ := ioutil.ReadAll(resp.Body); !strings.Contains(string(body), "-2010") {
+					t.Errorf("unexpected body %q", body)
+				}
+			}
+
+			if len(entries) != 1 {
+				t.Fatalf("expected a single log entry but got %#v", entries)
+			}
+			if entries[0].Duration < 0 {
+				t.Errorf("unexpected duration %v", entries[0].Duration)
+			}
+			entries[0].Duration = 0
+			errComparer := cmp.Comparer(func(a, b error) bool {
+				return (a == nil) == (b == nil) && (a == nil || a.Error() == b.Error())
+			})
+			if diff := cmp.Diff(tc.expected, entries[0], errComparer); diff != "" {
+				t.Errorf("unexpected log entry.\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	doer := mock_gobinance.NewMockDoer(ctrl)
+	metrics := mock_gobinance.NewMockRequestMetrics(ctrl)
+	uut := gobinance.MetricsMiddleware(metrics)(doer)
+
+	hdr := http.Header{
+		"X-Mbx-Used-Weight":      {"20"},
+		"X-Mbx-Used-Weight-1m":   {"20"},
+		"X-Mbx-Order-Count-10s":  {"2"},
+		"X-Mbx-Order-Count-1d":   {"30"},
+		"X-Mbx-Order-Count-Oops": {"not a number"},
+	}
+	doer.EXPECT().Do(gomock.Any()).Return(newTestResponse(http.StatusTooManyRequests, `{"code":-1003,"msg":"Too many requests."}`, hdr), nil)
+	metrics.EXPECT().ObserveLatency(http.MethodPost, "/api/v3/order", gomock.Any())
+	metrics.EXPECT().CountResponse(http.MethodPost, "/api/v3/order", http.StatusTooManyRequests, -1003)
+	metrics.EXPECT().SetWeightUsed("1m", int64(20))
+	metrics.EXPECT().SetOrderCount("10s", int64(2))
+	metrics.EXPECT().SetOrderCount("1d", int64(30))
+	req, _ := http.NewRequest(http.MethodPost, testBaseURL+"/api/v3/order?symbol=BTCUSDT", nil)
+	if _, err := uut.Do(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	requestErr := errors.New("timeout")
+	doer.EXPECT().Do(gomock.Any()).Return(nil, requestErr)
+	metrics.EXPECT().ObserveLatency(http.MethodGet, "/api/v3/account", gomock.Any())
+	metrics.EXPECT().CountError(http.MethodGet, "/api/v3/account")
+	req, _ = http.NewRequest(http.MethodGet, testBaseURL+"/api/v3/account", nil)
+	if _, err := uut.Do(req); err != requestErr {
+		t.Errorf("expected %v but got %v", requestErr, err)
+	}
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	doer := mock_gobinance.NewMockDoer(ctrl)
+	response := newTestResponse(http.StatusOK, "{}", nil)
+	doer.EXPECT().Do(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+		if got := r.Header.Get("traceparent"); got != "00-trace-span-01" {
+			t.Errorf("expected the trace header to be propagated but got %q", got)
+		}
+		return response, nil
+	})
+
+	type traceKey struct{}
+	var ended *http.Response
+	uut := gobinance.TracingMiddleware(func(r *http.Request) (*http.Request, func(*http.Response, error)) {
+		r = r.Clone(r.Context())
+		r.Header.Set("traceparent", r.Context().Value(traceKey{}).(string))
+		return r, func(resp *http.Response, err error) {
+			ended = resp
+		}
+	})(doer)
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "00-trace-span-01")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, testBaseURL+"/api/v3/time", nil)
+	if _, err := uut.Do(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ended != response {
+		t.Errorf("expected the span to be ended with the response")
+	}
+	if req.Header.Get("traceparent") != "" {
+		t.Errorf("expected the caller's request to be left unmodified")
+	}
+}
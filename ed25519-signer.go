@@ -0,0 +1,31 @@
+package gobinance
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+)
+
+// Ed25519Signer provides a method for signing data using an Ed25519 private key, for use with Ed25519 API keys
+type Ed25519Signer struct {
+	PrivateKey ed25519.PrivateKey
+}
+
+// NewEd25519Signer creates an Ed25519Signer from a PEM encoded PKCS #8 Ed25519 private key.  If the key is
+// encrypted, it is decrypted using passphrase.
+func NewEd25519Signer(pemKey []byte, passphrase []byte) (*Ed25519Signer, error) {
+	key, err := parsePEMPrivateKey(pemKey, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("expected an Ed25519 private key but got %T", key)
+	}
+	return &Ed25519Signer{PrivateKey: edKey}, nil
+}
+
+// Sign generates an Ed25519 signature of the `input` parameter, encoded as base64
+func (e *Ed25519Signer) Sign(input string) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(e.PrivateKey, []byte(input)))
+}
@@ -37,6 +37,29 @@ func (s OrderStatus) Validate() error {
 	return nil
 }
 
+// IsTerminal returns true if an order with this status can no longer be filled or changed
+func (s OrderStatus) IsTerminal() bool {
+	switch s {
+	case OrderStatusFilled, OrderStatusCanceled, OrderStatusRejected, OrderStatusExpired:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo returns true if an order with this status may subsequently be given the next status.  Orders
+// are only ever filled further or closed, so no status may be returned to once left, other than
+// OrderStatusPartiallyFilled which is repeated upon each further partial fill.
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	switch s {
+	case OrderStatusNew:
+		return next != OrderStatusNew
+	case OrderStatusPartiallyFilled:
+		return next == OrderStatusPartiallyFilled || next == OrderStatusFilled || next == OrderStatusCanceled ||
+			next == OrderStatusExpired
+	}
+	return false
+}
+
 // OrderType is an enumeration of the possible types of orders that can be placed
 type OrderType string
 
@@ -148,3 +171,146 @@ const (
 	// QuantityAssetBase indicates the quantity relates to the base asset
 	QuantityAssetBase = "BASE"
 )
+
+// KlineInterval is an enumeration of the possible intervals of klines / candlesticks
+type KlineInterval string
+
+const (
+	KlineInterval1s  KlineInterval = "1s"
+	KlineInterval1m  KlineInterval = "1m"
+	KlineInterval3m  KlineInterval = "3m"
+	KlineInterval5m  KlineInterval = "5m"
+	KlineInterval15m KlineInterval = "15m"
+	KlineInterval30m KlineInterval = "30m"
+	KlineInterval1h  KlineInterval = "1h"
+	KlineInterval2h  KlineInterval = "2h"
+	KlineInterval4h  KlineInterval = "4h"
+	KlineInterval6h  KlineInterval = "6h"
+	KlineInterval8h  KlineInterval = "8h"
+	KlineInterval12h KlineInterval = "12h"
+	KlineInterval1d  KlineInterval = "1d"
+	KlineInterval3d  KlineInterval = "3d"
+	KlineInterval1w  KlineInterval = "1w"
+	KlineInterval1M  KlineInterval = "1M"
+)
+
+// Validate returns nil if the value is a valid KlineInterval, or an error if not.
+func (k KlineInterval) Validate() error {
+	switch k {
+	case KlineInterval1s:
+	case KlineInterval1m:
+	case KlineInterval3m:
+	case KlineInterval5m:
+	case KlineInterval15m:
+	case KlineInterval30m:
+	case KlineInterval1h:
+	case KlineInterval2h:
+	case KlineInterval4h:
+	case KlineInterval6h:
+	case KlineInterval8h:
+	case KlineInterval12h:
+	case KlineInterval1d:
+	case KlineInterval3d:
+	case KlineInterval1w:
+	case KlineInterval1M:
+	default:
+		return fmt.Errorf("KlineInterval, %q, is not known", k)
+	}
+	return nil
+}
+
+// TickerWindowSize is an enumeration of the window sizes supported by rolling window ticker streams
+type TickerWindowSize string
+
+const (
+	TickerWindowSize1h TickerWindowSize = "1h"
+	TickerWindowSize4h TickerWindowSize = "4h"
+	TickerWindowSize1d TickerWindowSize = "1d"
+)
+
+// Validate returns nil if the value is a valid TickerWindowSize, or an error if not.
+func (w TickerWindowSize) Validate() error {
+	switch w {
+	case TickerWindowSize1h:
+	case TickerWindowSize4h:
+	case TickerWindowSize1d:
+	default:
+		return fmt.Errorf("TickerWindowSize, %q, is not known", w)
+	}
+	return nil
+}
+
+// DepthLevels is an enumeration of the number of levels available from partial book depth streams
+type DepthLevels int
+
+const (
+	DepthLevels5  DepthLevels = 5
+	DepthLevels10 DepthLevels = 10
+	DepthLevels20 DepthLevels = 20
+)
+
+// Validate returns nil if the value is a valid DepthLevels, or an error if not.
+func (d DepthLevels) Validate() error {
+	switch d {
+	case DepthLevels5:
+	case DepthLevels10:
+	case DepthLevels20:
+	default:
+		return fmt.Errorf("DepthLevels, %v, is not known", int(d))
+	}
+	return nil
+}
+
+// DepthUpdateSpeed is an enumeration of the frequencies at which depth streams can push updates
+type DepthUpdateSpeed string
+
+const (
+	// DepthUpdateSpeed1000ms causes depth updates to be pushed every second
+	DepthUpdateSpeed1000ms DepthUpdateSpeed = "1000ms"
+	// DepthUpdateSpeed100ms causes depth updates to be pushed every 100 milliseconds
+	DepthUpdateSpeed100ms DepthUpdateSpeed = "100ms"
+)
+
+// Validate returns nil if the value is a valid DepthUpdateSpeed, or an error if not.
+func (d DepthUpdateSpeed) Validate() error {
+	switch d {
+	case DepthUpdateSpeed1000ms:
+	case DepthUpdateSpeed100ms:
+	default:
+		return fmt.Errorf("DepthUpdateSpeed, %q, is not known", d)
+	}
+	return nil
+}
+
+// ExecutionType is an enumeration of the reasons an execution report is sent on the user data stream
+type ExecutionType string
+
+const (
+	// ExecutionTypeNew indicates the order has been accepted into the engine
+	ExecutionTypeNew ExecutionType = "NEW"
+	// ExecutionTypeCanceled indicates the order has been canceled by the user
+	ExecutionTypeCanceled ExecutionType = "CANCELED"
+	// ExecutionTypeReplaced is currently unused by binance
+	ExecutionTypeReplaced ExecutionType = "REPLACED"
+	// ExecutionTypeRejected indicates the new order has been rejected
+	ExecutionTypeRejected ExecutionType = "REJECTED"
+	// ExecutionTypeTrade indicates part of the order or all of the order's quantity has filled
+	ExecutionTypeTrade ExecutionType = "TRADE"
+	// ExecutionTypeExpired indicates the order was canceled according to the order type's rules or by the exchange
+	ExecutionTypeExpired ExecutionType = "EXPIRED"
+)
+
+// Validate returns nil if the value is a valid ExecutionType, or an error if not.
+func (e ExecutionType) Validate() error {
+	switch e {
+	case ExecutionTypeNew:
+	case ExecutionTypeCanceled:
+	case ExecutionTypeReplaced:
+	case ExecutionTypeRejected:
+	case ExecutionTypeTrade:
+	case ExecutionTypeExpired:
+	default:
+		return fmt.Errorf("ExecutionType, %q, is not known", e)
+	}
+	return nil
+}
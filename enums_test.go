@@ -87,4 +87,107 @@ func TestTimeInForce_Validate(t *testing.T) {
 		TimeInForceImmediateOrCancel,
 		TimeInForceFillOrKill,
 	)
-}
\ No newline at end of file
+}
+func TestKlineInterval_Validate(t *testing.T) {
+	testValidatableEnum(t,
+		KlineInterval("invalid"),
+		KlineInterval1s,
+		KlineInterval1m,
+		KlineInterval3m,
+		KlineInterval5m,
+		KlineInterval15m,
+		KlineInterval30m,
+		KlineInterval1h,
+		KlineInterval2h,
+		KlineInterval4h,
+		KlineInterval6h,
+		KlineInterval8h,
+		KlineInterval12h,
+		KlineInterval1d,
+		KlineInterval3d,
+		KlineInterval1w,
+		KlineInterval1M,
+	)
+}
+
+func TestTickerWindowSize_Validate(t *testing.T) {
+	testValidatableEnum(t,
+		TickerWindowSize("invalid"),
+		TickerWindowSize1h,
+		TickerWindowSize4h,
+		TickerWindowSize1d,
+	)
+}
+
+func TestDepthLevels_Validate(t *testing.T) {
+	testValidatableEnum(t,
+		DepthLevels(7),
+		DepthLevels5,
+		DepthLevels10,
+		DepthLevels20,
+	)
+}
+
+func TestDepthUpdateSpeed_Validate(t *testing.T) {
+	testValidatableEnum(t,
+		DepthUpdateSpeed("invalid"),
+		DepthUpdateSpeed1000ms,
+		DepthUpdateSpeed100ms,
+	)
+}
+
+func TestExecutionType_Validate(t *testing.T) {
+	testValidatableEnum(t,
+		ExecutionType("invalid"),
+		ExecutionTypeNew,
+		ExecutionTypeCanceled,
+		ExecutionTypeReplaced,
+		ExecutionTypeRejected,
+		ExecutionTypeTrade,
+		ExecutionTypeExpired,
+	)
+}
+
+func TestOrderStatus_IsTerminal(t *testing.T) {
+	expected := map[OrderStatus]bool{
+		OrderStatusNew:             false,
+		OrderStatusPartiallyFilled: false,
+		OrderStatusFilled:          true,
+		OrderStatusCanceled:        true,
+		OrderStatusRejected:        true,
+		OrderStatusExpired:         true,
+	}
+	for status, terminal := range expected {
+		if got := status.IsTerminal(); got != terminal {
+			t.Errorf("unexpected IsTerminal for %v. expected %v but got %v", status, terminal, got)
+		}
+	}
+}
+
+func TestOrderStatus_CanTransitionTo(t *testing.T) {
+	allowed := map[OrderStatus][]OrderStatus{
+		OrderStatusNew: {
+			OrderStatusPartiallyFilled, OrderStatusFilled, OrderStatusCanceled, OrderStatusRejected, OrderStatusExpired,
+		},
+		OrderStatusPartiallyFilled: {
+			OrderStatusPartiallyFilled, OrderStatusFilled, OrderStatusCanceled, OrderStatusExpired,
+		},
+	}
+	all := []OrderStatus{
+		OrderStatusNew, OrderStatusPartiallyFilled, OrderStatusFilled, OrderStatusCanceled, OrderStatusRejected,
+		OrderStatusExpired,
+	}
+	for _, from := range all {
+		for _, to := range all {
+			expected := false
+			for _, a := range allowed[from] {
+				if a == to {
+					expected = true
+				}
+			}
+			if got := from.CanTransitionTo(to); got != expected {
+				t.Errorf("unexpected CanTransitionTo from %v to %v. expected %v but got %v", from, to, expected, got)
+			}
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package gobinance
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // errorDTO is the error structure returned by binance in the event of a non-200 response
 type errorDTO struct {
@@ -27,4 +30,32 @@ func (h *HttpError) ErrorCode() int {
 // Error implements the error interface and returns a human-readable description of the error
 func (h *HttpError) Error() string {
 	return fmt.Sprintf("got status %v from binance. error code was %v: %v", h.HttpStatus, h.Code, h.Msg)
+}
+
+// ErrOrderStatusUnknown is matched by errors returned when it is unknown whether an order was placed
+var ErrOrderStatusUnknown = errors.New("order status unknown")
+
+// OrderStatusUnknownError is returned when placing an order using SpotIdempotent fails ambiguously, and querying
+// the order to resolve whether it was placed also fails.  The order may be queried later using ClientOrderID.
+type OrderStatusUnknownError struct {
+	ClientOrderID string
+	// PlaceErr is the error returned when placing the order
+	PlaceErr error
+	// ResolveErr is the error returned when querying the order
+	ResolveErr error
+}
+
+// Is returns true when target is ErrOrderStatusUnknown
+func (o *OrderStatusUnknownError) Is(target error) bool {
+	return target == ErrOrderStatusUnknown
+}
+
+// Unwrap returns the error returned when placing the order
+func (o *OrderStatusUnknownError) Unwrap() error {
+	return o.PlaceErr
+}
+
+// Error implements the error interface and returns a human-readable description of the error
+func (o *OrderStatusUnknownError) Error() string {
+	return fmt.Sprintf("%v: client order id %v. error placing order: %v. error querying order: %v", ErrOrderStatusUnknown, o.ClientOrderID, o.PlaceErr, o.ResolveErr)
 }
\ No newline at end of file
@@ -6,4 +6,5 @@ require (
 	github.com/golang/mock v1.4.4
 	github.com/google/go-cmp v0.5.2
 	github.com/gorilla/websocket v1.4.2
+	golang.org/x/crypto v0.0.0-20211117183948-ae814b36b871
 )
@@ -0,0 +1,62 @@
+package gobinance
+
+import (
+	"context"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const defaultHandshakeTimeout = 45 * time.Second
+
+// GorillaDialer is a DialContexter which makes websocket connections using github.com/gorilla/websocket.  The
+// connections returned implement NextReaderWriterCloser, so may be used with a StreamManager.
+//
+// The zero value is ready to use, connecting directly or via the proxy given by the environment with a handshake
+// timeout of 45 seconds.
+type GorillaDialer struct {
+	// Dialer is used to make connections, for configuration beyond that provided by the other fields.  When set,
+	// HandshakeTimeout, Proxy and EnableCompression are ignored.
+	Dialer *websocket.Dialer
+	// HandshakeTimeout is the maximum duration of the opening handshake.  When zero, 45 seconds is used.
+	HandshakeTimeout time.Duration
+	// Proxy returns the proxy to use for a request, or nil for no proxy.  When nil, http.ProxyFromEnvironment
+	// is used.
+	Proxy func(*http.Request) (*url.URL, error)
+	// EnableCompression requests per message compression from the server
+	EnableCompression bool
+	// ReadLimit is the maximum size in bytes of a message read from a connection.  Reading a larger message
+	// results in an error which closes the connection.  When zero, there is no limit.
+	ReadLimit int64
+}
+
+// DialContext creates a new websocket connection to url
+func (g *GorillaDialer) DialContext(ctx context.Context, url string, hdr http.Header) (NextReaderCloser, *http.Response, error) {
+	conn, resp, err := g.dialer().DialContext(ctx, url, hdr)
+	if err != nil {
+		return nil, resp, err
+	}
+	if g.ReadLimit > 0 {
+		conn.SetReadLimit(g.ReadLimit)
+	}
+	return conn, resp, nil
+}
+
+func (g *GorillaDialer) dialer() *websocket.Dialer {
+	if g.Dialer != nil {
+		return g.Dialer
+	}
+	d := &websocket.Dialer{
+		Proxy:             g.Proxy,
+		HandshakeTimeout:  g.HandshakeTimeout,
+		EnableCompression: g.EnableCompression,
+	}
+	if d.Proxy == nil {
+		d.Proxy = http.ProxyFromEnvironment
+	}
+	if d.HandshakeTimeout == 0 {
+		d.HandshakeTimeout = defaultHandshakeTimeout
+	}
+	return d
+}
@@ -0,0 +1,205 @@
+package gobinance_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	"github.com/gorilla/websocket"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGorillaDialer_DialContext(t *testing.T) {
+	t.Parallel()
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg string
+		switch r.URL.Path {
+		case "/ws/bnbbtc@trade":
+			msg = `{"e":"trade","E":123456789,"s":"BNBBTC","t":12345,"p":"0.001","q":"100","b":88,"a":50,"T":123456785,"m":true,"M":true}`
+		case "/large":
+			msg = strings.Repeat("a", 1024)
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("unexpected error upgrading connection: %v", err)
+			return
+		}
+		defer conn.Close()
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(msg))
+		// wait for the client to close the connection
+		_, _, _ = conn.NextReader()
+	}))
+	t.Cleanup(server.Close)
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	// unresponsive accepts connections but never completes the handshake
+	unresponsive, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = unresponsive.Close() })
+	go func() {
+		for {
+			conn, err := unresponsive.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	testCases := []struct {
+		name  string
+		uut   *gobinance.GorillaDialer
+		url   string
+		check func(t *testing.T, conn gobinance.NextReaderCloser, resp *http.Response, err error)
+	}{
+		{
+			name: "defaults",
+			uut:  &gobinance.GorillaDialer{},
+			url:  wsURL + "/ws/bnbbtc@trade",
+			check: func(t *testing.T, conn gobinance.NextReaderCloser, resp *http.Response, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if _, ok := conn.(gobinance.NextReaderWriterCloser); !ok {
+					t.Errorf("expected connection to implement NextReaderWriterCloser")
+				}
+				if ext := resp.Header.Get("Sec-Websocket-Extensions"); ext != "" {
+					t.Errorf("expected no compression but got extensions %q", ext)
+				}
+			},
+		},
+		{
+			name: "compression",
+			uut:  &gobinance.GorillaDialer{EnableCompression: true},
+			url:  wsURL + "/ws/bnbbtc@trade",
+			check: func(t *testing.T, conn gobinance.NextReaderCloser, resp *http.Response, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if ext := resp.Header.Get("Sec-Websocket-Extensions"); !strings.Contains(ext, "permessage-deflate") {
+					t.Errorf("expected compression to be negotiated but got extensions %q", ext)
+				}
+			},
+		},
+		{
+			name: "read limit",
+			uut:  &gobinance.GorillaDialer{ReadLimit: 512},
+			url:  wsURL + "/large",
+			check: func(t *testing.T, conn gobinance.NextReaderCloser, resp *http.Response, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				_, r, err := conn.NextReader()
+				if err == nil {
+					_, err = ioutil.ReadAll(r)
+				}
+				if err != websocket.ErrReadLimit {
+					t.Errorf("expected ErrReadLimit but got %v", err)
+				}
+			},
+		},
+		{
+			name: "handshake timeout",
+			uut:  &gobinance.GorillaDialer{HandshakeTimeout: 50 * time.Millisecond},
+			url:  "ws://" + unresponsive.Addr().String(),
+			check: func(t *testing.T, conn gobinance.NextReaderCloser, resp *http.Response, err error) {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				if conn != nil {
+					t.Errorf("expected a nil connection but got %#v", conn)
+				}
+			},
+		},
+		{
+			name: "proxy",
+			uut: &gobinance.GorillaDialer{Proxy: func(*http.Request) (*url.URL, error) {
+				return nil, fmt.Errorf("test proxy error")
+			}},
+			url: wsURL + "/ws/bnbbtc@trade",
+			check: func(t *testing.T, conn gobinance.NextReaderCloser, resp *http.Response, err error) {
+				if err == nil || !strings.Contains(err.Error(), "test proxy error") {
+					t.Errorf("expected the proxy to be used but got %v", err)
+				}
+			},
+		},
+		{
+			name: "handshake error",
+			uut:  &gobinance.GorillaDialer{},
+			url:  wsURL + "/not-found",
+			check: func(t *testing.T, conn gobinance.NextReaderCloser, resp *http.Response, err error) {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				if conn != nil {
+					t.Errorf("expected a nil connection but got %#v", conn)
+				}
+				if resp == nil || resp.StatusCode != http.StatusNotFound {
+					t.Errorf("expected the handshake response to be returned but got %v", resp)
+				}
+			},
+		},
+		{
+			name: "custom dialer",
+			uut:  &gobinance.GorillaDialer{Dialer: &websocket.Dialer{EnableCompression: true}},
+			url:  wsURL + "/ws/bnbbtc@trade",
+			check: func(t *testing.T, conn gobinance.NextReaderCloser, resp *http.Response, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if ext := resp.Header.Get("Sec-Websocket-Extensions"); !strings.Contains(ext, "permessage-deflate") {
+					t.Errorf("expected the custom dialer to be used but got extensions %q", ext)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			conn, resp, err := tc.uut.DialContext(context.Background(), tc.url, nil)
+			if conn != nil {
+				defer conn.Close()
+			}
+			tc.check(t, conn, resp, err)
+		})
+	}
+
+	t.Run("trades stream", func(t *testing.T) {
+		t.Parallel()
+		client, err := gobinance.NewClient(gobinance.ClientEnvironment(gobinance.Environment{
+			HTTPApiURL:      mustParseURL(t, server.URL),
+			WebsocketApiURL: mustParseURL(t, wsURL),
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		got := <-client.Trades(ctx, "BNBBTC")
+		if got.Err != nil || got.TradeID != 12345 {
+			t.Errorf("unexpected trade event %#v", got)
+		}
+	})
+}
+
+func mustParseURL(t *testing.T, s string) *url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatalf("unable to parse url %v: %v", s, err)
+	}
+	return u
+}
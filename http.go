@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -15,39 +17,96 @@ const (
 	recvWindowQuery = "recvWindow"
 	userAgentHeader = "User-Agent"
 	apiKeyHeader    = "X-MBX-APIKEY"
+
+	contentTypeHeader = "Content-Type"
+	formContentType   = "application/x-www-form-urlencoded"
 )
 
 func (c *Client) buildUnsignedRequest(ctx context.Context, method string, path string, parameters url.Values, includeAPIKey bool) (*http.Request, error) {
+	if !includeAPIKey {
+		return c.newRequest(ctx, method, path, parameters, "", nil)
+	}
+	creds, err := c.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.newRequest(ctx, method, path, parameters, "", &creds)
+}
+
+func (c *Client) buildSignedRequest(ctx context.Context, method string, path string, parameters url.Values) (*http.Request, error) {
+	creds, err := c.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if creds.Signer == nil {
+		return nil, fmt.Errorf("a Signer is required for authenticated requests")
+	}
+	if parameters == nil {
+		parameters = make(url.Values)
+	}
+	parameters.Set(timestampQuery, fmt.Sprint(c.Now().UnixNano()/int64(time.Millisecond)))
+	if parameters.Get(recvWindowQuery) == "" && c.RecvWindow > 0 {
+		parameters.Set(recvWindowQuery, fmt.Sprint(c.RecvWindow.Milliseconds()))
+	}
+
+	query, body := c.signedRequestEncoding(ctx, method).split(parameters)
+	if body == nil {
+		signature := creds.Signer.Sign(query.Encode())
+		// the signature is escaped along with the other parameters, as base64 signatures may contain '+', '/' and '='
+		query.Set(signatureQuery, signature)
+		return c.newRequest(ctx, method, path, query, "", &creds)
+	}
+
+	// binance signs the query string followed immediately by the body, and expects the signature at the end
+	encodedBody := body.Encode()
+	signature := creds.Signer.Sign(query.Encode() + encodedBody)
+	if encodedBody != "" {
+		encodedBody += "&"
+	}
+	encodedBody += signatureQuery + "=" + url.QueryEscape(signature)
+	return c.newRequest(ctx, method, path, query, encodedBody, &creds)
+}
+
+// newRequest builds a request to `path` with the query string given by `parameters`.  When `body` is not empty,
+// it is sent as an `application/x-www-form-urlencoded` body.  When `creds` is not nil, the API key header is set.
+func (c *Client) newRequest(ctx context.Context, method string, path string, parameters url.Values, body string, creds *Credentials) (*http.Request, error) {
 	u := c.HTTPApiURL.ResolveReference(&url.URL{
 		Path:     path,
 		RawQuery: parameters.Encode(),
 	})
 
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	var bodyReader io.Reader
+	if body != "" {
+		bodyReader = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("error building request")
 	}
 
 	req.Header.Set(userAgentHeader, c.UserAgent)
-	if includeAPIKey {
-		req.Header.Set(apiKeyHeader, c.APIKey)
+	if body != "" {
+		req.Header.Set(contentTypeHeader, formContentType)
+	}
+	if creds != nil {
+		req.Header.Set(apiKeyHeader, creds.APIKey)
 	}
 
 	return req, nil
 }
 
-func (c *Client) buildSignedRequest(ctx context.Context, method string, path string, parameters url.Values) (*http.Request, error) {
-	if parameters == nil {
-		parameters = make(url.Values)
+// credentials returns the API key and Signer used to authenticate requests, from the CredentialsProvider if one is
+// set, or the APIKey and Signer fields otherwise.  The same credentials must be used for the API key header and
+// signature of a request, so they are fetched once per request.
+func (c *Client) credentials(ctx context.Context) (Credentials, error) {
+	if c.CredentialsProvider == nil {
+		return Credentials{APIKey: c.APIKey, Signer: c.Signer}, nil
 	}
-	parameters.Set(timestampQuery, fmt.Sprint(c.Now().UnixNano()/int64(time.Millisecond)))
-	if parameters.Get(recvWindowQuery) == "" && c.RecvWindow > 0 {
-		parameters.Set(recvWindowQuery, fmt.Sprint(c.RecvWindow.Milliseconds()))
+	creds, err := c.CredentialsProvider.Credentials(ctx)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("error loading credentials: %w", err)
 	}
-	signature := c.Signer.Sign(parameters.Encode())
-	parameters.Set(signatureQuery, signature)
-
-	return c.buildUnsignedRequest(ctx, method, path, parameters, true)
+	return creds, nil
 }
 
 // performRequest executes the request in req using the doer.  If the host returns a non-200 status, an `HttpError`
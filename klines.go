@@ -0,0 +1,131 @@
+package gobinance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Kline holds the data of a single kline / candlestick
+type Kline struct {
+	OpenTime            time.Time
+	CloseTime           time.Time
+	Open                *big.Float
+	High                *big.Float
+	Low                 *big.Float
+	Close               *big.Float
+	Volume              *big.Float
+	QuoteVolume         *big.Float
+	NumberOfTrades      int64
+	TakerBuyBaseVolume  *big.Float
+	TakerBuyQuoteVolume *big.Float
+}
+
+// KlineEvent define websocket kline event
+type KlineEvent struct {
+	Event        string
+	Time         time.Time
+	Symbol       string
+	Interval     KlineInterval
+	FirstTradeID int64
+	LastTradeID  int64
+	// IsClosed is true when this is the final update for the kline
+	IsClosed bool
+	Kline
+}
+
+// UnmarshalJSON provides custom unmarshalling for KlineEvents.
+func (k *KlineEvent) UnmarshalJSON(bs []byte) error {
+	var tmp struct {
+		Event  string          `json:"e"`
+		Time   millisTimestamp `json:"E"`
+		Symbol string          `json:"s"`
+		Kline  struct {
+			OpenTime            millisTimestamp `json:"t"`
+			CloseTime           millisTimestamp `json:"T"`
+			Interval            KlineInterval   `json:"i"`
+			FirstTradeID        int64           `json:"f"`
+			LastTradeID         int64           `json:"L"`
+			Open                *big.Float      `json:"o"`
+			Close               *big.Float      `json:"c"`
+			High                *big.Float      `json:"h"`
+			Low                 *big.Float      `json:"l"`
+			Volume              *big.Float      `json:"v"`
+			NumberOfTrades      int64           `json:"n"`
+			IsClosed            bool            `json:"x"`
+			QuoteVolume         *big.Float      `json:"q"`
+			TakerBuyBaseVolume  *big.Float      `json:"V"`
+			TakerBuyQuoteVolume *big.Float      `json:"Q"`
+		} `json:"k"`
+	}
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	*k = KlineEvent{
+		Event:        tmp.Event,
+		Time:         time.Time(tmp.Time),
+		Symbol:       tmp.Symbol,
+		Interval:     tmp.Kline.Interval,
+		FirstTradeID: tmp.Kline.FirstTradeID,
+		LastTradeID:  tmp.Kline.LastTradeID,
+		IsClosed:     tmp.Kline.IsClosed,
+		Kline: Kline{
+			OpenTime:            time.Time(tmp.Kline.OpenTime),
+			CloseTime:           time.Time(tmp.Kline.CloseTime),
+			Open:                tmp.Kline.Open,
+			High:                tmp.Kline.High,
+			Low:                 tmp.Kline.Low,
+			Close:               tmp.Kline.Close,
+			Volume:              tmp.Kline.Volume,
+			QuoteVolume:         tmp.Kline.QuoteVolume,
+			NumberOfTrades:      tmp.Kline.NumberOfTrades,
+			TakerBuyBaseVolume:  tmp.Kline.TakerBuyBaseVolume,
+			TakerBuyQuoteVolume: tmp.Kline.TakerBuyQuoteVolume,
+		},
+	}
+	return nil
+}
+
+// KlineEventOrError is a union of KlineEvent or error
+type KlineEventOrError struct {
+	KlineEvent
+	Err error
+}
+
+// KlineStream initiates a websocket connection to binance and returns a channel from which updates to the current
+// kline of the given interval are streamed.  The channel is closed when the underlying context is cancelled, or upon
+// a connection error or the server closing the connection.
+func (c *Client) KlineStream(ctx context.Context, symbol string, interval KlineInterval) <-chan KlineEventOrError {
+	out := make(chan KlineEventOrError, 1)
+	if err := interval.Validate(); err != nil {
+		out <- KlineEventOrError{Err: err}
+		close(out)
+		return out
+	}
+
+	handle := func(reader io.Reader, err error) error {
+		if err != nil {
+			out <- KlineEventOrError{Err: err}
+			return nil
+		}
+		var kline KlineEvent
+		dec := json.NewDecoder(reader)
+		if err := dec.Decode(&kline); err != nil {
+			out <- KlineEventOrError{Err: fmt.Errorf("error decoding kline event: %w", err)}
+			return err
+		}
+		out <- KlineEventOrError{KlineEvent: kline}
+		return nil
+	}
+	path := fmt.Sprintf("/ws/%s@kline_%s", url.PathEscape(strings.ToLower(symbol)), interval)
+
+	go c.openWebsocket(ctx, path, handle, func() {
+		close(out)
+	})
+	return out
+}
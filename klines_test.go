@@ -0,0 +1,118 @@
+package gobinance_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestClient_KlineStream(t *testing.T) {
+	t.Parallel()
+	const BaseURL = "wss://example.com"
+	testError := fmt.Errorf("test error")
+
+	testCases := []struct {
+		name         string
+		interval     gobinance.KlineInterval
+		setup        func(*mock_gobinance.MockDialContexter, *mock_gobinance.MockNextReaderCloser)
+		expectations func(*testing.T, []gobinance.KlineEventOrError)
+	}{
+		{
+			name:     "invalid interval",
+			interval: gobinance.KlineInterval("2m"),
+			setup:    func(*mock_gobinance.MockDialContexter, *mock_gobinance.MockNextReaderCloser) {},
+			expectations: func(t *testing.T, events []gobinance.KlineEventOrError) {
+				if l := len(events); l != 1 {
+					t.Fatalf("expected 1 event but got %v", l)
+				}
+				if events[0].Err == nil {
+					t.Errorf("expected an error but got nil")
+				}
+			},
+		},
+		{
+			name:     "kline information is received",
+			interval: gobinance.KlineInterval1m,
+			setup: func(dialer *mock_gobinance.MockDialContexter, con *mock_gobinance.MockNextReaderCloser) {
+				dialer.EXPECT().DialContext(
+					gomock.Not(gomock.Nil()),
+					BaseURL+"/ws/bnbbtc@kline_1m",
+					nil).Return(con, nil, nil)
+				con.EXPECT().Close()
+				first := con.EXPECT().NextReader().Return(0,
+					bytes.NewBufferString(`{"e":"kline","E":123456789,"s":"BNBBTC","k":{"t":123400000,"T":123460000,"s":"BNBBTC","i":"1m","f":100,"L":200,"o":"0.0010","c":"0.0020","h":"0.0025","l":"0.0015","v":"1000","n":100,"x":true,"q":"1.0000","V":"500","Q":"0.500","B":"123456"}}`),
+					nil,
+				)
+				con.EXPECT().NextReader().Return(0, nil, testError).After(first)
+			},
+			expectations: func(t *testing.T, events []gobinance.KlineEventOrError) {
+				if l := len(events); l != 2 {
+					t.Fatalf("expected 2 events but got %v", l)
+				}
+				if !errors.Is(events[1].Err, testError) {
+					t.Errorf("expected error to extend from\n\t%#v\nbut got\n\t%#v", testError, events[1].Err)
+				}
+				expected := gobinance.KlineEventOrError{
+					KlineEvent: gobinance.KlineEvent{
+						Event:        "kline",
+						Time:         time.Date(1970, 01, 02, 10, 17, 36, int(789*time.Millisecond), time.UTC),
+						Symbol:       "BNBBTC",
+						Interval:     gobinance.KlineInterval1m,
+						FirstTradeID: 100,
+						LastTradeID:  200,
+						IsClosed:     true,
+						Kline: gobinance.Kline{
+							OpenTime:            time.Date(1970, 01, 02, 10, 16, 40, 0, time.UTC),
+							CloseTime:           time.Date(1970, 01, 02, 10, 17, 40, 0, time.UTC),
+							Open:                mustParseBigFloat(t, "0.0010"),
+							High:                mustParseBigFloat(t, "0.0025"),
+							Low:                 mustParseBigFloat(t, "0.0015"),
+							Close:               mustParseBigFloat(t, "0.0020"),
+							Volume:              mustParseBigFloat(t, "1000"),
+							QuoteVolume:         mustParseBigFloat(t, "1.0000"),
+							NumberOfTrades:      100,
+							TakerBuyBaseVolume:  mustParseBigFloat(t, "500"),
+							TakerBuyQuoteVolume: mustParseBigFloat(t, "0.500"),
+						},
+					},
+				}
+				if diff := cmp.Diff(expected, events[0], bigFloatComparer); diff != "" {
+					t.Errorf("unexpected event.  %s", diff)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDialer := mock_gobinance.NewMockDialContexter(ctrl)
+			mockNextReader := mock_gobinance.NewMockNextReaderCloser(ctrl)
+			tc.setup(mockDialer, mockNextReader)
+
+			baseURL, _ := url.Parse(BaseURL)
+			uut := &gobinance.Client{
+				WebsocketApiURL: baseURL,
+				DialContexter:   mockDialer,
+			}
+
+			var got []gobinance.KlineEventOrError
+			for kline := range uut.KlineStream(context.Background(), "BNBBTC", tc.interval) {
+				got = append(got, kline)
+			}
+			tc.expectations(t, got)
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package gobinance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLocalOrderBookSnapshotLimit = 1000
+	defaultLocalOrderBookResyncDelay   = time.Second
+	// defaultLocalOrderBookSnapshotRetryDelay is the delay before refetching a snapshot which was older than the
+	// first buffered update.  It doubles upon each further attempt.
+	defaultLocalOrderBookSnapshotRetryDelay = 250 * time.Millisecond
+	// maxLocalOrderBookSnapshotAttempts limits how many snapshots are fetched in a single synchronisation, as each
+	// carries a heavy request weight
+	maxLocalOrderBookSnapshotAttempts = 5
+)
+
+// LocalOrderBookOption is a function that applies optional configuration to a LocalOrderBook
+type LocalOrderBookOption func(b *LocalOrderBook)
+
+// LocalOrderBookSpeed sets the speed of the depth update stream used to maintain the order book.  The default
+// is DepthUpdateSpeed100ms.
+func LocalOrderBookSpeed(speed DepthUpdateSpeed) LocalOrderBookOption {
+	return func(b *LocalOrderBook) {
+		b.speed = speed
+	}
+}
+
+// LocalOrderBookSnapshotLimit sets the number of levels on each side of the book fetched in the depth snapshot.
+// The default is 1000.
+func LocalOrderBookSnapshotLimit(limit int) LocalOrderBookOption {
+	return func(b *LocalOrderBook) {
+		b.snapshotLimit = limit
+	}
+}
+
+// LocalOrderBookResyncDelay sets how long to wait before resynchronising the order book after an error.
+// The default is 1 second.
+func LocalOrderBookResyncDelay(d time.Duration) LocalOrderBookOption {
+	return func(b *LocalOrderBook) {
+		b.resyncDelay = d
+	}
+}
+
+// LocalOrderBookSnapshotRetryDelay sets how long to wait before refetching a snapshot which is older than the
+// first buffered update.  The delay doubles upon each further attempt, and synchronisation fails after 5 attempts.
+// The default is 250 milliseconds.
+func LocalOrderBookSnapshotRetryDelay(d time.Duration) LocalOrderBookOption {
+	return func(b *LocalOrderBook) {
+		b.snapshotRetryDelay = d
+	}
+}
+
+// LocalOrderBookErrorHandler sets a function to be called with each error that causes the order book to be
+// resynchronised, such as a gap in update IDs or the stream being disconnected.
+func LocalOrderBookErrorHandler(handle func(err error)) LocalOrderBookOption {
+	return func(b *LocalOrderBook) {
+		b.handleErr = handle
+	}
+}
+
+// LocalOrderBook maintains a copy of a symbol's order book, kept up to date using binance's diff depth stream
+// following the procedure described in binance's documentation.
+//
+// All methods are safe to call concurrently.  While the book is being resynchronised, it holds the last known
+// state and Synced returns false.
+type LocalOrderBook struct {
+	client        *Client
+	symbol        string
+	speed         DepthUpdateSpeed
+	snapshotLimit int
+	resyncDelay   time.Duration
+	// snapshotRetryDelay is the initial delay between attempts to fetch a snapshot
+	snapshotRetryDelay time.Duration
+	handleErr          func(err error)
+	changes            chan struct{}
+
+	mu           sync.RWMutex
+	synced       bool
+	lastUpdateID int64
+	// bids are sorted by descending price, asks by ascending price
+	bids []PriceLevel
+	asks []PriceLevel
+}
+
+// NewLocalOrderBook creates a LocalOrderBook for the given symbol.  The book is empty until Run is called.
+func (c *Client) NewLocalOrderBook(symbol string, opts ...LocalOrderBookOption) *LocalOrderBook {
+	b := &LocalOrderBook{
+		client:             c,
+		symbol:             symbol,
+		speed:              DepthUpdateSpeed100ms,
+		snapshotLimit:      defaultLocalOrderBookSnapshotLimit,
+		resyncDelay:        defaultLocalOrderBookResyncDelay,
+		snapshotRetryDelay: defaultLocalOrderBookSnapshotRetryDelay,
+		handleErr:          func(error) {},
+		changes:            make(chan struct{}, 1),
+	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+// Run synchronises the order book and keeps it up to date, automatically resynchronising upon gaps in the
+// update stream or reconnection.  It blocks until the context is cancelled, returning the context's error.
+func (b *LocalOrderBook) Run(ctx context.Context) error {
+	for {
+		err := b.sync(ctx)
+		b.mu.Lock()
+		b.synced = false
+		b.mu.Unlock()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		b.handleErr(err)
+
+		t := time.NewTimer(b.resyncDelay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Changes returns a channel which receives a value whenever the order book changes.  Notifications are
+// coalesced, so a single notification may represent several changes.
+func (b *LocalOrderBook) Changes() <-chan struct{} {
+	return b.changes
+}
+
+// Synced returns true when the order book is synchronised with binance
+func (b *LocalOrderBook) Synced() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.synced
+}
+
+// LastUpdateID returns the ID of the last update applied to the order book
+func (b *LocalOrderBook) LastUpdateID() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lastUpdateID
+}
+
+// BestBid returns the highest bid on the order book, or false if there are no bids
+func (b *LocalOrderBook) BestBid() (PriceLevel, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if len(b.bids) == 0 {
+		return PriceLevel{}, false
+	}
+	return copyPriceLevel(b.bids[0]), true
+}
+
+// BestAsk returns the lowest ask on the order book, or false if there are no asks
+func (b *LocalOrderBook) BestAsk() (PriceLevel, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if len(b.asks) == 0 {
+		return PriceLevel{}, false
+	}
+	return copyPriceLevel(b.asks[0]), true
+}
+
+// Bids returns up to n of the highest bids on the order book, highest first
+func (b *LocalOrderBook) Bids(n int) []PriceLevel {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return copyPriceLevels(b.bids, n)
+}
+
+// Asks returns up to n of the lowest asks on the order book, lowest first
+func (b *LocalOrderBook) Asks(n int) []PriceLevel {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return copyPriceLevels(b.asks, n)
+}
+
+// DepthAt returns the quantity on the order book at exactly the given price.  Bids are checked when side is
+// OrderSideBuy, asks when it is OrderSideSell.  Zero is returned when there are no orders at that price.
+func (b *LocalOrderBook) DepthAt(side OrderSide, price *big.Float) *big.Float {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	levels, descending := b.asks, false
+	if side == OrderSideBuy {
+		levels, descending = b.bids, true
+	}
+	if i, found := searchPriceLevels(levels, price, descending); found {
+		return new(big.Float).Copy(levels[i].Quantity)
+	}
+	return new(big.Float)
+}
+
+// sync follows binance's procedure for synchronising a local order book, then applies updates from the stream
+// until an error occurs.
+func (b *LocalOrderBook) sync(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// every update must be applied in order, so the stream is never allowed to drop messages
+	updates := b.client.DepthUpdates(WithStreamBuffer(ctx, StreamBuffer{}), b.symbol, b.speed)
+	defer func() {
+		// stop the stream and wait for it to shut down, so its connection is closed before returning
+		cancel()
+		for range updates {
+		}
+	}()
+
+	first, err := nextDepthUpdate(ctx, updates)
+	if err != nil {
+		return err
+	}
+	var snapshot OrderBookDepth
+	delay := b.snapshotRetryDelay
+	for attempt := 1; ; attempt++ {
+		snapshot, err = b.client.OrderBookDepth(ctx, b.symbol, OrderBookDepthLimit(b.snapshotLimit))
+		if err != nil {
+			return fmt.Errorf("error fetching order book snapshot: %w", err)
+		}
+		// the snapshot must not be older than the first buffered update, otherwise updates in between are missing
+		if snapshot.LastUpdateID+1 >= first.FirstUpdateID {
+			break
+		}
+		if attempt == maxLocalOrderBookSnapshotAttempts {
+			return fmt.Errorf("order book snapshot at update %v is still older than the first buffered update %v after %v attempts",
+				snapshot.LastUpdateID, first.FirstUpdateID, attempt)
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+	b.setSnapshot(snapshot)
+
+	update := first
+	for {
+		if err := b.apply(update); err != nil {
+			return err
+		}
+		update, err = nextDepthUpdate(ctx, updates)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func nextDepthUpdate(ctx context.Context, updates <-chan DepthUpdateEventOrError) (DepthUpdateEvent, error) {
+	select {
+	case update, ok := <-updates:
+		if !ok {
+			return DepthUpdateEvent{}, fmt.Errorf("depth update stream closed")
+		}
+		if update.Err != nil {
+			return DepthUpdateEvent{}, fmt.Errorf("error from depth update stream: %w", update.Err)
+		}
+		return update.DepthUpdateEvent, nil
+	case <-ctx.Done():
+		return DepthUpdateEvent{}, ctx.Err()
+	}
+}
+
+func (b *LocalOrderBook) setSnapshot(snapshot OrderBookDepth) {
+	b.mu.Lock()
+	b.lastUpdateID = snapshot.LastUpdateID
+	b.bids = nil
+	b.asks = nil
+	for _, level := range snapshot.Bids {
+		b.bids = updatePriceLevels(b.bids, level, true)
+	}
+	for _, level := range snapshot.Asks {
+		b.asks = updatePriceLevels(b.asks, level, false)
+	}
+	b.synced = true
+	b.mu.Unlock()
+	b.notify()
+}
+
+// apply applies a depth update to the book.  Updates which have already been applied are ignored, and an error
+// is returned if updates have been missed.
+func (b *LocalOrderBook) apply(update DepthUpdateEvent) error {
+	b.mu.Lock()
+	if update.FinalUpdateID <= b.lastUpdateID {
+		b.mu.Unlock()
+		return nil
+	}
+	if update.FirstUpdateID > b.lastUpdateID+1 {
+		defer b.mu.Unlock()
+		return fmt.Errorf("gap in depth updates: expected update %v but got %v", b.lastUpdateID+1, update.FirstUpdateID)
+	}
+	for _, level := range update.Bids {
+		b.bids = updatePriceLevels(b.bids, level, true)
+	}
+	for _, level := range update.Asks {
+		b.asks = updatePriceLevels(b.asks, level, false)
+	}
+	b.lastUpdateID = update.FinalUpdateID
+	b.mu.Unlock()
+	b.notify()
+	return nil
+}
+
+func (b *LocalOrderBook) notify() {
+	select {
+	case b.changes <- struct{}{}:
+	default:
+	}
+}
+
+// searchPriceLevels returns the index of price in the sorted levels, and whether it was found.  When not
+// found, the index is where the price should be inserted.
+func searchPriceLevels(levels []PriceLevel, price *big.Float, descending bool) (int, bool) {
+	i := sort.Search(len(levels), func(i int) bool {
+		if descending {
+			return levels[i].Price.Cmp(price) <= 0
+		}
+		return levels[i].Price.Cmp(price) >= 0
+	})
+	return i, i < len(levels) && levels[i].Price.Cmp(price) == 0
+}
+
+// updatePriceLevels sets the quantity of a price level in the sorted levels, removing it when the quantity is zero
+func updatePriceLevels(levels []PriceLevel, level PriceLevel, descending bool) []PriceLevel {
+	i, found := searchPriceLevels(levels, level.Price, descending)
+	remove := level.Quantity.Sign() == 0
+	switch {
+	case found && remove:
+		return append(levels[:i], levels[i+1:]...)
+	case found:
+		levels[i] = level
+	case !remove:
+		levels = append(levels, PriceLevel{})
+		copy(levels[i+1:], levels[i:])
+		levels[i] = level
+	}
+	return levels
+}
+
+func copyPriceLevel(level PriceLevel) PriceLevel {
+	return PriceLevel{
+		Price:    new(big.Float).Copy(level.Price),
+		Quantity: new(big.Float).Copy(level.Quantity),
+	}
+}
+
+func copyPriceLevels(levels []PriceLevel, n int) []PriceLevel {
+	if n > len(levels) {
+		n = len(levels)
+	}
+	if n < 0 {
+		n = 0
+	}
+	out := make([]PriceLevel, n)
+	for i := range out {
+		out[i] = copyPriceLevel(levels[i])
+	}
+	return out
+}
@@ -0,0 +1,286 @@
+package gobinance_test
+
+import (
+	"context"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalOrderBook_Run(t *testing.T) {
+	t.Parallel()
+	const snapshot = `{"lastUpdateId":160,"bids":[["0.0024","10"],["0.0022","5"]],"asks":[["0.0026","100"],["0.0027","20"]]}`
+
+	type expectedBook struct {
+		lastUpdateID int64
+		bids         []gobinance.PriceLevel
+		asks         []gobinance.PriceLevel
+	}
+
+	testCases := []struct {
+		name          string
+		snapshots     []string
+		messages      []string
+		expectedError string
+		expected      expectedBook
+	}{
+		{
+			name:      "stale updates are discarded and later updates applied",
+			snapshots: []string{snapshot},
+			messages: []string{
+				`{"e":"depthUpdate","E":1,"s":"BNBBTC","U":157,"u":160,"b":[["0.0024","999"]],"a":[]}`,
+				`{"e":"depthUpdate","E":1,"s":"BNBBTC","U":159,"u":162,"b":[["0.0023","7"],["0.0022","0"]],"a":[["0.0025","1"]]}`,
+				`{"e":"depthUpdate","E":1,"s":"BNBBTC","U":163,"u":163,"b":[],"a":[["0.0026","0"],["0.0027","25"]]}`,
+			},
+			expectedError: "depth update stream",
+			expected: expectedBook{
+				lastUpdateID: 163,
+				bids: []gobinance.PriceLevel{
+					{Price: mustParseBigFloat(t, "0.0024"), Quantity: mustParseBigFloat(t, "10")},
+					{Price: mustParseBigFloat(t, "0.0023"), Quantity: mustParseBigFloat(t, "7")},
+				},
+				asks: []gobinance.PriceLevel{
+					{Price: mustParseBigFloat(t, "0.0025"), Quantity: mustParseBigFloat(t, "1")},
+					{Price: mustParseBigFloat(t, "0.0027"), Quantity: mustParseBigFloat(t, "25")},
+				},
+			},
+		},
+		{
+			name:      "gap in updates",
+			snapshots: []string{snapshot},
+			messages: []string{
+				`{"e":"depthUpdate","E":1,"s":"BNBBTC","U":161,"u":162,"b":[["0.0023","7"]],"a":[]}`,
+				`{"e":"depthUpdate","E":1,"s":"BNBBTC","U":164,"u":165,"b":[["0.0021","7"]],"a":[]}`,
+			},
+			expectedError: "gap in depth updates",
+			expected: expectedBook{
+				lastUpdateID: 162,
+				bids: []gobinance.PriceLevel{
+					{Price: mustParseBigFloat(t, "0.0024"), Quantity: mustParseBigFloat(t, "10")},
+					{Price: mustParseBigFloat(t, "0.0023"), Quantity: mustParseBigFloat(t, "7")},
+					{Price: mustParseBigFloat(t, "0.0022"), Quantity: mustParseBigFloat(t, "5")},
+				},
+				asks: []gobinance.PriceLevel{
+					{Price: mustParseBigFloat(t, "0.0026"), Quantity: mustParseBigFloat(t, "100")},
+					{Price: mustParseBigFloat(t, "0.0027"), Quantity: mustParseBigFloat(t, "20")},
+				},
+			},
+		},
+		{
+			name: "snapshot older than first update is refetched",
+			snapshots: []string{
+				`{"lastUpdateId":100,"bids":[],"asks":[]}`,
+				snapshot,
+			},
+			messages: []string{
+				`{"e":"depthUpdate","E":1,"s":"BNBBTC","U":150,"u":161,"b":[],"a":[["0.0026","90"]]}`,
+			},
+			expectedError: "depth update stream",
+			expected: expectedBook{
+				lastUpdateID: 161,
+				bids: []gobinance.PriceLevel{
+					{Price: mustParseBigFloat(t, "0.0024"), Quantity: mustParseBigFloat(t, "10")},
+					{Price: mustParseBigFloat(t, "0.0022"), Quantity: mustParseBigFloat(t, "5")},
+				},
+				asks: []gobinance.PriceLevel{
+					{Price: mustParseBigFloat(t, "0.0026"), Quantity: mustParseBigFloat(t, "90")},
+					{Price: mustParseBigFloat(t, "0.0027"), Quantity: mustParseBigFloat(t, "20")},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDoer := mock_gobinance.NewMockDoer(ctrl)
+			var prev *gomock.Call
+			for _, s := range tc.snapshots {
+				call := mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(s string) func(*http.Request) (*http.Response, error) {
+					return func(req *http.Request) (*http.Response, error) {
+						expected := url.Values{"symbol": {"BNBBTC"}, "limit": {"1000"}}
+						if diff := cmp.Diff(expected, req.URL.Query()); diff != "" {
+							t.Errorf("unexpected parameters passed to request:\n%v", diff)
+						}
+						return &http.Response{
+							StatusCode: 200,
+							Body:       ioutil.NopCloser(strings.NewReader(s)),
+						}, nil
+					}
+				}(s))
+				if prev != nil {
+					call.After(prev)
+				}
+				prev = call
+			}
+
+			httpURL, _ := url.Parse(testBaseURL)
+			wsURL, _ := url.Parse(testWebsocketBaseURL)
+			client := &gobinance.Client{
+				HTTPApiURL:      httpURL,
+				WebsocketApiURL: wsURL,
+				Doer:            mockDoer,
+				DialContexter:   mockWebsocketMessages(ctrl, testWebsocketBaseURL+"/ws/bnbbtc@depth@100ms", tc.messages...),
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var gotErr error
+			uut := client.NewLocalOrderBook("BNBBTC", gobinance.LocalOrderBookSnapshotRetryDelay(time.Millisecond),
+				gobinance.LocalOrderBookErrorHandler(func(err error) {
+					gotErr = err
+					cancel()
+				}))
+			if err := uut.Run(ctx); err != context.Canceled {
+				t.Errorf("expected context.Canceled but got %v", err)
+			}
+
+			if gotErr == nil || !strings.Contains(gotErr.Error(), tc.expectedError) {
+				t.Errorf("expected error containing %q but got %v", tc.expectedError, gotErr)
+			}
+			if uut.Synced() {
+				t.Errorf("expected book not to be synced after an error")
+			}
+			if got := uut.LastUpdateID(); got != tc.expected.lastUpdateID {
+				t.Errorf("unexpected last update ID. expected %v but got %v", tc.expected.lastUpdateID, got)
+			}
+			if diff := cmp.Diff(tc.expected.bids, uut.Bids(10), bigFloatComparer); diff != "" {
+				t.Errorf("unexpected bids.\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expected.asks, uut.Asks(10), bigFloatComparer); diff != "" {
+				t.Errorf("unexpected asks.\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expected.bids[:1], uut.Bids(1), bigFloatComparer); diff != "" {
+				t.Errorf("unexpected top bid.\n%s", diff)
+			}
+			if got := uut.Bids(-1); len(got) != 0 {
+				t.Errorf("expected no bids for a negative count but got %v", got)
+			}
+			if best, ok := uut.BestBid(); !ok || best.Price.Cmp(tc.expected.bids[0].Price) != 0 {
+				t.Errorf("unexpected best bid %v", best)
+			}
+			if best, ok := uut.BestAsk(); !ok || best.Price.Cmp(tc.expected.asks[0].Price) != 0 {
+				t.Errorf("unexpected best ask %v", best)
+			}
+			for _, level := range tc.expected.asks {
+				if got := uut.DepthAt(gobinance.OrderSideSell, level.Price); got.Cmp(level.Quantity) != 0 {
+					t.Errorf("unexpected depth at %v. expected %v but got %v", level.Price, level.Quantity, got)
+				}
+			}
+			if got := uut.DepthAt(gobinance.OrderSideBuy, mustParseBigFloat(t, "1")); got.Sign() != 0 {
+				t.Errorf("expected no depth but got %v", got)
+			}
+			select {
+			case <-uut.Changes():
+			default:
+				t.Errorf("expected a change notification")
+			}
+		})
+	}
+}
+
+func TestLocalOrderBook_StaleSnapshots(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDoer := mock_gobinance.NewMockDoer(ctrl)
+	mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"lastUpdateId":100,"bids":[],"asks":[]}`)),
+		}, nil
+	}).Times(5)
+
+	httpURL, _ := url.Parse(testBaseURL)
+	wsURL, _ := url.Parse(testWebsocketBaseURL)
+	client := &gobinance.Client{
+		HTTPApiURL:      httpURL,
+		WebsocketApiURL: wsURL,
+		Doer:            mockDoer,
+		DialContexter: mockWebsocketMessages(ctrl, testWebsocketBaseURL+"/ws/bnbbtc@depth@100ms",
+			`{"e":"depthUpdate","E":1,"s":"BNBBTC","U":150,"u":161,"b":[],"a":[["0.0026","90"]]}`),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var gotErr error
+	uut := client.NewLocalOrderBook("BNBBTC", gobinance.LocalOrderBookSnapshotRetryDelay(time.Millisecond),
+		gobinance.LocalOrderBookErrorHandler(func(err error) {
+			gotErr = err
+			cancel()
+		}))
+	start := time.Now()
+	if err := uut.Run(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled but got %v", err)
+	}
+	if gotErr == nil || !strings.Contains(gotErr.Error(), "after 5 attempts") {
+		t.Errorf("expected the snapshot attempts to be exhausted but got %v", gotErr)
+	}
+	// the delays of 1, 2, 4 and 8 milliseconds between attempts
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("expected snapshots to be refetched with a backoff but took %v", elapsed)
+	}
+	if uut.Synced() {
+		t.Errorf("expected book not to be synced")
+	}
+}
+
+func TestLocalOrderBook_LossyClientStreamBuffer(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDoer := mock_gobinance.NewMockDoer(ctrl)
+	mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		// allow the updates to be read from the connection while the snapshot is fetched
+		time.Sleep(50 * time.Millisecond)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"lastUpdateId":160,"bids":[],"asks":[]}`)),
+		}, nil
+	})
+
+	httpURL, _ := url.Parse(testBaseURL)
+	wsURL, _ := url.Parse(testWebsocketBaseURL)
+	client := &gobinance.Client{
+		HTTPApiURL:      httpURL,
+		WebsocketApiURL: wsURL,
+		Doer:            mockDoer,
+		DialContexter: mockWebsocketMessages(ctrl, testWebsocketBaseURL+"/ws/bnbbtc@depth@100ms",
+			`{"e":"depthUpdate","E":1,"s":"BNBBTC","U":159,"u":161,"b":[],"a":[]}`,
+			`{"e":"depthUpdate","E":1,"s":"BNBBTC","U":162,"u":162,"b":[],"a":[]}`,
+			`{"e":"depthUpdate","E":1,"s":"BNBBTC","U":163,"u":163,"b":[],"a":[]}`,
+			`{"e":"depthUpdate","E":1,"s":"BNBBTC","U":164,"u":164,"b":[],"a":[]}`,
+		),
+		StreamBuffer: gobinance.StreamBuffer{Policy: gobinance.BackpressureDropNewest},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var gotErr error
+	uut := client.NewLocalOrderBook("BNBBTC", gobinance.LocalOrderBookErrorHandler(func(err error) {
+		gotErr = err
+		cancel()
+	}))
+	if err := uut.Run(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled but got %v", err)
+	}
+	if gotErr == nil || !strings.Contains(gotErr.Error(), "depth update stream") {
+		t.Errorf("expected the stream to end without a gap but got %v", gotErr)
+	}
+	if got := uut.LastUpdateID(); got != 164 {
+		t.Errorf("expected every update to be applied but the last update ID was %v", got)
+	}
+}
@@ -0,0 +1,992 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/beyondallrepair/gobinance (interfaces: SpotTrader,StopLimitOrderPlacer,StopOrderPlacer,OrderQuerier,AccountReader,SpotAccount,MarketDataReader,TradeStreamer,MarketStreamer,UserDataStreamer)
+
+// Package mock_gobinance is a generated GoMock package.
+package mock_gobinance
+
+import (
+	context "context"
+	gobinance "github.com/beyondallrepair/gobinance"
+	gomock "github.com/golang/mock/gomock"
+	big "math/big"
+	reflect "reflect"
+)
+
+// MockSpotTrader is a mock of SpotTrader interface
+type MockSpotTrader struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpotTraderMockRecorder
+}
+
+// MockSpotTraderMockRecorder is the mock recorder for MockSpotTrader
+type MockSpotTraderMockRecorder struct {
+	mock *MockSpotTrader
+}
+
+// NewMockSpotTrader creates a new mock instance
+func NewMockSpotTrader(ctrl *gomock.Controller) *MockSpotTrader {
+	mock := &MockSpotTrader{ctrl: ctrl}
+	mock.recorder = &MockSpotTraderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSpotTrader) EXPECT() *MockSpotTraderMockRecorder {
+	return m.recorder
+}
+
+// CancelOrderByClientOrderID mocks base method
+func (m *MockSpotTrader) CancelOrderByClientOrderID(arg0 context.Context, arg1, arg2 string, arg3 ...gobinance.CancelSpotOrderOption) (gobinance.CancelSpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CancelOrderByClientOrderID", varargs...)
+	ret0, _ := ret[0].(gobinance.CancelSpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrderByClientOrderID indicates an expected call of CancelOrderByClientOrderID
+func (mr *MockSpotTraderMockRecorder) CancelOrderByClientOrderID(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrderByClientOrderID", reflect.TypeOf((*MockSpotTrader)(nil).CancelOrderByClientOrderID), varargs...)
+}
+
+// CancelOrderByOrderID mocks base method
+func (m *MockSpotTrader) CancelOrderByOrderID(arg0 context.Context, arg1 string, arg2 int64, arg3 ...gobinance.CancelSpotOrderOption) (gobinance.CancelSpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CancelOrderByOrderID", varargs...)
+	ret0, _ := ret[0].(gobinance.CancelSpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrderByOrderID indicates an expected call of CancelOrderByOrderID
+func (mr *MockSpotTraderMockRecorder) CancelOrderByOrderID(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrderByOrderID", reflect.TypeOf((*MockSpotTrader)(nil).CancelOrderByOrderID), varargs...)
+}
+
+// PlaceLimitMakerOrder mocks base method
+func (m *MockSpotTrader) PlaceLimitMakerOrder(arg0 context.Context, arg1 string, arg2 gobinance.OrderSide, arg3, arg4 *big.Float, arg5 ...gobinance.SpotOrderOption) (gobinance.SpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2, arg3, arg4}
+	for _, a := range arg5 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PlaceLimitMakerOrder", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceLimitMakerOrder indicates an expected call of PlaceLimitMakerOrder
+func (mr *MockSpotTraderMockRecorder) PlaceLimitMakerOrder(arg0, arg1, arg2, arg3, arg4 interface{}, arg5 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3, arg4}, arg5...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceLimitMakerOrder", reflect.TypeOf((*MockSpotTrader)(nil).PlaceLimitMakerOrder), varargs...)
+}
+
+// PlaceLimitOrder mocks base method
+func (m *MockSpotTrader) PlaceLimitOrder(arg0 context.Context, arg1 string, arg2 gobinance.OrderSide, arg3, arg4 *big.Float, arg5 gobinance.TimeInForce, arg6 ...gobinance.SpotOrderOption) (gobinance.SpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2, arg3, arg4, arg5}
+	for _, a := range arg6 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PlaceLimitOrder", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceLimitOrder indicates an expected call of PlaceLimitOrder
+func (mr *MockSpotTraderMockRecorder) PlaceLimitOrder(arg0, arg1, arg2, arg3, arg4, arg5 interface{}, arg6 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3, arg4, arg5}, arg6...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceLimitOrder", reflect.TypeOf((*MockSpotTrader)(nil).PlaceLimitOrder), varargs...)
+}
+
+// PlaceSpotMarketOrder mocks base method
+func (m *MockSpotTrader) PlaceSpotMarketOrder(arg0 context.Context, arg1 string, arg2 gobinance.OrderSide, arg3 *big.Float, arg4 gobinance.QuantityAsset, arg5 ...gobinance.SpotOrderOption) (gobinance.SpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2, arg3, arg4}
+	for _, a := range arg5 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PlaceSpotMarketOrder", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceSpotMarketOrder indicates an expected call of PlaceSpotMarketOrder
+func (mr *MockSpotTraderMockRecorder) PlaceSpotMarketOrder(arg0, arg1, arg2, arg3, arg4 interface{}, arg5 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3, arg4}, arg5...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceSpotMarketOrder", reflect.TypeOf((*MockSpotTrader)(nil).PlaceSpotMarketOrder), varargs...)
+}
+
+// MockStopLimitOrderPlacer is a mock of StopLimitOrderPlacer interface
+type MockStopLimitOrderPlacer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStopLimitOrderPlacerMockRecorder
+}
+
+// MockStopLimitOrderPlacerMockRecorder is the mock recorder for MockStopLimitOrderPlacer
+type MockStopLimitOrderPlacerMockRecorder struct {
+	mock *MockStopLimitOrderPlacer
+}
+
+// NewMockStopLimitOrderPlacer creates a new mock instance
+func NewMockStopLimitOrderPlacer(ctrl *gomock.Controller) *MockStopLimitOrderPlacer {
+	mock := &MockStopLimitOrderPlacer{ctrl: ctrl}
+	mock.recorder = &MockStopLimitOrderPlacerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockStopLimitOrderPlacer) EXPECT() *MockStopLimitOrderPlacerMockRecorder {
+	return m.recorder
+}
+
+// PlaceStopLossLimitOrder mocks base method
+func (m *MockStopLimitOrderPlacer) PlaceStopLossLimitOrder(arg0 context.Context, arg1 string, arg2 gobinance.OrderSide, arg3, arg4, arg5 *big.Float, arg6 gobinance.TimeInForce, arg7 ...gobinance.SpotOrderOption) (gobinance.SpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2, arg3, arg4, arg5, arg6}
+	for _, a := range arg7 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PlaceStopLossLimitOrder", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceStopLossLimitOrder indicates an expected call of PlaceStopLossLimitOrder
+func (mr *MockStopLimitOrderPlacerMockRecorder) PlaceStopLossLimitOrder(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}, arg7 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3, arg4, arg5, arg6}, arg7...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceStopLossLimitOrder", reflect.TypeOf((*MockStopLimitOrderPlacer)(nil).PlaceStopLossLimitOrder), varargs...)
+}
+
+// PlaceTakeProfitLimitOrder mocks base method
+func (m *MockStopLimitOrderPlacer) PlaceTakeProfitLimitOrder(arg0 context.Context, arg1 string, arg2 gobinance.OrderSide, arg3, arg4, arg5 *big.Float, arg6 gobinance.TimeInForce, arg7 ...gobinance.SpotOrderOption) (gobinance.SpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2, arg3, arg4, arg5, arg6}
+	for _, a := range arg7 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PlaceTakeProfitLimitOrder", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceTakeProfitLimitOrder indicates an expected call of PlaceTakeProfitLimitOrder
+func (mr *MockStopLimitOrderPlacerMockRecorder) PlaceTakeProfitLimitOrder(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}, arg7 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3, arg4, arg5, arg6}, arg7...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceTakeProfitLimitOrder", reflect.TypeOf((*MockStopLimitOrderPlacer)(nil).PlaceTakeProfitLimitOrder), varargs...)
+}
+
+// MockStopOrderPlacer is a mock of StopOrderPlacer interface
+type MockStopOrderPlacer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStopOrderPlacerMockRecorder
+}
+
+// MockStopOrderPlacerMockRecorder is the mock recorder for MockStopOrderPlacer
+type MockStopOrderPlacerMockRecorder struct {
+	mock *MockStopOrderPlacer
+}
+
+// NewMockStopOrderPlacer creates a new mock instance
+func NewMockStopOrderPlacer(ctrl *gomock.Controller) *MockStopOrderPlacer {
+	mock := &MockStopOrderPlacer{ctrl: ctrl}
+	mock.recorder = &MockStopOrderPlacerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockStopOrderPlacer) EXPECT() *MockStopOrderPlacerMockRecorder {
+	return m.recorder
+}
+
+// PlaceStopLossOrder mocks base method
+func (m *MockStopOrderPlacer) PlaceStopLossOrder(arg0 context.Context, arg1 string, arg2 gobinance.OrderSide, arg3, arg4 *big.Float, arg5 ...gobinance.SpotOrderOption) (gobinance.SpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2, arg3, arg4}
+	for _, a := range arg5 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PlaceStopLossOrder", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceStopLossOrder indicates an expected call of PlaceStopLossOrder
+func (mr *MockStopOrderPlacerMockRecorder) PlaceStopLossOrder(arg0, arg1, arg2, arg3, arg4 interface{}, arg5 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3, arg4}, arg5...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceStopLossOrder", reflect.TypeOf((*MockStopOrderPlacer)(nil).PlaceStopLossOrder), varargs...)
+}
+
+// PlaceTakeProfitOrder mocks base method
+func (m *MockStopOrderPlacer) PlaceTakeProfitOrder(arg0 context.Context, arg1 string, arg2 gobinance.OrderSide, arg3, arg4 *big.Float, arg5 ...gobinance.SpotOrderOption) (gobinance.SpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2, arg3, arg4}
+	for _, a := range arg5 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PlaceTakeProfitOrder", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceTakeProfitOrder indicates an expected call of PlaceTakeProfitOrder
+func (mr *MockStopOrderPlacerMockRecorder) PlaceTakeProfitOrder(arg0, arg1, arg2, arg3, arg4 interface{}, arg5 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3, arg4}, arg5...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceTakeProfitOrder", reflect.TypeOf((*MockStopOrderPlacer)(nil).PlaceTakeProfitOrder), varargs...)
+}
+
+// MockOrderQuerier is a mock of OrderQuerier interface
+type MockOrderQuerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderQuerierMockRecorder
+}
+
+// MockOrderQuerierMockRecorder is the mock recorder for MockOrderQuerier
+type MockOrderQuerierMockRecorder struct {
+	mock *MockOrderQuerier
+}
+
+// NewMockOrderQuerier creates a new mock instance
+func NewMockOrderQuerier(ctrl *gomock.Controller) *MockOrderQuerier {
+	mock := &MockOrderQuerier{ctrl: ctrl}
+	mock.recorder = &MockOrderQuerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockOrderQuerier) EXPECT() *MockOrderQuerierMockRecorder {
+	return m.recorder
+}
+
+// AllOpenSpotOrders mocks base method
+func (m *MockOrderQuerier) AllOpenSpotOrders(arg0 context.Context, arg1 ...gobinance.OpenOrdersOptions) ([]gobinance.SpotOrder, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AllOpenSpotOrders", varargs...)
+	ret0, _ := ret[0].([]gobinance.SpotOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllOpenSpotOrders indicates an expected call of AllOpenSpotOrders
+func (mr *MockOrderQuerierMockRecorder) AllOpenSpotOrders(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllOpenSpotOrders", reflect.TypeOf((*MockOrderQuerier)(nil).AllOpenSpotOrders), varargs...)
+}
+
+// OpenSpotOrdersForSymbol mocks base method
+func (m *MockOrderQuerier) OpenSpotOrdersForSymbol(arg0 context.Context, arg1 string, arg2 ...gobinance.OpenOrdersOptions) ([]gobinance.SpotOrder, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "OpenSpotOrdersForSymbol", varargs...)
+	ret0, _ := ret[0].([]gobinance.SpotOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenSpotOrdersForSymbol indicates an expected call of OpenSpotOrdersForSymbol
+func (mr *MockOrderQuerierMockRecorder) OpenSpotOrdersForSymbol(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenSpotOrdersForSymbol", reflect.TypeOf((*MockOrderQuerier)(nil).OpenSpotOrdersForSymbol), varargs...)
+}
+
+// QueryOrderByClientID mocks base method
+func (m *MockOrderQuerier) QueryOrderByClientID(arg0 context.Context, arg1, arg2 string, arg3 ...gobinance.QueryOrderOption) (gobinance.SpotOrder, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryOrderByClientID", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryOrderByClientID indicates an expected call of QueryOrderByClientID
+func (mr *MockOrderQuerierMockRecorder) QueryOrderByClientID(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryOrderByClientID", reflect.TypeOf((*MockOrderQuerier)(nil).QueryOrderByClientID), varargs...)
+}
+
+// QueryOrderByID mocks base method
+func (m *MockOrderQuerier) QueryOrderByID(arg0 context.Context, arg1 string, arg2 int64, arg3 ...gobinance.QueryOrderOption) (gobinance.SpotOrder, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryOrderByID", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryOrderByID indicates an expected call of QueryOrderByID
+func (mr *MockOrderQuerierMockRecorder) QueryOrderByID(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryOrderByID", reflect.TypeOf((*MockOrderQuerier)(nil).QueryOrderByID), varargs...)
+}
+
+// MockAccountReader is a mock of AccountReader interface
+type MockAccountReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountReaderMockRecorder
+}
+
+// MockAccountReaderMockRecorder is the mock recorder for MockAccountReader
+type MockAccountReaderMockRecorder struct {
+	mock *MockAccountReader
+}
+
+// NewMockAccountReader creates a new mock instance
+func NewMockAccountReader(ctrl *gomock.Controller) *MockAccountReader {
+	mock := &MockAccountReader{ctrl: ctrl}
+	mock.recorder = &MockAccountReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAccountReader) EXPECT() *MockAccountReaderMockRecorder {
+	return m.recorder
+}
+
+// AccountInformation mocks base method
+func (m *MockAccountReader) AccountInformation(arg0 context.Context) (gobinance.AccountInformation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountInformation", arg0)
+	ret0, _ := ret[0].(gobinance.AccountInformation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountInformation indicates an expected call of AccountInformation
+func (mr *MockAccountReaderMockRecorder) AccountInformation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountInformation", reflect.TypeOf((*MockAccountReader)(nil).AccountInformation), arg0)
+}
+
+// MockSpotAccount is a mock of SpotAccount interface
+type MockSpotAccount struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpotAccountMockRecorder
+}
+
+// MockSpotAccountMockRecorder is the mock recorder for MockSpotAccount
+type MockSpotAccountMockRecorder struct {
+	mock *MockSpotAccount
+}
+
+// NewMockSpotAccount creates a new mock instance
+func NewMockSpotAccount(ctrl *gomock.Controller) *MockSpotAccount {
+	mock := &MockSpotAccount{ctrl: ctrl}
+	mock.recorder = &MockSpotAccountMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSpotAccount) EXPECT() *MockSpotAccountMockRecorder {
+	return m.recorder
+}
+
+// AccountInformation mocks base method
+func (m *MockSpotAccount) AccountInformation(arg0 context.Context) (gobinance.AccountInformation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountInformation", arg0)
+	ret0, _ := ret[0].(gobinance.AccountInformation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountInformation indicates an expected call of AccountInformation
+func (mr *MockSpotAccountMockRecorder) AccountInformation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountInformation", reflect.TypeOf((*MockSpotAccount)(nil).AccountInformation), arg0)
+}
+
+// AllOpenSpotOrders mocks base method
+func (m *MockSpotAccount) AllOpenSpotOrders(arg0 context.Context, arg1 ...gobinance.OpenOrdersOptions) ([]gobinance.SpotOrder, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AllOpenSpotOrders", varargs...)
+	ret0, _ := ret[0].([]gobinance.SpotOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllOpenSpotOrders indicates an expected call of AllOpenSpotOrders
+func (mr *MockSpotAccountMockRecorder) AllOpenSpotOrders(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllOpenSpotOrders", reflect.TypeOf((*MockSpotAccount)(nil).AllOpenSpotOrders), varargs...)
+}
+
+// CancelOrderByClientOrderID mocks base method
+func (m *MockSpotAccount) CancelOrderByClientOrderID(arg0 context.Context, arg1, arg2 string, arg3 ...gobinance.CancelSpotOrderOption) (gobinance.CancelSpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CancelOrderByClientOrderID", varargs...)
+	ret0, _ := ret[0].(gobinance.CancelSpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrderByClientOrderID indicates an expected call of CancelOrderByClientOrderID
+func (mr *MockSpotAccountMockRecorder) CancelOrderByClientOrderID(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrderByClientOrderID", reflect.TypeOf((*MockSpotAccount)(nil).CancelOrderByClientOrderID), varargs...)
+}
+
+// CancelOrderByOrderID mocks base method
+func (m *MockSpotAccount) CancelOrderByOrderID(arg0 context.Context, arg1 string, arg2 int64, arg3 ...gobinance.CancelSpotOrderOption) (gobinance.CancelSpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CancelOrderByOrderID", varargs...)
+	ret0, _ := ret[0].(gobinance.CancelSpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrderByOrderID indicates an expected call of CancelOrderByOrderID
+func (mr *MockSpotAccountMockRecorder) CancelOrderByOrderID(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrderByOrderID", reflect.TypeOf((*MockSpotAccount)(nil).CancelOrderByOrderID), varargs...)
+}
+
+// OpenSpotOrdersForSymbol mocks base method
+func (m *MockSpotAccount) OpenSpotOrdersForSymbol(arg0 context.Context, arg1 string, arg2 ...gobinance.OpenOrdersOptions) ([]gobinance.SpotOrder, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "OpenSpotOrdersForSymbol", varargs...)
+	ret0, _ := ret[0].([]gobinance.SpotOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenSpotOrdersForSymbol indicates an expected call of OpenSpotOrdersForSymbol
+func (mr *MockSpotAccountMockRecorder) OpenSpotOrdersForSymbol(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenSpotOrdersForSymbol", reflect.TypeOf((*MockSpotAccount)(nil).OpenSpotOrdersForSymbol), varargs...)
+}
+
+// PlaceLimitMakerOrder mocks base method
+func (m *MockSpotAccount) PlaceLimitMakerOrder(arg0 context.Context, arg1 string, arg2 gobinance.OrderSide, arg3, arg4 *big.Float, arg5 ...gobinance.SpotOrderOption) (gobinance.SpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2, arg3, arg4}
+	for _, a := range arg5 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PlaceLimitMakerOrder", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceLimitMakerOrder indicates an expected call of PlaceLimitMakerOrder
+func (mr *MockSpotAccountMockRecorder) PlaceLimitMakerOrder(arg0, arg1, arg2, arg3, arg4 interface{}, arg5 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3, arg4}, arg5...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceLimitMakerOrder", reflect.TypeOf((*MockSpotAccount)(nil).PlaceLimitMakerOrder), varargs...)
+}
+
+// PlaceLimitOrder mocks base method
+func (m *MockSpotAccount) PlaceLimitOrder(arg0 context.Context, arg1 string, arg2 gobinance.OrderSide, arg3, arg4 *big.Float, arg5 gobinance.TimeInForce, arg6 ...gobinance.SpotOrderOption) (gobinance.SpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2, arg3, arg4, arg5}
+	for _, a := range arg6 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PlaceLimitOrder", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceLimitOrder indicates an expected call of PlaceLimitOrder
+func (mr *MockSpotAccountMockRecorder) PlaceLimitOrder(arg0, arg1, arg2, arg3, arg4, arg5 interface{}, arg6 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3, arg4, arg5}, arg6...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceLimitOrder", reflect.TypeOf((*MockSpotAccount)(nil).PlaceLimitOrder), varargs...)
+}
+
+// PlaceSpotMarketOrder mocks base method
+func (m *MockSpotAccount) PlaceSpotMarketOrder(arg0 context.Context, arg1 string, arg2 gobinance.OrderSide, arg3 *big.Float, arg4 gobinance.QuantityAsset, arg5 ...gobinance.SpotOrderOption) (gobinance.SpotOrderResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2, arg3, arg4}
+	for _, a := range arg5 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PlaceSpotMarketOrder", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceSpotMarketOrder indicates an expected call of PlaceSpotMarketOrder
+func (mr *MockSpotAccountMockRecorder) PlaceSpotMarketOrder(arg0, arg1, arg2, arg3, arg4 interface{}, arg5 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3, arg4}, arg5...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceSpotMarketOrder", reflect.TypeOf((*MockSpotAccount)(nil).PlaceSpotMarketOrder), varargs...)
+}
+
+// QueryOrderByClientID mocks base method
+func (m *MockSpotAccount) QueryOrderByClientID(arg0 context.Context, arg1, arg2 string, arg3 ...gobinance.QueryOrderOption) (gobinance.SpotOrder, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryOrderByClientID", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryOrderByClientID indicates an expected call of QueryOrderByClientID
+func (mr *MockSpotAccountMockRecorder) QueryOrderByClientID(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryOrderByClientID", reflect.TypeOf((*MockSpotAccount)(nil).QueryOrderByClientID), varargs...)
+}
+
+// QueryOrderByID mocks base method
+func (m *MockSpotAccount) QueryOrderByID(arg0 context.Context, arg1 string, arg2 int64, arg3 ...gobinance.QueryOrderOption) (gobinance.SpotOrder, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryOrderByID", varargs...)
+	ret0, _ := ret[0].(gobinance.SpotOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryOrderByID indicates an expected call of QueryOrderByID
+func (mr *MockSpotAccountMockRecorder) QueryOrderByID(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryOrderByID", reflect.TypeOf((*MockSpotAccount)(nil).QueryOrderByID), varargs...)
+}
+
+// MockMarketDataReader is a mock of MarketDataReader interface
+type MockMarketDataReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockMarketDataReaderMockRecorder
+}
+
+// MockMarketDataReaderMockRecorder is the mock recorder for MockMarketDataReader
+type MockMarketDataReaderMockRecorder struct {
+	mock *MockMarketDataReader
+}
+
+// NewMockMarketDataReader creates a new mock instance
+func NewMockMarketDataReader(ctrl *gomock.Controller) *MockMarketDataReader {
+	mock := &MockMarketDataReader{ctrl: ctrl}
+	mock.recorder = &MockMarketDataReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMarketDataReader) EXPECT() *MockMarketDataReaderMockRecorder {
+	return m.recorder
+}
+
+// AggregateTrades mocks base method
+func (m *MockMarketDataReader) AggregateTrades(arg0 context.Context, arg1 string, arg2 ...gobinance.AggregateTradesOption) ([]gobinance.AggTrade, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AggregateTrades", varargs...)
+	ret0, _ := ret[0].([]gobinance.AggTrade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AggregateTrades indicates an expected call of AggregateTrades
+func (mr *MockMarketDataReaderMockRecorder) AggregateTrades(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateTrades", reflect.TypeOf((*MockMarketDataReader)(nil).AggregateTrades), varargs...)
+}
+
+// OrderBookDepth mocks base method
+func (m *MockMarketDataReader) OrderBookDepth(arg0 context.Context, arg1 string, arg2 ...gobinance.OrderBookDepthOption) (gobinance.OrderBookDepth, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "OrderBookDepth", varargs...)
+	ret0, _ := ret[0].(gobinance.OrderBookDepth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrderBookDepth indicates an expected call of OrderBookDepth
+func (mr *MockMarketDataReaderMockRecorder) OrderBookDepth(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderBookDepth", reflect.TypeOf((*MockMarketDataReader)(nil).OrderBookDepth), varargs...)
+}
+
+// MockTradeStreamer is a mock of TradeStreamer interface
+type MockTradeStreamer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTradeStreamerMockRecorder
+}
+
+// MockTradeStreamerMockRecorder is the mock recorder for MockTradeStreamer
+type MockTradeStreamerMockRecorder struct {
+	mock *MockTradeStreamer
+}
+
+// NewMockTradeStreamer creates a new mock instance
+func NewMockTradeStreamer(ctrl *gomock.Controller) *MockTradeStreamer {
+	mock := &MockTradeStreamer{ctrl: ctrl}
+	mock.recorder = &MockTradeStreamerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTradeStreamer) EXPECT() *MockTradeStreamerMockRecorder {
+	return m.recorder
+}
+
+// Trades mocks base method
+func (m *MockTradeStreamer) Trades(arg0 context.Context, arg1 string) <-chan gobinance.TradeEventOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trades", arg0, arg1)
+	ret0, _ := ret[0].(<-chan gobinance.TradeEventOrError)
+	return ret0
+}
+
+// Trades indicates an expected call of Trades
+func (mr *MockTradeStreamerMockRecorder) Trades(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trades", reflect.TypeOf((*MockTradeStreamer)(nil).Trades), arg0, arg1)
+}
+
+// MockMarketStreamer is a mock of MarketStreamer interface
+type MockMarketStreamer struct {
+	ctrl     *gomock.Controller
+	recorder *MockMarketStreamerMockRecorder
+}
+
+// MockMarketStreamerMockRecorder is the mock recorder for MockMarketStreamer
+type MockMarketStreamerMockRecorder struct {
+	mock *MockMarketStreamer
+}
+
+// NewMockMarketStreamer creates a new mock instance
+func NewMockMarketStreamer(ctrl *gomock.Controller) *MockMarketStreamer {
+	mock := &MockMarketStreamer{ctrl: ctrl}
+	mock.recorder = &MockMarketStreamerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMarketStreamer) EXPECT() *MockMarketStreamerMockRecorder {
+	return m.recorder
+}
+
+// AggTrades mocks base method
+func (m *MockMarketStreamer) AggTrades(arg0 context.Context, arg1 string) <-chan gobinance.AggTradeEventOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggTrades", arg0, arg1)
+	ret0, _ := ret[0].(<-chan gobinance.AggTradeEventOrError)
+	return ret0
+}
+
+// AggTrades indicates an expected call of AggTrades
+func (mr *MockMarketStreamerMockRecorder) AggTrades(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggTrades", reflect.TypeOf((*MockMarketStreamer)(nil).AggTrades), arg0, arg1)
+}
+
+// AllMiniTickers mocks base method
+func (m *MockMarketStreamer) AllMiniTickers(arg0 context.Context) <-chan gobinance.MiniTickerEventsOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllMiniTickers", arg0)
+	ret0, _ := ret[0].(<-chan gobinance.MiniTickerEventsOrError)
+	return ret0
+}
+
+// AllMiniTickers indicates an expected call of AllMiniTickers
+func (mr *MockMarketStreamerMockRecorder) AllMiniTickers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllMiniTickers", reflect.TypeOf((*MockMarketStreamer)(nil).AllMiniTickers), arg0)
+}
+
+// AllRollingWindowTickers mocks base method
+func (m *MockMarketStreamer) AllRollingWindowTickers(arg0 context.Context, arg1 gobinance.TickerWindowSize) <-chan gobinance.TickerEventsOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllRollingWindowTickers", arg0, arg1)
+	ret0, _ := ret[0].(<-chan gobinance.TickerEventsOrError)
+	return ret0
+}
+
+// AllRollingWindowTickers indicates an expected call of AllRollingWindowTickers
+func (mr *MockMarketStreamerMockRecorder) AllRollingWindowTickers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllRollingWindowTickers", reflect.TypeOf((*MockMarketStreamer)(nil).AllRollingWindowTickers), arg0, arg1)
+}
+
+// AllTickers mocks base method
+func (m *MockMarketStreamer) AllTickers(arg0 context.Context) <-chan gobinance.TickerEventsOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllTickers", arg0)
+	ret0, _ := ret[0].(<-chan gobinance.TickerEventsOrError)
+	return ret0
+}
+
+// AllTickers indicates an expected call of AllTickers
+func (mr *MockMarketStreamerMockRecorder) AllTickers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllTickers", reflect.TypeOf((*MockMarketStreamer)(nil).AllTickers), arg0)
+}
+
+// BookTicker mocks base method
+func (m *MockMarketStreamer) BookTicker(arg0 context.Context, arg1 string) <-chan gobinance.BookTickerEventOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookTicker", arg0, arg1)
+	ret0, _ := ret[0].(<-chan gobinance.BookTickerEventOrError)
+	return ret0
+}
+
+// BookTicker indicates an expected call of BookTicker
+func (mr *MockMarketStreamerMockRecorder) BookTicker(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookTicker", reflect.TypeOf((*MockMarketStreamer)(nil).BookTicker), arg0, arg1)
+}
+
+// DepthUpdates mocks base method
+func (m *MockMarketStreamer) DepthUpdates(arg0 context.Context, arg1 string, arg2 gobinance.DepthUpdateSpeed) <-chan gobinance.DepthUpdateEventOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DepthUpdates", arg0, arg1, arg2)
+	ret0, _ := ret[0].(<-chan gobinance.DepthUpdateEventOrError)
+	return ret0
+}
+
+// DepthUpdates indicates an expected call of DepthUpdates
+func (mr *MockMarketStreamerMockRecorder) DepthUpdates(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DepthUpdates", reflect.TypeOf((*MockMarketStreamer)(nil).DepthUpdates), arg0, arg1, arg2)
+}
+
+// KlineStream mocks base method
+func (m *MockMarketStreamer) KlineStream(arg0 context.Context, arg1 string, arg2 gobinance.KlineInterval) <-chan gobinance.KlineEventOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KlineStream", arg0, arg1, arg2)
+	ret0, _ := ret[0].(<-chan gobinance.KlineEventOrError)
+	return ret0
+}
+
+// KlineStream indicates an expected call of KlineStream
+func (mr *MockMarketStreamerMockRecorder) KlineStream(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KlineStream", reflect.TypeOf((*MockMarketStreamer)(nil).KlineStream), arg0, arg1, arg2)
+}
+
+// MiniTicker mocks base method
+func (m *MockMarketStreamer) MiniTicker(arg0 context.Context, arg1 string) <-chan gobinance.MiniTickerEventOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MiniTicker", arg0, arg1)
+	ret0, _ := ret[0].(<-chan gobinance.MiniTickerEventOrError)
+	return ret0
+}
+
+// MiniTicker indicates an expected call of MiniTicker
+func (mr *MockMarketStreamerMockRecorder) MiniTicker(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MiniTicker", reflect.TypeOf((*MockMarketStreamer)(nil).MiniTicker), arg0, arg1)
+}
+
+// PartialDepth mocks base method
+func (m *MockMarketStreamer) PartialDepth(arg0 context.Context, arg1 string, arg2 gobinance.DepthLevels, arg3 gobinance.DepthUpdateSpeed) <-chan gobinance.PartialDepthEventOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PartialDepth", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(<-chan gobinance.PartialDepthEventOrError)
+	return ret0
+}
+
+// PartialDepth indicates an expected call of PartialDepth
+func (mr *MockMarketStreamerMockRecorder) PartialDepth(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PartialDepth", reflect.TypeOf((*MockMarketStreamer)(nil).PartialDepth), arg0, arg1, arg2, arg3)
+}
+
+// RollingWindowTicker mocks base method
+func (m *MockMarketStreamer) RollingWindowTicker(arg0 context.Context, arg1 string, arg2 gobinance.TickerWindowSize) <-chan gobinance.TickerEventOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollingWindowTicker", arg0, arg1, arg2)
+	ret0, _ := ret[0].(<-chan gobinance.TickerEventOrError)
+	return ret0
+}
+
+// RollingWindowTicker indicates an expected call of RollingWindowTicker
+func (mr *MockMarketStreamerMockRecorder) RollingWindowTicker(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollingWindowTicker", reflect.TypeOf((*MockMarketStreamer)(nil).RollingWindowTicker), arg0, arg1, arg2)
+}
+
+// Ticker mocks base method
+func (m *MockMarketStreamer) Ticker(arg0 context.Context, arg1 string) <-chan gobinance.TickerEventOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ticker", arg0, arg1)
+	ret0, _ := ret[0].(<-chan gobinance.TickerEventOrError)
+	return ret0
+}
+
+// Ticker indicates an expected call of Ticker
+func (mr *MockMarketStreamerMockRecorder) Ticker(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ticker", reflect.TypeOf((*MockMarketStreamer)(nil).Ticker), arg0, arg1)
+}
+
+// Trades mocks base method
+func (m *MockMarketStreamer) Trades(arg0 context.Context, arg1 string) <-chan gobinance.TradeEventOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trades", arg0, arg1)
+	ret0, _ := ret[0].(<-chan gobinance.TradeEventOrError)
+	return ret0
+}
+
+// Trades indicates an expected call of Trades
+func (mr *MockMarketStreamerMockRecorder) Trades(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trades", reflect.TypeOf((*MockMarketStreamer)(nil).Trades), arg0, arg1)
+}
+
+// MockUserDataStreamer is a mock of UserDataStreamer interface
+type MockUserDataStreamer struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserDataStreamerMockRecorder
+}
+
+// MockUserDataStreamerMockRecorder is the mock recorder for MockUserDataStreamer
+type MockUserDataStreamerMockRecorder struct {
+	mock *MockUserDataStreamer
+}
+
+// NewMockUserDataStreamer creates a new mock instance
+func NewMockUserDataStreamer(ctrl *gomock.Controller) *MockUserDataStreamer {
+	mock := &MockUserDataStreamer{ctrl: ctrl}
+	mock.recorder = &MockUserDataStreamerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockUserDataStreamer) EXPECT() *MockUserDataStreamerMockRecorder {
+	return m.recorder
+}
+
+// CloseUserDataStream mocks base method
+func (m *MockUserDataStreamer) CloseUserDataStream(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseUserDataStream", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseUserDataStream indicates an expected call of CloseUserDataStream
+func (mr *MockUserDataStreamerMockRecorder) CloseUserDataStream(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseUserDataStream", reflect.TypeOf((*MockUserDataStreamer)(nil).CloseUserDataStream), arg0, arg1)
+}
+
+// KeepAliveUserDataStream mocks base method
+func (m *MockUserDataStreamer) KeepAliveUserDataStream(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeepAliveUserDataStream", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// KeepAliveUserDataStream indicates an expected call of KeepAliveUserDataStream
+func (mr *MockUserDataStreamerMockRecorder) KeepAliveUserDataStream(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeepAliveUserDataStream", reflect.TypeOf((*MockUserDataStreamer)(nil).KeepAliveUserDataStream), arg0, arg1)
+}
+
+// StartUserDataStream mocks base method
+func (m *MockUserDataStreamer) StartUserDataStream(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartUserDataStream", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartUserDataStream indicates an expected call of StartUserDataStream
+func (mr *MockUserDataStreamerMockRecorder) StartUserDataStream(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartUserDataStream", reflect.TypeOf((*MockUserDataStreamer)(nil).StartUserDataStream), arg0)
+}
+
+// UserData mocks base method
+func (m *MockUserDataStreamer) UserData(arg0 context.Context) <-chan gobinance.UserDataEventOrError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserData", arg0)
+	ret0, _ := ret[0].(<-chan gobinance.UserDataEventOrError)
+	return ret0
+}
+
+// UserData indicates an expected call of UserData
+func (mr *MockUserDataStreamerMockRecorder) UserData(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserData", reflect.TypeOf((*MockUserDataStreamer)(nil).UserData), arg0)
+}
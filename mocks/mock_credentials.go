@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/beyondallrepair/gobinance (interfaces: CredentialsProvider,Keyring)
+
+// Package mock_gobinance is a generated GoMock package.
+package mock_gobinance
+
+import (
+	context "context"
+	gobinance "github.com/beyondallrepair/gobinance"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockCredentialsProvider is a mock of CredentialsProvider interface
+type MockCredentialsProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockCredentialsProviderMockRecorder
+}
+
+// MockCredentialsProviderMockRecorder is the mock recorder for MockCredentialsProvider
+type MockCredentialsProviderMockRecorder struct {
+	mock *MockCredentialsProvider
+}
+
+// NewMockCredentialsProvider creates a new mock instance
+func NewMockCredentialsProvider(ctrl *gomock.Controller) *MockCredentialsProvider {
+	mock := &MockCredentialsProvider{ctrl: ctrl}
+	mock.recorder = &MockCredentialsProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCredentialsProvider) EXPECT() *MockCredentialsProviderMockRecorder {
+	return m.recorder
+}
+
+// Credentials mocks base method
+func (m *MockCredentialsProvider) Credentials(arg0 context.Context) (gobinance.Credentials, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Credentials", arg0)
+	ret0, _ := ret[0].(gobinance.Credentials)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Credentials indicates an expected call of Credentials
+func (mr *MockCredentialsProviderMockRecorder) Credentials(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Credentials", reflect.TypeOf((*MockCredentialsProvider)(nil).Credentials), arg0)
+}
+
+// MockKeyring is a mock of Keyring interface
+type MockKeyring struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyringMockRecorder
+}
+
+// MockKeyringMockRecorder is the mock recorder for MockKeyring
+type MockKeyringMockRecorder struct {
+	mock *MockKeyring
+}
+
+// NewMockKeyring creates a new mock instance
+func NewMockKeyring(ctrl *gomock.Controller) *MockKeyring {
+	mock := &MockKeyring{ctrl: ctrl}
+	mock.recorder = &MockKeyringMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockKeyring) EXPECT() *MockKeyringMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method
+func (m *MockKeyring) Get(arg0, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *MockKeyringMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockKeyring)(nil).Get), arg0, arg1)
+}
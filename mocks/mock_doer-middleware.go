@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/beyondallrepair/gobinance (interfaces: RequestMetrics)
+
+// Package mock_gobinance is a generated GoMock package.
+package mock_gobinance
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockRequestMetrics is a mock of RequestMetrics interface
+type MockRequestMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockRequestMetricsMockRecorder
+}
+
+// MockRequestMetricsMockRecorder is the mock recorder for MockRequestMetrics
+type MockRequestMetricsMockRecorder struct {
+	mock *MockRequestMetrics
+}
+
+// NewMockRequestMetrics creates a new mock instance
+func NewMockRequestMetrics(ctrl *gomock.Controller) *MockRequestMetrics {
+	mock := &MockRequestMetrics{ctrl: ctrl}
+	mock.recorder = &MockRequestMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRequestMetrics) EXPECT() *MockRequestMetricsMockRecorder {
+	return m.recorder
+}
+
+// CountError mocks base method
+func (m *MockRequestMetrics) CountError(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CountError", arg0, arg1)
+}
+
+// CountError indicates an expected call of CountError
+func (mr *MockRequestMetricsMockRecorder) CountError(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountError", reflect.TypeOf((*MockRequestMetrics)(nil).CountError), arg0, arg1)
+}
+
+// CountResponse mocks base method
+func (m *MockRequestMetrics) CountResponse(arg0, arg1 string, arg2, arg3 int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CountResponse", arg0, arg1, arg2, arg3)
+}
+
+// CountResponse indicates an expected call of CountResponse
+func (mr *MockRequestMetricsMockRecorder) CountResponse(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountResponse", reflect.TypeOf((*MockRequestMetrics)(nil).CountResponse), arg0, arg1, arg2, arg3)
+}
+
+// ObserveLatency mocks base method
+func (m *MockRequestMetrics) ObserveLatency(arg0, arg1 string, arg2 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveLatency", arg0, arg1, arg2)
+}
+
+// ObserveLatency indicates an expected call of ObserveLatency
+func (mr *MockRequestMetricsMockRecorder) ObserveLatency(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveLatency", reflect.TypeOf((*MockRequestMetrics)(nil).ObserveLatency), arg0, arg1, arg2)
+}
+
+// SetOrderCount mocks base method
+func (m *MockRequestMetrics) SetOrderCount(arg0 string, arg1 int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetOrderCount", arg0, arg1)
+}
+
+// SetOrderCount indicates an expected call of SetOrderCount
+func (mr *MockRequestMetricsMockRecorder) SetOrderCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrderCount", reflect.TypeOf((*MockRequestMetrics)(nil).SetOrderCount), arg0, arg1)
+}
+
+// SetWeightUsed mocks base method
+func (m *MockRequestMetrics) SetWeightUsed(arg0 string, arg1 int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetWeightUsed", arg0, arg1)
+}
+
+// SetWeightUsed indicates an expected call of SetWeightUsed
+func (mr *MockRequestMetricsMockRecorder) SetWeightUsed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWeightUsed", reflect.TypeOf((*MockRequestMetrics)(nil).SetWeightUsed), arg0, arg1)
+}
@@ -0,0 +1,118 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/beyondallrepair/gobinance (interfaces: StreamObserver)
+
+// Package mock_gobinance is a generated GoMock package.
+package mock_gobinance
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockStreamObserver is a mock of StreamObserver interface
+type MockStreamObserver struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamObserverMockRecorder
+}
+
+// MockStreamObserverMockRecorder is the mock recorder for MockStreamObserver
+type MockStreamObserverMockRecorder struct {
+	mock *MockStreamObserver
+}
+
+// NewMockStreamObserver creates a new mock instance
+func NewMockStreamObserver(ctrl *gomock.Controller) *MockStreamObserver {
+	mock := &MockStreamObserver{ctrl: ctrl}
+	mock.recorder = &MockStreamObserverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockStreamObserver) EXPECT() *MockStreamObserverMockRecorder {
+	return m.recorder
+}
+
+// Connected mocks base method
+func (m *MockStreamObserver) Connected(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Connected", arg0)
+}
+
+// Connected indicates an expected call of Connected
+func (mr *MockStreamObserverMockRecorder) Connected(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connected", reflect.TypeOf((*MockStreamObserver)(nil).Connected), arg0)
+}
+
+// DecodeError mocks base method
+func (m *MockStreamObserver) DecodeError(arg0 string, arg1 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DecodeError", arg0, arg1)
+}
+
+// DecodeError indicates an expected call of DecodeError
+func (mr *MockStreamObserverMockRecorder) DecodeError(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecodeError", reflect.TypeOf((*MockStreamObserver)(nil).DecodeError), arg0, arg1)
+}
+
+// Delivered mocks base method
+func (m *MockStreamObserver) Delivered(arg0 string, arg1 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Delivered", arg0, arg1)
+}
+
+// Delivered indicates an expected call of Delivered
+func (mr *MockStreamObserverMockRecorder) Delivered(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delivered", reflect.TypeOf((*MockStreamObserver)(nil).Delivered), arg0, arg1)
+}
+
+// Dialled mocks base method
+func (m *MockStreamObserver) Dialled(arg0 string, arg1 time.Duration, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Dialled", arg0, arg1, arg2)
+}
+
+// Dialled indicates an expected call of Dialled
+func (mr *MockStreamObserverMockRecorder) Dialled(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dialled", reflect.TypeOf((*MockStreamObserver)(nil).Dialled), arg0, arg1, arg2)
+}
+
+// Disconnected mocks base method
+func (m *MockStreamObserver) Disconnected(arg0 string, arg1 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Disconnected", arg0, arg1)
+}
+
+// Disconnected indicates an expected call of Disconnected
+func (mr *MockStreamObserverMockRecorder) Disconnected(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disconnected", reflect.TypeOf((*MockStreamObserver)(nil).Disconnected), arg0, arg1)
+}
+
+// Dropped mocks base method
+func (m *MockStreamObserver) Dropped(arg0 string, arg1 int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Dropped", arg0, arg1)
+}
+
+// Dropped indicates an expected call of Dropped
+func (mr *MockStreamObserverMockRecorder) Dropped(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dropped", reflect.TypeOf((*MockStreamObserver)(nil).Dropped), arg0, arg1)
+}
+
+// MessageReceived mocks base method
+func (m *MockStreamObserver) MessageReceived(arg0 string, arg1 int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "MessageReceived", arg0, arg1)
+}
+
+// MessageReceived indicates an expected call of MessageReceived
+func (mr *MockStreamObserverMockRecorder) MessageReceived(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessageReceived", reflect.TypeOf((*MockStreamObserver)(nil).MessageReceived), arg0, arg1)
+}
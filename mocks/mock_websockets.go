@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/beyondallrepair/gobinance (interfaces: NextReaderCloser,DialContexter)
+// Source: github.com/beyondallrepair/gobinance (interfaces: NextReaderCloser,DialContexter,NextReaderWriterCloser)
 
 // Package mock_gobinance is a generated GoMock package.
 package mock_gobinance
@@ -104,3 +104,70 @@ func (mr *MockDialContexterMockRecorder) DialContext(arg0, arg1, arg2 interface{
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DialContext", reflect.TypeOf((*MockDialContexter)(nil).DialContext), arg0, arg1, arg2)
 }
+
+// MockNextReaderWriterCloser is a mock of NextReaderWriterCloser interface
+type MockNextReaderWriterCloser struct {
+	ctrl     *gomock.Controller
+	recorder *MockNextReaderWriterCloserMockRecorder
+}
+
+// MockNextReaderWriterCloserMockRecorder is the mock recorder for MockNextReaderWriterCloser
+type MockNextReaderWriterCloserMockRecorder struct {
+	mock *MockNextReaderWriterCloser
+}
+
+// NewMockNextReaderWriterCloser creates a new mock instance
+func NewMockNextReaderWriterCloser(ctrl *gomock.Controller) *MockNextReaderWriterCloser {
+	mock := &MockNextReaderWriterCloser{ctrl: ctrl}
+	mock.recorder = &MockNextReaderWriterCloserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockNextReaderWriterCloser) EXPECT() *MockNextReaderWriterCloserMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockNextReaderWriterCloser) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockNextReaderWriterCloserMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockNextReaderWriterCloser)(nil).Close))
+}
+
+// NextReader mocks base method
+func (m *MockNextReaderWriterCloser) NextReader() (int, io.Reader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextReader")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(io.Reader)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// NextReader indicates an expected call of NextReader
+func (mr *MockNextReaderWriterCloserMockRecorder) NextReader() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextReader", reflect.TypeOf((*MockNextReaderWriterCloser)(nil).NextReader))
+}
+
+// WriteMessage mocks base method
+func (m *MockNextReaderWriterCloser) WriteMessage(arg0 int, arg1 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteMessage", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteMessage indicates an expected call of WriteMessage
+func (mr *MockNextReaderWriterCloserMockRecorder) WriteMessage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMessage", reflect.TypeOf((*MockNextReaderWriterCloser)(nil).WriteMessage), arg0, arg1)
+}
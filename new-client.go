@@ -0,0 +1,204 @@
+package gobinance
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultUserAgent   = "gobinance"
+	defaultHTTPTimeout = 30 * time.Second
+)
+
+// Environment holds the base URLs of a binance deployment
+type Environment struct {
+	HTTPApiURL      *url.URL
+	WebsocketApiURL *url.URL
+}
+
+var (
+	// EnvironmentProduction is binance's production environment
+	EnvironmentProduction = Environment{
+		HTTPApiURL:      mustParseURL("https://api.binance.com"),
+		WebsocketApiURL: mustParseURL("wss://stream.binance.com:9443"),
+	}
+	// EnvironmentProductionAPI1 is binance's production environment, using the api1 cluster
+	EnvironmentProductionAPI1 = Environment{
+		HTTPApiURL:      mustParseURL("https://api1.binance.com"),
+		WebsocketApiURL: mustParseURL("wss://stream.binance.com:9443"),
+	}
+	// EnvironmentProductionAPI2 is binance's production environment, using the api2 cluster
+	EnvironmentProductionAPI2 = Environment{
+		HTTPApiURL:      mustParseURL("https://api2.binance.com"),
+		WebsocketApiURL: mustParseURL("wss://stream.binance.com:9443"),
+	}
+	// EnvironmentProductionAPI3 is binance's production environment, using the api3 cluster
+	EnvironmentProductionAPI3 = Environment{
+		HTTPApiURL:      mustParseURL("https://api3.binance.com"),
+		WebsocketApiURL: mustParseURL("wss://stream.binance.com:9443"),
+	}
+	// EnvironmentProductionAPI4 is binance's production environment, using the api4 cluster
+	EnvironmentProductionAPI4 = Environment{
+		HTTPApiURL:      mustParseURL("https://api4.binance.com"),
+		WebsocketApiURL: mustParseURL("wss://stream.binance.com:9443"),
+	}
+	// EnvironmentSpotTestnet is binance's spot test network
+	EnvironmentSpotTestnet = Environment{
+		HTTPApiURL:      mustParseURL("https://testnet.binance.vision"),
+		WebsocketApiURL: mustParseURL("wss://testnet.binance.vision"),
+	}
+	// EnvironmentBinanceUS is binance.us's production environment
+	EnvironmentBinanceUS = Environment{
+		HTTPApiURL:      mustParseURL("https://api.binance.us"),
+		WebsocketApiURL: mustParseURL("wss://stream.binance.us:9443"),
+	}
+)
+
+func mustParseURL(s string) *url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// ClientOption is a function that applies optional configuration to a Client created using NewClient
+type ClientOption func(c *Client)
+
+// ClientEnvironment sets the URLs used by the client.  The default is EnvironmentProduction.
+func ClientEnvironment(env Environment) ClientOption {
+	return func(c *Client) {
+		c.HTTPApiURL = copyURL(env.HTTPApiURL)
+		c.WebsocketApiURL = copyURL(env.WebsocketApiURL)
+	}
+}
+
+// ClientCredentials sets the API key used by the client, and signs requests using an HMACSigner with the given
+// secret key.  Without credentials, only endpoints which do not require authentication may be used.
+func ClientCredentials(apiKey string, secretKey string) ClientOption {
+	return func(c *Client) {
+		c.APIKey = apiKey
+		c.Signer = &HMACSigner{Secret: secretKey}
+	}
+}
+
+// ClientSigner sets the Signer used to sign requests
+func ClientSigner(signer Signer) ClientOption {
+	return func(c *Client) {
+		c.Signer = signer
+	}
+}
+
+// ClientCredentialsProvider sets the CredentialsProvider used to authenticate requests, in place of the API key
+// and Signer set by ClientCredentials or ClientSigner
+func ClientCredentialsProvider(provider CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.CredentialsProvider = provider
+	}
+}
+
+// ClientDoer sets the Doer used to perform HTTP requests.  The default is an http.Client with a timeout of
+// 30 seconds.
+func ClientDoer(doer Doer) ClientOption {
+	return func(c *Client) {
+		c.Doer = doer
+	}
+}
+
+// ClientDialContexter sets the DialContexter used to make websocket connections.  The default is a GorillaDialer.
+func ClientDialContexter(dialer DialContexter) ClientOption {
+	return func(c *Client) {
+		c.DialContexter = dialer
+	}
+}
+
+// ClientUserAgent sets the User-Agent header sent in HTTP requests
+func ClientUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
+
+// ClientRecvWindow sets the default receive window of signed requests
+func ClientRecvWindow(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.RecvWindow = d
+	}
+}
+
+// ClientSignedRequestEncoding sets where the parameters of signed POST, PUT and DELETE requests are sent
+func ClientSignedRequestEncoding(encoding SignedRequestEncoding) ClientOption {
+	return func(c *Client) {
+		c.SignedRequestEncoding = encoding
+	}
+}
+
+// ClientNow sets the function used to get the current time.  The default is time.Now.
+func ClientNow(now func() time.Time) ClientOption {
+	return func(c *Client) {
+		c.Now = now
+	}
+}
+
+// ClientOrderIDs sets the ClientOrderIDGenerator used to generate client order IDs
+func ClientOrderIDs(generator ClientOrderIDGenerator) ClientOption {
+	return func(c *Client) {
+		c.ClientOrderIDGenerator = generator
+	}
+}
+
+// NewClient creates a Client for binance's production environment, using the default HTTP client and a
+// GorillaDialer.  Options may be used to change the environment, provide credentials, and override the defaults.
+// An error is returned if the resulting client is missing any required fields.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	c := &Client{
+		UserAgent:     defaultUserAgent,
+		Doer:          &http.Client{Timeout: defaultHTTPTimeout},
+		DialContexter: &GorillaDialer{},
+		Now:           time.Now,
+	}
+	ClientEnvironment(EnvironmentProduction)(c)
+	for _, o := range opts {
+		o(c)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate returns an error listing any fields required by the client which are not set.  Signer is not
+// required, as it is only needed for endpoints which require authentication.
+func (c *Client) Validate() error {
+	var missing []string
+	if c.HTTPApiURL == nil {
+		missing = append(missing, "HTTPApiURL")
+	}
+	if c.WebsocketApiURL == nil {
+		missing = append(missing, "WebsocketApiURL")
+	}
+	if c.Doer == nil {
+		missing = append(missing, "Doer")
+	}
+	if c.DialContexter == nil {
+		missing = append(missing, "DialContexter")
+	}
+	if c.Now == nil {
+		missing = append(missing, "Now")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("client is missing required fields: %v", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func copyURL(u *url.URL) *url.URL {
+	if u == nil {
+		return nil
+	}
+	out := *u
+	return &out
+}
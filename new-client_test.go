@@ -0,0 +1,142 @@
+package gobinance_test
+
+import (
+	"context"
+	"github.com/beyondallrepair/gobinance"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name                 string
+		options              []gobinance.ClientOption
+		expectedHTTPURL      string
+		expectedWebsocketURL string
+		test                 func(t *testing.T, c *gobinance.Client)
+	}{
+		{
+			name:                 "defaults",
+			expectedHTTPURL:      "https://api.binance.com",
+			expectedWebsocketURL: "wss://stream.binance.com:9443",
+			test: func(t *testing.T, c *gobinance.Client) {
+				if doer, ok := c.Doer.(*http.Client); !ok || doer.Timeout == 0 {
+					t.Errorf("expected an http.Client with a timeout but got %#v", c.Doer)
+				}
+				if _, ok := c.DialContexter.(*gobinance.GorillaDialer); !ok {
+					t.Errorf("expected a GorillaDialer but got %#v", c.DialContexter)
+				}
+				if c.Signer != nil {
+					t.Errorf("expected no signer without credentials")
+				}
+				if c.UserAgent == "" {
+					t.Errorf("expected a default user agent")
+				}
+			},
+		},
+		{
+			name:                 "testnet with credentials",
+			options:              []gobinance.ClientOption{gobinance.ClientEnvironment(gobinance.EnvironmentSpotTestnet), gobinance.ClientCredentials("key", "secret")},
+			expectedHTTPURL:      "https://testnet.binance.vision",
+			expectedWebsocketURL: "wss://testnet.binance.vision",
+			test: func(t *testing.T, c *gobinance.Client) {
+				if c.APIKey != "key" {
+					t.Errorf("unexpected api key %q", c.APIKey)
+				}
+				if signer, ok := c.Signer.(*gobinance.HMACSigner); !ok || signer.Secret != "secret" {
+					t.Errorf("unexpected signer %#v", c.Signer)
+				}
+			},
+		},
+		{
+			name:                 "binance.us",
+			options:              []gobinance.ClientOption{gobinance.ClientEnvironment(gobinance.EnvironmentBinanceUS)},
+			expectedHTTPURL:      "https://api.binance.us",
+			expectedWebsocketURL: "wss://stream.binance.us:9443",
+		},
+		{
+			name:                 "alternate cluster",
+			options:              []gobinance.ClientOption{gobinance.ClientEnvironment(gobinance.EnvironmentProductionAPI3)},
+			expectedHTTPURL:      "https://api3.binance.com",
+			expectedWebsocketURL: "wss://stream.binance.com:9443",
+		},
+		{
+			name: "overrides",
+			options: []gobinance.ClientOption{
+				gobinance.ClientUserAgent(testUserAgent),
+				gobinance.ClientRecvWindow(testRecvWindow),
+				gobinance.ClientNow(mockNow),
+				gobinance.ClientOrderIDs(&gobinance.ULIDGenerator{}),
+			},
+			expectedHTTPURL:      "https://api.binance.com",
+			expectedWebsocketURL: "wss://stream.binance.com:9443",
+			test: func(t *testing.T, c *gobinance.Client) {
+				if c.UserAgent != testUserAgent || c.RecvWindow != testRecvWindow || !c.Now().Equal(mockNow()) || c.ClientOrderIDGenerator == nil {
+					t.Errorf("expected overrides to be applied but got %#v", c)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := gobinance.NewClient(tc.options...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if u := got.HTTPApiURL.String(); u != tc.expectedHTTPURL {
+				t.Errorf("unexpected http api url. expected %v but got %v", tc.expectedHTTPURL, u)
+			}
+			if u := got.WebsocketApiURL.String(); u != tc.expectedWebsocketURL {
+				t.Errorf("unexpected websocket api url. expected %v but got %v", tc.expectedWebsocketURL, u)
+			}
+			if tc.test != nil {
+				tc.test(t, got)
+			}
+		})
+	}
+
+	t.Run("environment is copied", func(t *testing.T) {
+		t.Parallel()
+		got, err := gobinance.NewClient()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got.HTTPApiURL.Host = "example.com"
+		if gobinance.EnvironmentProduction.HTTPApiURL.Host != "api.binance.com" {
+			t.Errorf("expected modifying the client not to modify the environment")
+		}
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		t.Parallel()
+		_, err := gobinance.NewClient(gobinance.ClientDoer(nil), gobinance.ClientDialContexter(nil), gobinance.ClientEnvironment(gobinance.Environment{}))
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+		for _, field := range []string{"HTTPApiURL", "WebsocketApiURL", "Doer", "DialContexter"} {
+			if !strings.Contains(err.Error(), field) {
+				t.Errorf("expected error to report missing %v but got %v", field, err)
+			}
+		}
+		if strings.Contains(err.Error(), "Now") {
+			t.Errorf("expected Now not to be reported as missing but got %v", err)
+		}
+	})
+}
+
+func TestClient_SignedRequestWithoutSigner(t *testing.T) {
+	t.Parallel()
+	uut, err := gobinance.NewClient(gobinance.ClientNow(func() time.Time { return mockNow() }))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uut.AccountInformation(context.Background()); err == nil || !strings.Contains(err.Error(), "Signer") {
+		t.Errorf("expected an error reporting the missing signer but got %v", err)
+	}
+}
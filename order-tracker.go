@@ -0,0 +1,274 @@
+package gobinance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+const defaultOrderTrackerPollInterval = 10 * time.Second
+
+// InvalidOrderTransitionError is passed to the error handler of an OrderTracker when an update would move the
+// order into a status it cannot reach from its current status.  Such updates are ignored.
+type InvalidOrderTransitionError struct {
+	OrderID int64
+	From    OrderStatus
+	To      OrderStatus
+}
+
+// Error implements the error interface and returns a human-readable description of the error
+func (e *InvalidOrderTransitionError) Error() string {
+	return fmt.Sprintf("order %v cannot transition from %v to %v", e.OrderID, e.From, e.To)
+}
+
+// OrderTrackerOption is a function that applies optional configuration to an OrderTracker
+type OrderTrackerOption func(t *OrderTracker)
+
+// OrderTrackerPollInterval sets how often the order is queried using QueryOrderByID, as a fallback for updates
+// missed by, or unavailable due to, the user data stream.  The default is 10 seconds.
+func OrderTrackerPollInterval(d time.Duration) OrderTrackerOption {
+	return func(t *OrderTracker) {
+		t.pollInterval = d
+	}
+}
+
+// OrderTrackerErrorHandler sets a function to be called with errors encountered while tracking the order, such
+// as the user data stream disconnecting, failed queries, and *InvalidOrderTransitionError.
+func OrderTrackerErrorHandler(handle func(err error)) OrderTrackerOption {
+	return func(t *OrderTracker) {
+		t.handleErr = handle
+	}
+}
+
+// OrderTracker follows an order through its lifecycle until it reaches a terminal status, using execution
+// reports from the user data stream and falling back to polling QueryOrderByID.  Updates which would move the
+// order to a status it cannot reach from its current status are rejected.
+//
+// All methods are safe to call concurrently.
+type OrderTracker struct {
+	client       *Client
+	pollInterval time.Duration
+	handleErr    func(err error)
+	changes      chan struct{}
+	done         chan struct{}
+	doneOnce     sync.Once
+
+	mu       sync.RWMutex
+	order    SpotOrder
+	fills    []Fill
+	tradeIDs map[int64]bool
+}
+
+// NewOrderTracker creates an OrderTracker for an order placed with one of the Place...Order methods.  The order
+// is not followed until Run is called.
+func (c *Client) NewOrderTracker(result SpotOrderResult, opts ...OrderTrackerOption) *OrderTracker {
+	t := &OrderTracker{
+		client:       c,
+		pollInterval: defaultOrderTrackerPollInterval,
+		handleErr:    func(error) {},
+		changes:      make(chan struct{}, 1),
+		done:         make(chan struct{}),
+		tradeIDs:     make(map[int64]bool),
+		order: SpotOrder{
+			Symbol:             result.Symbol,
+			OrderID:            int64(result.OrderID),
+			OrderListID:        int64(result.OrderListID),
+			ClientOrderID:      result.ClientOrderID,
+			Price:              result.Price,
+			OriginalQty:        result.OrigQty,
+			ExecutedQty:        result.ExecutedQty,
+			CumulativeQuoteQty: result.CumulativeQuoteQty,
+			Status:             result.Status,
+			TimeInForce:        result.TimeInForce,
+			Type:               result.Type,
+			Side:               result.Side,
+			Time:               result.TransactTime,
+			UpdateTime:         result.TransactTime,
+		},
+	}
+	for _, o := range opts {
+		o(t)
+	}
+	for _, f := range result.Fills {
+		t.addFill(f)
+	}
+	if t.order.Status.IsTerminal() {
+		t.doneOnce.Do(func() { close(t.done) })
+	}
+	return t
+}
+
+// Run follows the order until it reaches a terminal status, returning nil, or until the context is cancelled,
+// returning the context's error.
+func (t *OrderTracker) Run(ctx context.Context) error {
+	if t.terminal() {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream := t.client.UserData(ctx)
+	defer func() {
+		// stop the stream and wait for it to shut down, so its connection is closed before returning
+		cancel()
+		for range stream {
+		}
+	}()
+
+	// events is set to nil once the stream closes, so that polling continues without it
+	events := stream
+
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+	for !t.terminal() {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.handleErr(fmt.Errorf("user data stream closed, falling back to polling"))
+				events = nil
+				continue
+			}
+			if event.Err != nil {
+				t.handleErr(fmt.Errorf("error from user data stream: %w", event.Err))
+				continue
+			}
+			if report := event.ExecutionReport; report != nil && report.OrderID == t.orderID() {
+				if report.ExecutionType == ExecutionTypeTrade {
+					t.mu.Lock()
+					t.addFill(Fill{
+						Price:           report.LastExecutedPrice,
+						Qty:             report.LastExecutedQty,
+						Commission:      report.Commission,
+						CommissionAsset: report.CommissionAsset,
+						TradeID:         report.TradeID,
+					})
+					t.mu.Unlock()
+				}
+				t.update(report.SpotOrder())
+			}
+		case <-ticker.C:
+			order, err := t.client.QueryOrderByID(ctx, t.symbol(), t.orderID())
+			if err != nil {
+				if ctx.Err() == nil {
+					t.handleErr(fmt.Errorf("error querying order: %w", err))
+				}
+				continue
+			}
+			t.update(order)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Wait blocks until the order reaches a terminal status, returning its final state, or until the context is
+// cancelled, returning the context's error.  Run must be called for the order to progress.
+func (t *OrderTracker) Wait(ctx context.Context) (SpotOrder, error) {
+	select {
+	case <-t.done:
+		return t.Order(), nil
+	case <-ctx.Done():
+		return SpotOrder{}, ctx.Err()
+	}
+}
+
+// Done returns a channel which is closed once the order reaches a terminal status
+func (t *OrderTracker) Done() <-chan struct{} {
+	return t.done
+}
+
+// Changes returns a channel which receives a value whenever the order changes.  Notifications are coalesced, so
+// a single notification may represent several changes.
+func (t *OrderTracker) Changes() <-chan struct{} {
+	return t.changes
+}
+
+// Order returns the latest known state of the order
+func (t *OrderTracker) Order() SpotOrder {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.order
+}
+
+// Fills returns the fills of the order received so far, in the order they were received.
+//
+// Fills are only received in the result of placing the order and in execution reports from the user data stream.
+// Fills made while the stream is down are not recorded, even though polling reflects them in the order's
+// ExecutedQty and Status, so the fills returned may total less than the order's ExecutedQty.
+func (t *OrderTracker) Fills() []Fill {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	out := make([]Fill, len(t.fills))
+	copy(out, t.fills)
+	return out
+}
+
+// AveragePrice returns the average price at which the order has been filled, or false if nothing has been filled
+func (t *OrderTracker) AveragePrice() (*big.Float, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.order.ExecutedQty == nil || t.order.ExecutedQty.Sign() == 0 || t.order.CumulativeQuoteQty == nil {
+		return nil, false
+	}
+	return new(big.Float).Quo(t.order.CumulativeQuoteQty, t.order.ExecutedQty), true
+}
+
+func (t *OrderTracker) orderID() int64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.order.OrderID
+}
+
+func (t *OrderTracker) symbol() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.order.Symbol
+}
+
+func (t *OrderTracker) terminal() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.order.Status.IsTerminal()
+}
+
+// addFill records a fill, ignoring fills which have already been recorded.  The caller must hold the lock.
+func (t *OrderTracker) addFill(f Fill) {
+	if f.TradeID != 0 {
+		if t.tradeIDs[f.TradeID] {
+			return
+		}
+		t.tradeIDs[f.TradeID] = true
+	}
+	t.fills = append(t.fills, f)
+}
+
+// update replaces the state of the order, provided the new state is not older than the current one and its
+// status can be reached from the current status.
+func (t *OrderTracker) update(order SpotOrder) {
+	t.mu.Lock()
+	current := t.order
+	if order.UpdateTime.Before(current.UpdateTime) ||
+		(order.ExecutedQty != nil && current.ExecutedQty != nil && order.ExecutedQty.Cmp(current.ExecutedQty) < 0) {
+		// stale
+		t.mu.Unlock()
+		return
+	}
+	// the status is unknown when the order was placed with OrderResponseTypeAck
+	if current.Status != "" && order.Status != current.Status && !current.Status.CanTransitionTo(order.Status) {
+		t.mu.Unlock()
+		t.handleErr(&InvalidOrderTransitionError{OrderID: current.OrderID, From: current.Status, To: order.Status})
+		return
+	}
+	t.order = order
+	t.mu.Unlock()
+
+	select {
+	case t.changes <- struct{}{}:
+	default:
+	}
+	if order.Status.IsTerminal() {
+		t.doneOnce.Do(func() { close(t.done) })
+	}
+}
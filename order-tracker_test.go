@@ -0,0 +1,191 @@
+package gobinance_test
+
+import (
+	"context"
+	"errors"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOrderTracker_Run(t *testing.T) {
+	t.Parallel()
+	newOrder := gobinance.SpotOrderResult{
+		Symbol:       "ETHBTC",
+		OrderID:      5,
+		OrderListID:  -1,
+		Status:       gobinance.OrderStatusNew,
+		Side:         gobinance.OrderSideBuy,
+		Type:         gobinance.OrderTypeLimit,
+		TransactTime: time.Unix(1500000000, 0),
+	}
+
+	testCases := []struct {
+		name string
+		// result is the order being tracked
+		result gobinance.SpotOrderResult
+		// messages are sent on the user data stream.  The stream is disconnected when messages is nil.
+		messages       []string
+		queries        []string
+		expectedStatus gobinance.OrderStatus
+		expectedFills  []gobinance.Fill
+		expectedAvg    string
+		expectedErrs   []string
+	}{
+		{
+			name:   "fills from the user data stream",
+			result: newOrder,
+			messages: []string{
+				`{"e":"executionReport","E":1500000001000,"s":"ETHBTC","i":6,"x":"TRADE","X":"FILLED","z":"1","Z":"1","T":1500000001000}`,
+				`{"e":"executionReport","E":1500000001000,"s":"ETHBTC","i":5,"x":"TRADE","X":"PARTIALLY_FILLED","t":11,"l":"0.4","L":"0.1","n":"0.001","N":"BNB","z":"0.4","Z":"0.04","T":1500000001000}`,
+				`{"e":"executionReport","E":1500000001000,"s":"ETHBTC","i":5,"x":"TRADE","X":"PARTIALLY_FILLED","t":11,"l":"0.4","L":"0.1","n":"0.001","N":"BNB","z":"0.4","Z":"0.04","T":1500000001000}`,
+				`{"e":"executionReport","E":1500000002000,"s":"ETHBTC","i":5,"x":"TRADE","X":"FILLED","t":12,"l":"0.6","L":"0.2","n":"0.002","N":"BNB","z":"1","Z":"0.16","T":1500000002000}`,
+			},
+			expectedStatus: gobinance.OrderStatusFilled,
+			expectedFills: []gobinance.Fill{
+				{Price: mustParseBigFloat(t, "0.1"), Qty: mustParseBigFloat(t, "0.4"), Commission: mustParseBigFloat(t, "0.001"), CommissionAsset: "BNB", TradeID: 11},
+				{Price: mustParseBigFloat(t, "0.2"), Qty: mustParseBigFloat(t, "0.6"), Commission: mustParseBigFloat(t, "0.002"), CommissionAsset: "BNB", TradeID: 12},
+			},
+			expectedAvg: "0.16",
+		},
+		{
+			name:   "polling when the stream is unavailable",
+			result: newOrder,
+			queries: []string{
+				`{"symbol":"ETHBTC","orderId":5,"status":"PARTIALLY_FILLED","executedQty":"0.5","cummulativeQuoteQty":"0.05","updateTime":1500000001000}`,
+				`{"symbol":"ETHBTC","orderId":5,"status":"CANCELED","executedQty":"0.5","cummulativeQuoteQty":"0.05","updateTime":1500000002000}`,
+			},
+			expectedStatus: gobinance.OrderStatusCanceled,
+			expectedFills:  []gobinance.Fill{},
+			expectedAvg:    "0.1",
+			expectedErrs:   []string{"error from user data stream", "user data stream closed"},
+		},
+		{
+			name: "invalid transitions are rejected",
+			result: gobinance.SpotOrderResult{
+				Symbol:       "ETHBTC",
+				OrderID:      5,
+				Status:       gobinance.OrderStatusPartiallyFilled,
+				ExecutedQty:  mustParseBigFloat(t, "0.5"),
+				TransactTime: time.Unix(1500000000, 0),
+				Fills: []gobinance.Fill{
+					{Price: mustParseBigFloat(t, "0.1"), Qty: mustParseBigFloat(t, "0.5"), TradeID: 11},
+				},
+			},
+			messages: []string{
+				`{"e":"executionReport","E":1500000001000,"s":"ETHBTC","i":5,"x":"NEW","X":"NEW","z":"0.5","Z":"0.05","T":1500000001000}`,
+				`{"e":"executionReport","E":1500000002000,"s":"ETHBTC","i":5,"x":"EXPIRED","X":"EXPIRED","z":"0.5","Z":"0.05","T":1500000002000}`,
+			},
+			expectedStatus: gobinance.OrderStatusExpired,
+			expectedFills: []gobinance.Fill{
+				{Price: mustParseBigFloat(t, "0.1"), Qty: mustParseBigFloat(t, "0.5"), TradeID: 11},
+			},
+			expectedAvg:  "0.1",
+			expectedErrs: []string{"cannot transition from PARTIALLY_FILLED to NEW"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDoer := mockRoutedDoer(t, ctrl, map[string][]string{
+				"/api/v3/order": tc.queries,
+			})
+			var dialer gobinance.DialContexter
+			if tc.messages != nil {
+				conn := newFakeStreamConnection(ctrl, nil)
+				for _, msg := range tc.messages {
+					conn.incoming <- msg
+				}
+				mockDialer := mock_gobinance.NewMockDialContexter(ctrl)
+				mockDialer.EXPECT().DialContext(gomock.Any(), testWebsocketBaseURL+"/ws/test-listen-key", nil).Return(conn, nil, nil)
+				dialer = mockDialer
+			} else {
+				dialer = mockWebsocketMessages(ctrl, testWebsocketBaseURL+"/ws/test-listen-key")
+			}
+			client := newTestUserDataClient(ctrl, mockDoer, dialer)
+
+			// only poll when the test case expects it, so events are taken from the stream
+			pollInterval := time.Hour
+			if tc.queries != nil {
+				pollInterval = 10 * time.Millisecond
+			}
+			var gotErrs []error
+			uut := client.NewOrderTracker(tc.result,
+				gobinance.OrderTrackerPollInterval(pollInterval),
+				gobinance.OrderTrackerErrorHandler(func(err error) {
+					gotErrs = append(gotErrs, err)
+				}),
+			)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := uut.Run(ctx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := uut.Wait(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error waiting for order: %v", err)
+			}
+			if got.Status != tc.expectedStatus {
+				t.Errorf("unexpected status. expected %v but got %v", tc.expectedStatus, got.Status)
+			}
+			if diff := cmp.Diff(tc.expectedFills, uut.Fills(), bigFloatComparer); diff != "" {
+				t.Errorf("unexpected fills.\n%s", diff)
+			}
+			if avg, ok := uut.AveragePrice(); !ok || avg.Cmp(mustParseBigFloat(t, tc.expectedAvg)) != 0 {
+				t.Errorf("unexpected average price. expected %v but got %v", tc.expectedAvg, avg)
+			}
+			select {
+			case <-uut.Changes():
+			default:
+				t.Errorf("expected a change notification")
+			}
+
+			if len(gotErrs) != len(tc.expectedErrs) {
+				t.Fatalf("expected errors containing %q but got %v", tc.expectedErrs, gotErrs)
+			}
+			for i, expected := range tc.expectedErrs {
+				if !strings.Contains(gotErrs[i].Error(), expected) {
+					t.Errorf("expected error containing %q but got %v", expected, gotErrs[i])
+				}
+			}
+		})
+	}
+
+	t.Run("already terminal", func(t *testing.T) {
+		t.Parallel()
+		result := newOrder
+		result.Status = gobinance.OrderStatusRejected
+		uut := (&gobinance.Client{}).NewOrderTracker(result)
+		if err := uut.Run(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		select {
+		case <-uut.Done():
+		default:
+			t.Errorf("expected tracker to be done")
+		}
+		if _, ok := uut.AveragePrice(); ok {
+			t.Errorf("expected no average price for an unfilled order")
+		}
+	})
+
+	t.Run("wait cancelled", func(t *testing.T) {
+		t.Parallel()
+		uut := (&gobinance.Client{}).NewOrderTracker(newOrder)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := uut.Wait(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,336 @@
+package gobinance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPaperTraderResyncDelay = time.Second
+	// paperTraderDepthLevels is the number of levels of the order book that market orders are matched against
+	paperTraderDepthLevels = DepthLevels20
+)
+
+// PaperTraderOption is a function that applies optional configuration to a PaperTrader
+type PaperTraderOption func(p *PaperTrader)
+
+// PaperTraderSymbol adds a symbol which may be traded, along with its base and quote assets.  Market data is
+// only followed for the symbols added.
+func PaperTraderSymbol(symbol string, baseAsset string, quoteAsset string) PaperTraderOption {
+	return func(p *PaperTrader) {
+		p.exchange.AddSymbol(symbol, baseAsset, quoteAsset)
+	}
+}
+
+// PaperTraderBalance sets the initial free balance of an asset.  Balances not set are zero.
+func PaperTraderBalance(asset string, free *big.Float) PaperTraderOption {
+	return func(p *PaperTrader) {
+		p.exchange.SetBalance(asset, free)
+	}
+}
+
+// PaperTraderFees sets the fee rates charged on maker and taker fills, e.g. 0.001 for 0.1%.  Fees are charged in
+// the asset received.  The default is 0.1% for both.
+func PaperTraderFees(maker *big.Float, taker *big.Float) PaperTraderOption {
+	return func(p *PaperTrader) {
+		p.exchange.makerFee = maker
+		p.exchange.takerFee = taker
+	}
+}
+
+// PaperTraderSlippage sets the fraction of the price, e.g. 0.0005 for 0.05%, by which fills taking liquidity are
+// made worse.  Limit orders never fill beyond their price.  The default is no slippage.
+func PaperTraderSlippage(fraction *big.Float) PaperTraderOption {
+	return func(p *PaperTrader) {
+		p.exchange.slippage = fraction
+	}
+}
+
+// PaperTraderLatency sets a delay applied before each request is processed, simulating the time taken for a
+// request to reach binance.  The default is no delay.
+func PaperTraderLatency(d time.Duration) PaperTraderOption {
+	return func(p *PaperTrader) {
+		p.latency = d
+	}
+}
+
+// PaperTraderResyncDelay sets how long to wait before reconnecting a market data stream after an error.  The
+// default is 1 second.
+func PaperTraderResyncDelay(d time.Duration) PaperTraderOption {
+	return func(p *PaperTrader) {
+		p.resyncDelay = d
+	}
+}
+
+// PaperTraderErrorHandler sets a function to be called with each error that causes a market data stream to be
+// reconnected
+func PaperTraderErrorHandler(handle func(err error)) PaperTraderOption {
+	return func(p *PaperTrader) {
+		p.handleErr = handle
+	}
+}
+
+// PaperTrader simulates the execution of spot orders against live market data, maintaining a virtual balance
+// sheet in place of the account's.  Its order and account methods have the same signatures and results as those
+// of Client, so strategies can be run against either, and errors such as insufficient balance are returned as
+// *HttpError with the codes binance would use.
+//
+// Orders taking liquidity fill against the top 20 levels of the order book, or the last traded price if the book
+// is not yet known, with slippage applied.  Market orders which exhaust the known levels fill the remainder at
+// the price of the last level.  Orders resting on the book fill at their price when a trade is made at or
+// through it, up to the traded quantity, without regard to their position in the queue.  Only limit, limit
+// maker and market orders are supported.
+//
+// All methods are safe to call concurrently.
+type PaperTrader struct {
+	client      *Client
+	exchange    *SimulatedExchange
+	latency     time.Duration
+	resyncDelay time.Duration
+	handleErr   func(err error)
+}
+
+// NewPaperTrader creates a PaperTrader which follows market data using the client.  Orders taking liquidity
+// cannot be filled until Run is called and market data has been received.
+func (c *Client) NewPaperTrader(opts ...PaperTraderOption) *PaperTrader {
+	p := &PaperTrader{
+		client:      c,
+		exchange:    NewSimulatedExchange(),
+		resyncDelay: defaultPaperTraderResyncDelay,
+		handleErr:   func(error) {},
+	}
+	for _, o := range opts {
+		o(p)
+	}
+	return p
+}
+
+// Run follows the trade and partial depth streams of each symbol, reconnecting upon errors.  It blocks until the
+// context is cancelled, returning the context's error.
+func (p *PaperTrader) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for symbol := range p.exchange.symbols {
+		symbol := symbol
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			p.follow(ctx, func(ctx context.Context) error {
+				return p.followTrades(ctx, symbol)
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			p.follow(ctx, func(ctx context.Context) error {
+				return p.followDepth(ctx, symbol)
+			})
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// follow calls stream until the context is cancelled, waiting for the resync delay after each error
+func (p *PaperTrader) follow(ctx context.Context, stream func(ctx context.Context) error) {
+	for {
+		err := stream(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		p.handleErr(err)
+
+		t := time.NewTimer(p.resyncDelay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return
+		}
+	}
+}
+
+func (p *PaperTrader) followTrades(ctx context.Context, symbol string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	trades := p.client.Trades(ctx, symbol)
+	defer func() {
+		// stop the stream and wait for it to shut down, so its connection is closed before returning
+		cancel()
+		for range trades {
+		}
+	}()
+	for trade := range trades {
+		if trade.Err != nil {
+			return fmt.Errorf("error from trade stream: %w", trade.Err)
+		}
+		p.exchange.Trade(symbol, trade.Price, trade.Quantity, trade.TradeTime)
+	}
+	return fmt.Errorf("trade stream closed")
+}
+
+func (p *PaperTrader) followDepth(ctx context.Context, symbol string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	updates := p.client.PartialDepth(ctx, symbol, paperTraderDepthLevels, DepthUpdateSpeed100ms)
+	defer func() {
+		// stop the stream and wait for it to shut down, so its connection is closed before returning
+		cancel()
+		for range updates {
+		}
+	}()
+	for update := range updates {
+		if update.Err != nil {
+			return fmt.Errorf("error from partial depth stream: %w", update.Err)
+		}
+		p.exchange.Depth(symbol, update.Bids, update.Asks)
+	}
+	return fmt.Errorf("partial depth stream closed")
+}
+
+// delay waits for the configured latency
+func (p *PaperTrader) delay(ctx context.Context) error {
+	if p.latency <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(p.latency)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *PaperTrader) now() time.Time {
+	if p.client.Now != nil {
+		return p.client.Now()
+	}
+	return time.Now()
+}
+
+// PlaceLimitOrder places a simulated limit order
+func (p *PaperTrader) PlaceLimitOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, price *big.Float, tif TimeInForce, opts ...SpotOrderOption) (SpotOrderResult, error) {
+	return p.placeOrder(ctx, spotOrderInput{
+		Type:        OrderTypeLimit,
+		Symbol:      symbol,
+		Side:        side,
+		Quantity:    qty,
+		Price:       price,
+		TimeInForce: tif,
+	}, opts)
+}
+
+// PlaceLimitMakerOrder places a simulated limit order which is rejected if it would take liquidity
+func (p *PaperTrader) PlaceLimitMakerOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, price *big.Float, opts ...SpotOrderOption) (SpotOrderResult, error) {
+	return p.placeOrder(ctx, spotOrderInput{
+		Type:     OrderTypeLimitMaker,
+		Symbol:   symbol,
+		Side:     side,
+		Quantity: qty,
+		Price:    price,
+	}, opts)
+}
+
+// PlaceSpotMarketOrder places a simulated market order.  See Client.PlaceSpotMarketOrder for the meaning of qty
+// and asset.
+func (p *PaperTrader) PlaceSpotMarketOrder(ctx context.Context, symbol string, side OrderSide, qty *big.Float, asset QuantityAsset, opts ...SpotOrderOption) (SpotOrderResult, error) {
+	input := spotOrderInput{
+		Type:   OrderTypeMarket,
+		Symbol: symbol,
+		Side:   side,
+	}
+	switch asset {
+	case QuantityAssetBase:
+		input.Quantity = qty
+	case QuantityAssetQuote:
+		input.QuoteOrderQty = qty
+	default:
+		return SpotOrderResult{}, fmt.Errorf("unknown asset value '%v'", asset)
+	}
+	return p.placeOrder(ctx, input, opts)
+}
+
+func (p *PaperTrader) placeOrder(ctx context.Context, input spotOrderInput, opts []SpotOrderOption) (SpotOrderResult, error) {
+	applySpotOrderOptions(&input, opts...)
+	if err := p.client.assignClientOrderID(&input); err != nil {
+		return SpotOrderResult{}, err
+	}
+	if err := p.delay(ctx); err != nil {
+		return SpotOrderResult{}, err
+	}
+	return p.exchange.place(input, p.now())
+}
+
+// CancelOrderByOrderID cancels a simulated order with the given order ID
+func (p *PaperTrader) CancelOrderByOrderID(ctx context.Context, symbol string, orderID int64, opts ...CancelSpotOrderOption) (CancelSpotOrderResult, error) {
+	return p.cancelOrder(ctx, cancelSpotOrderInput{
+		Symbol:  symbol,
+		OrderID: orderID,
+	}, opts)
+}
+
+// CancelOrderByClientOrderID cancels a simulated order with the given client order ID
+func (p *PaperTrader) CancelOrderByClientOrderID(ctx context.Context, symbol string, clientOrderID string, opts ...CancelSpotOrderOption) (CancelSpotOrderResult, error) {
+	return p.cancelOrder(ctx, cancelSpotOrderInput{
+		Symbol:            symbol,
+		OrigClientOrderID: clientOrderID,
+	}, opts)
+}
+
+func (p *PaperTrader) cancelOrder(ctx context.Context, input cancelSpotOrderInput, opts []CancelSpotOrderOption) (CancelSpotOrderResult, error) {
+	applyCancelSpotOrderOptions(&input, opts...)
+	if err := p.delay(ctx); err != nil {
+		return CancelSpotOrderResult{}, err
+	}
+	return p.exchange.cancel(input, p.now())
+}
+
+// QueryOrderByID returns the simulated order with the given order ID
+func (p *PaperTrader) QueryOrderByID(ctx context.Context, symbol string, orderID int64, opts ...QueryOrderOption) (SpotOrder, error) {
+	return p.queryOrder(ctx, queryOrderInput{
+		Symbol:  symbol,
+		OrderID: orderID,
+	}, opts)
+}
+
+// QueryOrderByClientID returns the simulated order with the given client order ID
+func (p *PaperTrader) QueryOrderByClientID(ctx context.Context, symbol string, clientOrderID string, opts ...QueryOrderOption) (SpotOrder, error) {
+	return p.queryOrder(ctx, queryOrderInput{
+		Symbol:            symbol,
+		OrigClientOrderID: clientOrderID,
+	}, opts)
+}
+
+func (p *PaperTrader) queryOrder(ctx context.Context, input queryOrderInput, opts []QueryOrderOption) (SpotOrder, error) {
+	applyQueryOrderOptions(&input, opts)
+	if err := p.delay(ctx); err != nil {
+		return SpotOrder{}, err
+	}
+	return p.exchange.query(input)
+}
+
+// OpenSpotOrdersForSymbol returns the open simulated orders on the symbol
+func (p *PaperTrader) OpenSpotOrdersForSymbol(ctx context.Context, symbol string, opts ...OpenOrdersOptions) ([]SpotOrder, error) {
+	if err := p.delay(ctx); err != nil {
+		return nil, err
+	}
+	return p.exchange.OpenOrders(symbol), nil
+}
+
+// AllOpenSpotOrders returns all open simulated orders
+func (p *PaperTrader) AllOpenSpotOrders(ctx context.Context, opts ...OpenOrdersOptions) ([]SpotOrder, error) {
+	if err := p.delay(ctx); err != nil {
+		return nil, err
+	}
+	return p.exchange.OpenOrders(""), nil
+}
+
+// AccountInformation returns the simulated balances.  The commission rates are given in basis points, as binance
+// does.
+func (p *PaperTrader) AccountInformation(ctx context.Context) (AccountInformation, error) {
+	if err := p.delay(ctx); err != nil {
+		return AccountInformation{}, err
+	}
+	return p.exchange.AccountInformation(), nil
+}
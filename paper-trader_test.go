@@ -0,0 +1,158 @@
+package gobinance_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	"github.com/google/go-cmp/cmp"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMarketData is a DialContexter whose connections read the messages sent on the channel for their URL
+type fakeMarketData map[string]chan string
+
+func (f fakeMarketData) DialContext(ctx context.Context, url string, hdr http.Header) (gobinance.NextReaderCloser, *http.Response, error) {
+	messages, ok := f[url]
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected connection to %v", url)
+	}
+	return &fakeMarketDataConnection{messages: messages, closed: make(chan struct{})}, nil, nil
+}
+
+type fakeMarketDataConnection struct {
+	messages  chan string
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (f *fakeMarketDataConnection) NextReader() (int, io.Reader, error) {
+	select {
+	case msg := <-f.messages:
+		return 1, strings.NewReader(msg), nil
+	case <-f.closed:
+		return 0, nil, io.EOF
+	}
+}
+
+func (f *fakeMarketDataConnection) Close() error {
+	f.closeOnce.Do(func() { close(f.closed) })
+	return nil
+}
+
+// eventually calls check until it returns true, failing the test if it does not within a second
+func eventually(t *testing.T, check func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !check() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met within a second")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPaperTrader(t *testing.T) {
+	t.Parallel()
+	trades, depth := make(chan string), make(chan string)
+	wsURL, _ := url.Parse(testWebsocketBaseURL)
+	client := &gobinance.Client{
+		WebsocketApiURL: wsURL,
+		DialContexter: fakeMarketData{
+			testWebsocketBaseURL + "/ws/btcusdt@trade":         trades,
+			testWebsocketBaseURL + "/ws/btcusdt@depth20@100ms": depth,
+		},
+		Now: mockNow,
+	}
+	uut := client.NewPaperTrader(
+		gobinance.PaperTraderSymbol("BTCUSDT", "BTC", "USDT"),
+		gobinance.PaperTraderBalance("USDT", big.NewFloat(1000)),
+		gobinance.PaperTraderFees(new(big.Float), big.NewFloat(0.5)),
+		gobinance.PaperTraderLatency(time.Millisecond),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- uut.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("expected Run to return context.Canceled but got %v", err)
+		}
+	}()
+
+	depth <- `{"lastUpdateId":1,"bids":[["99","1"]],"asks":[["100","1"],["101","1"]]}`
+	// market orders are rejected until market data has been received
+	var result gobinance.SpotOrderResult
+	eventually(t, func() bool {
+		var err error
+		result, err = uut.PlaceSpotMarketOrder(ctx, "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(1.5), gobinance.QuantityAssetBase)
+		return err == nil
+	})
+	expectedFills := []gobinance.Fill{
+		{Price: big.NewFloat(100), Qty: big.NewFloat(1), Commission: big.NewFloat(0.5), CommissionAsset: "BTC", TradeID: 1},
+		{Price: big.NewFloat(101), Qty: big.NewFloat(0.5), Commission: big.NewFloat(0.25), CommissionAsset: "BTC", TradeID: 2},
+	}
+	if diff := cmp.Diff(expectedFills, result.Fills, bigFloatComparer); diff != "" {
+		t.Errorf("unexpected fills.\n%s", diff)
+	}
+	if result.Status != gobinance.OrderStatusFilled || !result.TransactTime.Equal(mockNow()) {
+		t.Errorf("unexpected result %#v", result)
+	}
+
+	result, err := uut.PlaceLimitOrder(ctx, "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(1), big.NewFloat(90), gobinance.TimeInForceGoodTilCanceled, gobinance.SpotClientOrderID("rest"))
+	if err != nil || result.Status != gobinance.OrderStatusNew {
+		t.Fatalf("unexpected result %#v, %v", result, err)
+	}
+	open, err := uut.OpenSpotOrdersForSymbol(ctx, "BTCUSDT")
+	if err != nil || len(open) != 1 || open[0].ClientOrderID != "rest" {
+		t.Errorf("unexpected open orders %#v, %v", open, err)
+	}
+
+	trades <- `{"e":"trade","E":1500000000000,"s":"BTCUSDT","t":1,"p":"90","q":"5","T":1500000000000}`
+	eventually(t, func() bool {
+		order, err := uut.QueryOrderByClientID(ctx, "BTCUSDT", "rest")
+		return err == nil && order.Status == gobinance.OrderStatusFilled
+	})
+
+	info, err := uut.AccountInformation(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedBalances := map[string]gobinance.Balance{
+		"BTC":  {Asset: "BTC", Free: big.NewFloat(1.75), Locked: big.NewFloat(0)},
+		"USDT": {Asset: "USDT", Free: big.NewFloat(759.5), Locked: big.NewFloat(0)},
+	}
+	if diff := cmp.Diff(expectedBalances, info.Balances, bigFloatComparer); diff != "" {
+		t.Errorf("unexpected balances.\n%s", diff)
+	}
+	if info.MakerCommission != 0 || info.TakerCommission != 5000 {
+		t.Errorf("unexpected commissions %v and %v", info.MakerCommission, info.TakerCommission)
+	}
+
+	_, err = uut.CancelOrderByClientOrderID(ctx, "BTCUSDT", "rest")
+	isHttpError(400, -2011)(t, err)
+	_, err = uut.PlaceSpotMarketOrder(ctx, "BTCUSDT", gobinance.OrderSideSell, big.NewFloat(2), gobinance.QuantityAssetBase)
+	isHttpError(400, -2010)(t, err)
+}
+
+func TestPaperTrader_Latency(t *testing.T) {
+	t.Parallel()
+	uut := (&gobinance.Client{}).NewPaperTrader(
+		gobinance.PaperTraderSymbol("BTCUSDT", "BTC", "USDT"),
+		gobinance.PaperTraderLatency(time.Hour),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := uut.AccountInformation(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the request to be delayed past the deadline but got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package gobinance
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"strings"
+	"testing"
+)
+
+func TestDecryptPKCS8_IterationCount(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		iterations int
+	}{
+		{name: "zero", iterations: 0},
+		{name: "negative", iterations: -1},
+		{name: "above the limit", iterations: maxPBKDF2Iterations + 1},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			der := encryptedPKCS8WithIterations(t, tc.iterations)
+			_, err := decryptPKCS8(der, []byte("secret"))
+			if err == nil || !strings.Contains(err.Error(), "iteration count") {
+				t.Errorf("expected the iteration count to be rejected but got %v", err)
+			}
+		})
+	}
+}
+
+// encryptedPKCS8WithIterations returns an encrypted PKCS #8 key using PBES2 with PBKDF2 and AES-256-CBC, whose key
+// derivation uses the given number of iterations
+func encryptedPKCS8WithIterations(t *testing.T, iterations int) []byte {
+	t.Helper()
+	mustMarshal := func(v interface{}) []byte {
+		bs, err := asn1.Marshal(v)
+		if err != nil {
+			t.Fatalf("error marshalling %T: %v", v, err)
+		}
+		return bs
+	}
+	kdf := pbkdf2Params{
+		Salt:           []byte("saltsalt"),
+		IterationCount: iterations,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	}
+	params := pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPBKDF2,
+			Parameters: asn1.RawValue{FullBytes: mustMarshal(kdf)},
+		},
+		EncryptionScheme: pkix.AlgorithmIdentifier{
+			Algorithm:  oidAES256CBC,
+			Parameters: asn1.RawValue{FullBytes: mustMarshal(make([]byte, 16))},
+		},
+	}
+	return mustMarshal(encryptedPrivateKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPBES2,
+			Parameters: asn1.RawValue{FullBytes: mustMarshal(params)},
+		},
+		EncryptedData: make([]byte, 32),
+	})
+}
@@ -0,0 +1,254 @@
+package gobinance
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// archiveMicrosThreshold is the smallest timestamp in an archive treated as microseconds rather than milliseconds.
+// It is around the year 5000 in milliseconds, and 1973 in microseconds.
+const archiveMicrosThreshold = 100000000000000
+
+// ErrChecksumMismatch is returned when an archive's SHA-256 hash does not match its checksum file
+var ErrChecksumMismatch = errors.New("archive checksum mismatch")
+
+// VerifyArchiveChecksum checks that the SHA-256 hash of an archive downloaded from the binance public data
+// portal matches the accompanying .CHECKSUM file, which holds the hex encoded hash followed by the archive's name.
+// It returns an error wrapping ErrChecksumMismatch if the hashes differ.
+func VerifyArchiveChecksum(archive io.Reader, checksum io.Reader) error {
+	line, err := bufio.NewReader(checksum).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("error reading checksum: %w", err)
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return fmt.Errorf("checksum file is empty")
+	}
+	expected, err := hex.DecodeString(fields[0])
+	if err != nil {
+		return fmt.Errorf("error decoding checksum: %w", err)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, archive); err != nil {
+		return fmt.Errorf("error reading archive: %w", err)
+	}
+	if actual := h.Sum(nil); !bytes.Equal(expected, actual) {
+		return fmt.Errorf("%w: expected %x but got %x", ErrChecksumMismatch, expected, actual)
+	}
+	return nil
+}
+
+// OpenArchive opens a zip archive downloaded from the binance public data portal, returning the CSV file it
+// contains.  If a checksum file with the same path and a .CHECKSUM suffix exists, the archive is verified
+// against it first.  The returned reader must be closed.
+func OpenArchive(path string) (io.ReadCloser, error) {
+	checksum, err := os.Open(path + ".CHECKSUM")
+	switch {
+	case err == nil:
+		defer checksum.Close()
+		archive, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		err = VerifyArchiveChecksum(archive, checksum)
+		archive.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error verifying %v: %w", path, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	var csvFiles []*zip.File
+	for _, f := range zr.File {
+		if strings.HasSuffix(strings.ToLower(f.Name), ".csv") {
+			csvFiles = append(csvFiles, f)
+		}
+	}
+	if len(csvFiles) != 1 {
+		zr.Close()
+		return nil, fmt.Errorf("expected a single csv file in %v but found %v", path, len(csvFiles))
+	}
+	rc, err := csvFiles[0].Open()
+	if err != nil {
+		zr.Close()
+		return nil, err
+	}
+	return &archiveFile{ReadCloser: rc, archive: zr}, nil
+}
+
+// archiveFile is a file within a zip archive, which closes the archive when it is closed
+type archiveFile struct {
+	io.ReadCloser
+	archive *zip.ReadCloser
+}
+
+func (a *archiveFile) Close() error {
+	err := a.ReadCloser.Close()
+	if archiveErr := a.archive.Close(); err == nil {
+		err = archiveErr
+	}
+	return err
+}
+
+// ReadKlineCSV reads klines from a klines CSV file of the binance public data portal
+func ReadKlineCSV(r io.Reader) ([]Kline, error) {
+	var out []Kline
+	err := readArchiveCSV(r, 11, func(p *archiveRecordParser) {
+		out = append(out, Kline{
+			OpenTime:            p.time(0),
+			Open:                p.float(1),
+			High:                p.float(2),
+			Low:                 p.float(3),
+			Close:               p.float(4),
+			Volume:              p.float(5),
+			CloseTime:           p.time(6),
+			QuoteVolume:         p.float(7),
+			NumberOfTrades:      p.int(8),
+			TakerBuyBaseVolume:  p.float(9),
+			TakerBuyQuoteVolume: p.float(10),
+		})
+	})
+	return out, err
+}
+
+// ReadTradeCSV reads the trades of the symbol from a trades CSV file of the binance public data portal.  The
+// events have the trade time as their event time, and no buyer or seller order IDs, which the archives do not
+// hold.
+func ReadTradeCSV(symbol string, r io.Reader) ([]TradeEvent, error) {
+	var out []TradeEvent
+	err := readArchiveCSV(r, 6, func(p *archiveRecordParser) {
+		tradeTime := p.time(4)
+		out = append(out, TradeEvent{
+			Event:        "trade",
+			Time:         tradeTime,
+			Symbol:       symbol,
+			TradeID:      p.int(0),
+			Price:        p.float(1),
+			Quantity:     p.float(2),
+			TradeTime:    tradeTime,
+			IsBuyerMaker: p.bool(5),
+		})
+	})
+	return out, err
+}
+
+// ReadAggTradeCSV reads aggregate trades from an aggTrades CSV file of the binance public data portal
+func ReadAggTradeCSV(r io.Reader) ([]AggTrade, error) {
+	var out []AggTrade
+	err := readArchiveCSV(r, 7, func(p *archiveRecordParser) {
+		a := AggTrade{
+			AggTradeID:   p.int(0),
+			Price:        p.float(1),
+			Quantity:     p.float(2),
+			FirstTradeID: p.int(3),
+			LastTradeID:  p.int(4),
+			Time:         p.time(5),
+			IsBuyerMaker: p.bool(6),
+		}
+		if len(p.record) > 7 {
+			a.IsBestMatch = p.bool(7)
+		}
+		out = append(out, a)
+	})
+	return out, err
+}
+
+// readArchiveCSV calls parse with each record of a CSV file, skipping the header row present in some files.
+// Records with fewer than minFields fields are an error.
+func readArchiveCSV(r io.Reader, minFields int, parse func(p *archiveRecordParser)) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if line == 1 && !isArchiveNumber(record[0]) {
+			continue
+		}
+		if len(record) < minFields {
+			return fmt.Errorf("line %v: expected at least %v fields but got %v", line, minFields, len(record))
+		}
+		p := archiveRecordParser{record: record}
+		parse(&p)
+		if p.err != nil {
+			return fmt.Errorf("line %v: %w", line, p.err)
+		}
+	}
+}
+
+func isArchiveNumber(s string) bool {
+	_, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	return err == nil
+}
+
+// archiveRecordParser parses the fields of a CSV record, keeping the first error encountered
+type archiveRecordParser struct {
+	record []string
+	err    error
+}
+
+func (p *archiveRecordParser) field(i int) string {
+	return strings.TrimSpace(p.record[i])
+}
+
+func (p *archiveRecordParser) setErr(i int, err error) {
+	if p.err == nil {
+		p.err = fmt.Errorf("field %v: %w", i+1, err)
+	}
+}
+
+func (p *archiveRecordParser) int(i int) int64 {
+	v, err := strconv.ParseInt(p.field(i), 10, 64)
+	if err != nil {
+		p.setErr(i, err)
+	}
+	return v
+}
+
+func (p *archiveRecordParser) float(i int) *big.Float {
+	v, _, err := new(big.Float).Parse(p.field(i), 10)
+	if err != nil {
+		p.setErr(i, err)
+	}
+	return v
+}
+
+func (p *archiveRecordParser) bool(i int) bool {
+	v, err := strconv.ParseBool(p.field(i))
+	if err != nil {
+		p.setErr(i, err)
+	}
+	return v
+}
+
+// time parses a timestamp, which is given in milliseconds in older archives and microseconds in newer ones
+func (p *archiveRecordParser) time(i int) time.Time {
+	v := p.int(i)
+	if v >= archiveMicrosThreshold {
+		return time.Unix(0, v*int64(time.Microsecond)).UTC()
+	}
+	return millisToTime(v)
+}
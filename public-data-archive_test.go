@@ -0,0 +1,252 @@
+package gobinance_test
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	"github.com/google/go-cmp/cmp"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadKlineCSV(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		input    string
+		expected []gobinance.Kline
+		errCheck errorCheck
+	}{
+		{
+			name: "millisecond timestamps without a header",
+			input: "1609459200000,28923.63,29031.34,28690.17,28995.13,2311.811445,1609462799999,66768830.34,58389,1215.359309,35103542.48,0\n" +
+				"1609462800000,28995.13,29470.00,28960.35,29409.99,3229.487566,1609466399999,94705413.84,103896,1959.157017,57458005.37,0\n",
+			expected: []gobinance.Kline{
+				{
+					OpenTime:            time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+					CloseTime:           time.Date(2021, 1, 1, 0, 59, 59, 999000000, time.UTC),
+					Open:                mustParseBigFloat(t, "28923.63"),
+					High:                mustParseBigFloat(t, "29031.34"),
+					Low:                 mustParseBigFloat(t, "28690.17"),
+					Close:               mustParseBigFloat(t, "28995.13"),
+					Volume:              mustParseBigFloat(t, "2311.811445"),
+					QuoteVolume:         mustParseBigFloat(t, "66768830.34"),
+					NumberOfTrades:      58389,
+					TakerBuyBaseVolume:  mustParseBigFloat(t, "1215.359309"),
+					TakerBuyQuoteVolume: mustParseBigFloat(t, "35103542.48"),
+				},
+				{
+					OpenTime:            time.Date(2021, 1, 1, 1, 0, 0, 0, time.UTC),
+					CloseTime:           time.Date(2021, 1, 1, 1, 59, 59, 999000000, time.UTC),
+					Open:                mustParseBigFloat(t, "28995.13"),
+					High:                mustParseBigFloat(t, "29470"),
+					Low:                 mustParseBigFloat(t, "28960.35"),
+					Close:               mustParseBigFloat(t, "29409.99"),
+					Volume:              mustParseBigFloat(t, "3229.487566"),
+					QuoteVolume:         mustParseBigFloat(t, "94705413.84"),
+					NumberOfTrades:      103896,
+					TakerBuyBaseVolume:  mustParseBigFloat(t, "1959.157017"),
+					TakerBuyQuoteVolume: mustParseBigFloat(t, "57458005.37"),
+				},
+			},
+			errCheck: errNil,
+		},
+		{
+			name: "microsecond timestamps with a header",
+			input: "open_time,open,high,low,close,volume,close_time,quote_volume,count,taker_buy_volume,taker_buy_quote_volume,ignore\n" +
+				"1735689600000000,93576.00,93610.93,93537.50,93610.93,8.21827,1735689659999999,769057.80,1507,3.70839,347063.43,0\n",
+			expected: []gobinance.Kline{
+				{
+					OpenTime:            time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					CloseTime:           time.Date(2025, 1, 1, 0, 0, 59, 999999000, time.UTC),
+					Open:                mustParseBigFloat(t, "93576"),
+					High:                mustParseBigFloat(t, "93610.93"),
+					Low:                 mustParseBigFloat(t, "93537.5"),
+					Close:               mustParseBigFloat(t, "93610.93"),
+					Volume:              mustParseBigFloat(t, "8.21827"),
+					QuoteVolume:         mustParseBigFloat(t, "769057.8"),
+					NumberOfTrades:      1507,
+					TakerBuyBaseVolume:  mustParseBigFloat(t, "3.70839"),
+					TakerBuyQuoteVolume: mustParseBigFloat(t, "347063.43"),
+				},
+			},
+			errCheck: errNil,
+		},
+		{
+			name:     "invalid price",
+			input:    "1609459200000,abc,29031.34,28690.17,28995.13,2311.811445,1609462799999,66768830.34,58389,1215.359309,35103542.48,0\n",
+			errCheck: errNotNil,
+		},
+		{
+			name:     "too few fields",
+			input:    "1609459200000,28923.63,29031.34\n",
+			errCheck: errNotNil,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := gobinance.ReadKlineCSV(strings.NewReader(tc.input))
+			if !tc.errCheck(t, err) {
+				return
+			}
+			if diff := cmp.Diff(tc.expected, got, bigFloatComparer); diff != "" {
+				t.Errorf("unexpected klines.\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadTradeCSV(t *testing.T) {
+	t.Parallel()
+	input := "id,price,qty,quote_qty,time,is_buyer_maker,is_best_match\n" +
+		"259510453,28923.63,0.00214,61.89656820,1609459200008,false,true\n" +
+		"259510454,28923.64,0.01000,289.23640000,1735689600000123,True,True\n"
+	got, err := gobinance.ReadTradeCSV("BTCUSDT", strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := time.Date(2021, 1, 1, 0, 0, 0, 8000000, time.UTC)
+	second := time.Date(2025, 1, 1, 0, 0, 0, 123000, time.UTC)
+	expected := []gobinance.TradeEvent{
+		{Event: "trade", Time: first, Symbol: "BTCUSDT", TradeID: 259510453, Price: mustParseBigFloat(t, "28923.63"), Quantity: mustParseBigFloat(t, "0.00214"), TradeTime: first},
+		{Event: "trade", Time: second, Symbol: "BTCUSDT", TradeID: 259510454, Price: mustParseBigFloat(t, "28923.64"), Quantity: mustParseBigFloat(t, "0.01"), TradeTime: second, IsBuyerMaker: true},
+	}
+	if diff := cmp.Diff(expected, got, bigFloatComparer); diff != "" {
+		t.Errorf("unexpected trades.\n%s", diff)
+	}
+}
+
+func TestReadAggTradeCSV(t *testing.T) {
+	t.Parallel()
+	input := "451273590,28923.63,0.00214,259510453,259510453,1609459200008,false,true\n" +
+		"451273591,28923.64,0.01000,259510454,259510456,1609459200011,true,true\n"
+	got, err := gobinance.ReadAggTradeCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []gobinance.AggTrade{
+		{
+			AggTradeID: 451273590, Price: mustParseBigFloat(t, "28923.63"), Quantity: mustParseBigFloat(t, "0.00214"), FirstTradeID: 259510453, LastTradeID: 259510453,
+			Time: time.Date(2021, 1, 1, 0, 0, 0, 8000000, time.UTC), IsBestMatch: true,
+		},
+		{
+			AggTradeID: 451273591, Price: mustParseBigFloat(t, "28923.64"), Quantity: mustParseBigFloat(t, "0.01"), FirstTradeID: 259510454, LastTradeID: 259510456,
+			Time: time.Date(2021, 1, 1, 0, 0, 0, 11000000, time.UTC), IsBuyerMaker: true, IsBestMatch: true,
+		},
+	}
+	if diff := cmp.Diff(expected, got, bigFloatComparer); diff != "" {
+		t.Errorf("unexpected aggregate trades.\n%s", diff)
+	}
+
+	_, err = gobinance.ReadAggTradeCSV(strings.NewReader("451273590,28923.63,0.00214,259510453,259510453,1609459200008,maybe,true\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 1: field 7") {
+		t.Errorf("expected an error locating the invalid field but got %v", err)
+	}
+}
+
+// writeTestArchive writes a zip archive holding a single csv file, and returns its path and SHA-256 hash
+func writeTestArchive(t *testing.T, dir string, name string, contents string) (string, string) {
+	t.Helper()
+	path := filepath.Join(dir, name+".zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("unable to create archive: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	w, err := zw.Create(name + ".csv")
+	if err == nil {
+		_, err = w.Write([]byte(contents))
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if err == nil {
+		err = f.Close()
+	}
+	if err != nil {
+		t.Fatalf("unable to write archive: %v", err)
+	}
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read archive: %v", err)
+	}
+	return path, fmt.Sprintf("%x", sha256.Sum256(bs))
+}
+
+func TestOpenArchive(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "gobinance")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	contents := "451273590,28923.63,0.00214,259510453,259510453,1609459200008,false,true\n"
+	verified, hash := writeTestArchive(t, dir, "BTCUSDT-aggTrades-2021-01", contents)
+	if err := ioutil.WriteFile(verified+".CHECKSUM", []byte(hash+"  BTCUSDT-aggTrades-2021-01.zip\n"), 0600); err != nil {
+		t.Fatalf("unable to write checksum: %v", err)
+	}
+	unverified, _ := writeTestArchive(t, dir, "BTCUSDT-aggTrades-2021-02", contents)
+	corrupt, _ := writeTestArchive(t, dir, "BTCUSDT-aggTrades-2021-03", contents)
+	if err := ioutil.WriteFile(corrupt+".CHECKSUM", []byte(strings.Repeat("0", 64)+"  BTCUSDT-aggTrades-2021-03.zip\n"), 0600); err != nil {
+		t.Fatalf("unable to write checksum: %v", err)
+	}
+
+	for _, path := range []string{verified, unverified} {
+		rc, err := gobinance.OpenArchive(path)
+		if err != nil {
+			t.Fatalf("unexpected error opening %v: %v", path, err)
+		}
+		trades, err := gobinance.ReadAggTradeCSV(rc)
+		if err != nil || len(trades) != 1 || trades[0].AggTradeID != 451273590 {
+			t.Errorf("unexpected trades %#v, %v", trades, err)
+		}
+		if err := rc.Close(); err != nil {
+			t.Errorf("unexpected error closing %v: %v", path, err)
+		}
+	}
+
+	if _, err := gobinance.OpenArchive(corrupt); !errors.Is(err, gobinance.ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch but got %v", err)
+	}
+	if _, err := gobinance.OpenArchive(filepath.Join(dir, "missing.zip")); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error but got %v", err)
+	}
+}
+
+func TestVerifyArchiveChecksum(t *testing.T) {
+	t.Parallel()
+	archive := "archive contents"
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(archive)))
+	testCases := []struct {
+		name     string
+		checksum string
+		errCheck errorCheck
+	}{
+		{name: "matching", checksum: hash + "  BTCUSDT-klines-1h-2021-01.zip\n", errCheck: errNil},
+		{name: "hash only", checksum: hash, errCheck: errNil},
+		{name: "mismatch", checksum: strings.Repeat("ab", 32) + "  BTCUSDT-klines-1h-2021-01.zip\n", errCheck: func(t *testing.T, err error) bool {
+			if !errors.Is(err, gobinance.ErrChecksumMismatch) {
+				t.Errorf("expected ErrChecksumMismatch but got %v", err)
+			}
+			return false
+		}},
+		{name: "empty", checksum: "", errCheck: errNotNil},
+		{name: "not hex", checksum: "xyz  BTCUSDT-klines-1h-2021-01.zip\n", errCheck: errNotNil},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.errCheck(t, gobinance.VerifyArchiveChecksum(strings.NewReader(archive), strings.NewReader(tc.checksum)))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package gobinance
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// RSASigner provides a method for signing data using an RSA private key, for use with RSA API keys
+type RSASigner struct {
+	PrivateKey *rsa.PrivateKey
+}
+
+// NewRSASigner creates an RSASigner from a PEM encoded RSA private key, in either PKCS #8 or PKCS #1 form.  If the
+// key is encrypted, it is decrypted using passphrase.
+func NewRSASigner(pemKey []byte, passphrase []byte) (*RSASigner, error) {
+	key, err := parsePEMPrivateKey(pemKey, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("expected an RSA private key but got %T", key)
+	}
+	if err := rsaKey.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid RSA private key: %w", err)
+	}
+	return &RSASigner{PrivateKey: rsaKey}, nil
+}
+
+// Sign generates an RSASSA-PKCS1-v1_5 SHA256 signature of the `input` parameter, encoded as base64.  An empty string
+// is returned if the key is too small to produce a signature, which binance will reject.
+func (r *RSASigner) Sign(input string) string {
+	hashed := sha256.Sum256([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, r.PrivateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(signature)
+}
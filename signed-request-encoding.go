@@ -0,0 +1,61 @@
+package gobinance
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// SignedRequestEncoding controls where the parameters of signed POST, PUT and DELETE requests are sent.  The zero
+// value sends all parameters, including the signature, in the query string.  Parameters of GET requests are always
+// sent in the query string.
+//
+// Sending parameters in the body keeps details such as order prices and quantities out of the URLs recorded in proxy
+// and access logs, and avoids limits on the length of URLs.
+type SignedRequestEncoding struct {
+	// Body sends the parameters, and the signature, in an `application/x-www-form-urlencoded` request body
+	Body bool
+	// QueryParameters names parameters which are sent in the query string when Body is true.  As documented by
+	// binance, the signature of such requests is calculated from the query string followed immediately by the body.
+	QueryParameters []string
+}
+
+type signedRequestEncodingKey struct{}
+
+// WithSignedRequestEncoding returns a copy of ctx which causes signed requests made using it to be encoded using
+// encoding, in place of the SignedRequestEncoding set on the Client
+func WithSignedRequestEncoding(ctx context.Context, encoding SignedRequestEncoding) context.Context {
+	return context.WithValue(ctx, signedRequestEncodingKey{}, encoding)
+}
+
+// signedRequestEncoding returns the encoding of a signed request using `method`, from the context if set there,
+// or the client otherwise
+func (c *Client) signedRequestEncoding(ctx context.Context, method string) SignedRequestEncoding {
+	if method == http.MethodGet || method == http.MethodHead {
+		return SignedRequestEncoding{}
+	}
+	if ctx != nil {
+		if encoding, ok := ctx.Value(signedRequestEncodingKey{}).(SignedRequestEncoding); ok {
+			return encoding
+		}
+	}
+	return c.SignedRequestEncoding
+}
+
+// split divides parameters between the query string and the body
+func (e SignedRequestEncoding) split(parameters url.Values) (query url.Values, body url.Values) {
+	if !e.Body {
+		return parameters, nil
+	}
+	query, body = make(url.Values), make(url.Values)
+	for k, v := range parameters {
+		body[k] = v
+	}
+	for _, k := range e.QueryParameters {
+		if v, ok := body[k]; ok {
+			query[k] = v
+			delete(body, k)
+		}
+	}
+	return query, body
+}
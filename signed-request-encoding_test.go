@@ -0,0 +1,129 @@
+package gobinance_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestClient_SignedRequestEncoding(t *testing.T) {
+	t.Parallel()
+	placeOrder := func(ctx context.Context, c *gobinance.Client) {
+		_, _ = c.PlaceLimitOrder(ctx, "BTCUSDT", gobinance.OrderSideBuy, big.NewFloat(1), big.NewFloat(2), gobinance.TimeInForceGoodTilCanceled, gobinance.SpotClientOrderID("abc"))
+	}
+	const orderParams = "newClientOrderId=abc&newOrderRespType=FULL&price=2&quantity=1&side=BUY&symbol=BTCUSDT&timeInForce=GTC&timestamp=1234567890123&type=LIMIT"
+	testCases := []struct {
+		name           string
+		clientEncoding gobinance.SignedRequestEncoding
+		request        func(ctx context.Context, c *gobinance.Client)
+		ctx            context.Context
+		signature      string
+		expectedSign   string
+		expectedQuery  string
+		expectedBody   string
+	}{
+		{
+			name:          "query by default",
+			request:       placeOrder,
+			signature:     "sig",
+			expectedSign:  orderParams,
+			expectedQuery: "newClientOrderId=abc&newOrderRespType=FULL&price=2&quantity=1&side=BUY&signature=sig&symbol=BTCUSDT&timeInForce=GTC&timestamp=1234567890123&type=LIMIT",
+		},
+		{
+			name:           "body",
+			clientEncoding: gobinance.SignedRequestEncoding{Body: true},
+			request:        placeOrder,
+			signature:      "a+b/c=",
+			expectedSign:   orderParams,
+			expectedBody:   orderParams + "&signature=a%2Bb%2Fc%3D",
+		},
+		{
+			name:           "mixed",
+			clientEncoding: gobinance.SignedRequestEncoding{Body: true, QueryParameters: []string{"symbol", "side", "notPresent"}},
+			request:        placeOrder,
+			signature:      "sig",
+			expectedSign:   "side=BUY&symbol=BTCUSDT" + "newClientOrderId=abc&newOrderRespType=FULL&price=2&quantity=1&timeInForce=GTC&timestamp=1234567890123&type=LIMIT",
+			expectedQuery:  "side=BUY&symbol=BTCUSDT",
+			expectedBody:   "newClientOrderId=abc&newOrderRespType=FULL&price=2&quantity=1&timeInForce=GTC&timestamp=1234567890123&type=LIMIT&signature=sig",
+		},
+		{
+			name:           "context overrides client",
+			clientEncoding: gobinance.SignedRequestEncoding{Body: true},
+			ctx:            gobinance.WithSignedRequestEncoding(context.Background(), gobinance.SignedRequestEncoding{}),
+			request:        placeOrder,
+			signature:      "sig",
+			expectedSign:   orderParams,
+			expectedQuery:  "newClientOrderId=abc&newOrderRespType=FULL&price=2&quantity=1&side=BUY&signature=sig&symbol=BTCUSDT&timeInForce=GTC&timestamp=1234567890123&type=LIMIT",
+		},
+		{
+			name:         "context selects body",
+			ctx:          gobinance.WithSignedRequestEncoding(context.Background(), gobinance.SignedRequestEncoding{Body: true}),
+			request:      placeOrder,
+			signature:    "sig",
+			expectedSign: orderParams,
+			expectedBody: orderParams + "&signature=sig",
+		},
+		{
+			name:           "get requests use the query",
+			clientEncoding: gobinance.SignedRequestEncoding{Body: true},
+			request: func(ctx context.Context, c *gobinance.Client) {
+				_, _ = c.AccountInformation(ctx)
+			},
+			signature:     "sig",
+			expectedSign:  "timestamp=1234567890123",
+			expectedQuery: "signature=sig&timestamp=1234567890123",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSigner := mock_gobinance.NewMockSigner(ctrl)
+			mockSigner.EXPECT().Sign(tc.expectedSign).Return(tc.signature)
+			mockDoer := mock_gobinance.NewMockDoer(ctrl)
+			mockDoer.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+				if req.URL.RawQuery != tc.expectedQuery {
+					t.Errorf("unexpected query.  expected\n\t%v\ngot\n\t%v", tc.expectedQuery, req.URL.RawQuery)
+				}
+				var body []byte
+				if req.Body != nil {
+					body, _ = ioutil.ReadAll(req.Body)
+				}
+				if string(body) != tc.expectedBody {
+					t.Errorf("unexpected body.  expected\n\t%v\ngot\n\t%v", tc.expectedBody, string(body))
+				}
+				expectedContentType := ""
+				if tc.expectedBody != "" {
+					expectedContentType = "application/x-www-form-urlencoded"
+				}
+				if got := req.Header.Get("Content-Type"); got != expectedContentType {
+					t.Errorf("unexpected content type %q", got)
+				}
+			}).Return(nil, fmt.Errorf("stop early"))
+
+			u, _ := url.Parse(testBaseURL)
+			uut := &gobinance.Client{
+				HTTPApiURL:            u,
+				Signer:                mockSigner,
+				Doer:                  mockDoer,
+				Now:                   mockNow,
+				SignedRequestEncoding: tc.clientEncoding,
+			}
+			ctx := tc.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			tc.request(ctx, uut)
+		})
+	}
+}
@@ -0,0 +1,866 @@
+package gobinance
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// simulatedQuantityDecimals is the number of decimal places quantities calculated from a quote order quantity
+	// are truncated to
+	simulatedQuantityDecimals = 8
+	// error codes returned by binance, and so by the simulated exchange
+	errorCodeInvalidQuantity  = -1013
+	errorCodeInvalidSymbol    = -1121
+	errorCodeNewOrderRejected = -2010
+	errorCodeCancelRejected   = -2011
+)
+
+var simulatedQuantityScale = new(big.Float).SetFloat64(math.Pow10(simulatedQuantityDecimals))
+
+// simulatedSymbol holds the assets of a symbol traded on a SimulatedExchange
+type simulatedSymbol struct {
+	base  string
+	quote string
+}
+
+// simulatedOrder is an order placed on a SimulatedExchange.  The big.Floats of an order are never modified once
+// set, so copies of the SpotOrder may be returned to callers.
+type simulatedOrder struct {
+	SpotOrder
+}
+
+func (o *simulatedOrder) remaining() *big.Float {
+	return new(big.Float).Sub(o.OriginalQty, o.ExecutedQty)
+}
+
+// simulatedBook holds the market data of a symbol traded on a SimulatedExchange
+type simulatedBook struct {
+	// bids are sorted by descending price and asks by ascending price
+	bids      []PriceLevel
+	asks      []PriceLevel
+	lastPrice *big.Float
+}
+
+// simulatedFill is a fill which has been matched but not yet applied
+type simulatedFill struct {
+	price *big.Float
+	qty   *big.Float
+}
+
+// SimulatedExchange matches orders against market data fed to it, maintaining a virtual balance sheet.  It is the
+// matching engine of PaperTrader and Backtest, and may be used to build other fakes of binance.
+//
+// Orders taking liquidity fill against the order book, or the last traded price when no book is known, with
+// slippage applied against the taker.  Market orders which exhaust the known levels fill the remainder at the
+// price of the last level.  Orders resting on the book fill at their own price as the maker when a trade is
+// made at or through that price, up to the traded quantity.  Queue position is not modelled.  Stop loss limit
+// and take profit limit orders hold their funds without working until a trade reaches their stop price, when
+// they take liquidity as limit orders would.
+//
+// Errors which binance would return are returned as *HttpError with the codes binance would use.  All methods are
+// safe to call concurrently.
+type SimulatedExchange struct {
+	makerFee *big.Float
+	takerFee *big.Float
+	slippage *big.Float
+	// onExecution, if set, is called with e.mu held for each execution of an order
+	onExecution func(x SimulatedExecution)
+
+	mu             sync.Mutex
+	symbols        map[string]simulatedSymbol
+	balances       map[string]Balance
+	orders         map[int64]*simulatedOrder
+	clientOrderIDs map[string]int64
+	books          map[string]*simulatedBook
+	nextOrderID    int64
+	nextTradeID    int64
+	updateTime     time.Time
+}
+
+// SimulatedExecution describes a change to an order placed on a SimulatedExchange, as reported by the execution
+// reports of binance's user data stream
+type SimulatedExecution struct {
+	// Order is the state of the order following the execution
+	Order         SpotOrder
+	ExecutionType ExecutionType
+	// Fill is the fill made by an execution of type ExecutionTypeTrade, and nil otherwise
+	Fill    *Fill
+	IsMaker bool
+	Time    time.Time
+	// Balances are the balances of the base and quote assets of the order's symbol following the execution
+	Balances []Balance
+}
+
+// SimulatedOrderRequest is an order to be placed on a SimulatedExchange.  The fields are those of the request to
+// place an order on binance.  Limit, limit maker, market, stop loss limit and take profit limit orders are
+// supported.
+type SimulatedOrderRequest struct {
+	Symbol      string
+	Side        OrderSide
+	Type        OrderType
+	TimeInForce TimeInForce
+	Quantity    *big.Float
+	// QuoteOrderQty is the quantity of the quote asset spent or received by a market order in place of Quantity
+	QuoteOrderQty *big.Float
+	Price         *big.Float
+	StopPrice     *big.Float
+	// ClientOrderID identifies the order.  When empty, one is generated.
+	ClientOrderID string
+}
+
+// SimulatedCancelRequest is a request to cancel an order placed on a SimulatedExchange, identified by OrderID or,
+// if it is zero, by OrigClientOrderID
+type SimulatedCancelRequest struct {
+	Symbol            string
+	OrderID           int64
+	OrigClientOrderID string
+	// NewClientOrderID identifies the cancellation.  When empty, one is generated.
+	NewClientOrderID string
+}
+
+// NewSimulatedExchange creates a SimulatedExchange without any symbols or balances, which charges fees of 0.1% on
+// maker and taker fills without slippage
+func NewSimulatedExchange() *SimulatedExchange {
+	return &SimulatedExchange{
+		symbols:        make(map[string]simulatedSymbol),
+		makerFee:       big.NewFloat(0.001),
+		takerFee:       big.NewFloat(0.001),
+		slippage:       new(big.Float),
+		balances:       make(map[string]Balance),
+		orders:         make(map[int64]*simulatedOrder),
+		clientOrderIDs: make(map[string]int64),
+		books:          make(map[string]*simulatedBook),
+	}
+}
+
+func newSimulatedError(code int, msg string) *HttpError {
+	return &HttpError{HttpStatus: http.StatusBadRequest, errorDTO: errorDTO{Code: code, Msg: msg}}
+}
+
+// AddSymbol adds a symbol which may be traded, along with its base and quote assets
+func (e *SimulatedExchange) AddSymbol(symbol string, baseAsset string, quoteAsset string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.symbols[symbol] = simulatedSymbol{base: baseAsset, quote: quoteAsset}
+}
+
+// SetFees sets the fee rates charged on maker and taker fills, e.g. 0.001 for 0.1%.  Fees are charged in the asset
+// received.
+func (e *SimulatedExchange) SetFees(maker *big.Float, taker *big.Float) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.makerFee = maker
+	e.takerFee = taker
+}
+
+// SetExecutionHandler sets a function to be called with each execution of an order, such as its placement, each
+// of its fills, and its cancellation or expiry.  It is called while the exchange is locked, so must not call the
+// exchange's methods.
+func (e *SimulatedExchange) SetExecutionHandler(handle func(x SimulatedExecution)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onExecution = handle
+}
+
+// SetBalance sets the free balance of an asset, leaving any locked balance unchanged
+func (e *SimulatedExchange) SetBalance(asset string, free *big.Float) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b := e.balance(asset)
+	b.Free = new(big.Float).Copy(free)
+	e.balances[asset] = b
+}
+
+// Balance returns the balance of an asset
+func (e *SimulatedExchange) Balance(asset string) Balance {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.balance(asset)
+}
+
+// NewTradeID returns an ID for a trade made on the market by other participants, which is not used by the
+// exchange's fills
+func (e *SimulatedExchange) NewTradeID() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.nextTradeID++
+	return e.nextTradeID
+}
+
+// balance returns the balance of asset.  It must be called with e.mu held.
+func (e *SimulatedExchange) balance(asset string) Balance {
+	b, ok := e.balances[asset]
+	if !ok {
+		b = Balance{Asset: asset, Free: new(big.Float), Locked: new(big.Float)}
+	}
+	return b
+}
+
+// adjustBalance adds the deltas to the balance of asset.  It must be called with e.mu held.
+func (e *SimulatedExchange) adjustBalance(asset string, free *big.Float, locked *big.Float) {
+	b := e.balance(asset)
+	if free != nil {
+		b.Free = new(big.Float).Add(b.Free, free)
+	}
+	if locked != nil {
+		b.Locked = new(big.Float).Add(b.Locked, locked)
+	}
+	e.balances[asset] = b
+}
+
+func (e *SimulatedExchange) book(symbol string) *simulatedBook {
+	book, ok := e.books[symbol]
+	if !ok {
+		book = &simulatedBook{}
+		e.books[symbol] = book
+	}
+	return book
+}
+
+// Trade records a trade made on the market, filling the resting orders it crosses up to its quantity.  A nil
+// quantity fills every order crossed.
+func (e *SimulatedExchange) Trade(symbol string, price *big.Float, qty *big.Float, at time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	book := e.book(symbol)
+	book.lastPrice = new(big.Float).Copy(price)
+	var available *big.Float
+	if qty != nil {
+		available = new(big.Float).Copy(qty)
+	}
+	e.triggerStops(book, symbol, price, available, at)
+
+	var crossed []*simulatedOrder
+	for _, o := range e.orders {
+		if o.Symbol != symbol || o.Status.IsTerminal() || !o.IsWorking {
+			continue
+		}
+		if (o.Side == OrderSideBuy && price.Cmp(o.Price) <= 0) || (o.Side == OrderSideSell && price.Cmp(o.Price) >= 0) {
+			crossed = append(crossed, o)
+		}
+	}
+	// fill the best priced orders first, then the oldest
+	sort.Slice(crossed, func(i, j int) bool {
+		if c := crossed[i].Price.Cmp(crossed[j].Price); c != 0 {
+			return (c > 0) == (crossed[i].Side == OrderSideBuy)
+		}
+		return crossed[i].OrderID < crossed[j].OrderID
+	})
+
+	for _, o := range crossed {
+		fillQty := o.remaining()
+		if available != nil {
+			if available.Sign() <= 0 {
+				break
+			}
+			fillQty = minFloat(fillQty, available)
+			available.Sub(available, fillQty)
+		}
+		e.fill(o, simulatedFill{price: o.Price, qty: fillQty}, true, true, at)
+	}
+}
+
+// triggerStops starts working the stop orders on the symbol whose stop price has been reached by a trade,
+// oldest first, taking liquidity with each.  The quantity taken is deducted from available, the quantity of the
+// trade remaining to fill resting orders, unless it is nil.  It must be called with e.mu held.
+func (e *SimulatedExchange) triggerStops(book *simulatedBook, symbol string, price *big.Float, available *big.Float, at time.Time) {
+	var triggered []*simulatedOrder
+	for _, o := range e.orders {
+		if o.Symbol == symbol && !o.Status.IsTerminal() && !o.IsWorking && stopTriggered(o.Type, o.Side, o.StopPrice, price) {
+			triggered = append(triggered, o)
+		}
+	}
+	sort.Slice(triggered, func(i, j int) bool {
+		return triggered[i].OrderID < triggered[j].OrderID
+	})
+	for _, o := range triggered {
+		o.IsWorking = true
+		o.UpdateTime = at
+		e.take(book, o, available, at)
+	}
+}
+
+// take matches a triggered stop order against the book, as a limit order with funds already locked, deducting the
+// quantity taken from available unless it is nil.  It must be called with e.mu held.
+func (e *SimulatedExchange) take(book *simulatedBook, o *simulatedOrder, available *big.Float, at time.Time) {
+	levels := book.levels(o.Side)
+	if available != nil && len(levels) > 0 && levels[0].Quantity == nil {
+		// without a book, the liquidity known to be available is that of the trade
+		levels = []PriceLevel{{Price: levels[0].Price, Quantity: new(big.Float).Copy(available)}}
+	}
+	remaining := o.remaining()
+	fills, executedQty, _ := e.match(levels, spotOrderInput{Side: o.Side, Price: o.Price, Quantity: remaining})
+	if o.TimeInForce == TimeInForceFillOrKill && executedQty.Cmp(remaining) < 0 {
+		fills = nil
+	}
+	for _, f := range fills {
+		e.consumeLiquidity(book, o.Side, f)
+		e.fill(o, f, false, true, at)
+		if available != nil {
+			available.Sub(available, f.qty)
+		}
+	}
+	if remaining = o.remaining(); remaining.Sign() > 0 && (o.TimeInForce == TimeInForceImmediateOrCancel || o.TimeInForce == TimeInForceFillOrKill) {
+		e.lock(o, remaining.Neg(remaining))
+		o.Status = OrderStatusExpired
+		e.execute(o, ExecutionTypeExpired, nil, false, at)
+	}
+}
+
+// levels returns the levels of the book that an order on the given side takes liquidity from, using the last
+// traded price with unlimited liquidity if the side is empty
+func (b *simulatedBook) levels(side OrderSide) []PriceLevel {
+	levels := b.asks
+	if side == OrderSideSell {
+		levels = b.bids
+	}
+	if len(levels) == 0 && b.lastPrice != nil {
+		levels = []PriceLevel{{Price: b.lastPrice}}
+	}
+	return levels
+}
+
+// isStopLimit returns true for the order types which do not work until their stop price is reached
+func isStopLimit(t OrderType) bool {
+	return t == OrderTypeStopLossLimit || t == OrderTypeTakeProfitLimit
+}
+
+// stopTriggered returns true if a trade at price reaches the stop price of an order.  Stop losses trigger when
+// the price moves against the position being closed and take profits when it moves in its favour.
+func stopTriggered(t OrderType, side OrderSide, stopPrice *big.Float, price *big.Float) bool {
+	c := price.Cmp(stopPrice)
+	if (t == OrderTypeStopLossLimit) == (side == OrderSideBuy) {
+		return c >= 0
+	}
+	return c <= 0
+}
+
+// Depth replaces the order book of the symbol
+func (e *SimulatedExchange) Depth(symbol string, bids []PriceLevel, asks []PriceLevel) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	book := e.book(symbol)
+	book.bids = copyPriceLevels(bids, len(bids))
+	book.asks = copyPriceLevels(asks, len(asks))
+}
+
+// Place places an order, matching it against the book immediately
+func (e *SimulatedExchange) Place(req SimulatedOrderRequest, now time.Time) (SpotOrderResult, error) {
+	return e.place(spotOrderInput{
+		Symbol:           req.Symbol,
+		Side:             req.Side,
+		Type:             req.Type,
+		TimeInForce:      req.TimeInForce,
+		Quantity:         req.Quantity,
+		QuoteOrderQty:    req.QuoteOrderQty,
+		Price:            req.Price,
+		NewClientOrderID: req.ClientOrderID,
+		StopPrice:        req.StopPrice,
+	}, now)
+}
+
+// place places an order, matching it against the book immediately
+func (e *SimulatedExchange) place(input spotOrderInput, now time.Time) (SpotOrderResult, error) {
+	switch input.Type {
+	case OrderTypeLimit, OrderTypeLimitMaker:
+		if input.Quantity == nil || input.Quantity.Sign() <= 0 {
+			return SpotOrderResult{}, newSimulatedError(errorCodeInvalidQuantity, "Invalid quantity.")
+		}
+		if input.Price == nil || input.Price.Sign() <= 0 {
+			return SpotOrderResult{}, newSimulatedError(errorCodeInvalidQuantity, "Invalid price.")
+		}
+	case OrderTypeStopLossLimit, OrderTypeTakeProfitLimit:
+		if input.Quantity == nil || input.Quantity.Sign() <= 0 {
+			return SpotOrderResult{}, newSimulatedError(errorCodeInvalidQuantity, "Invalid quantity.")
+		}
+		if input.Price == nil || input.Price.Sign() <= 0 {
+			return SpotOrderResult{}, newSimulatedError(errorCodeInvalidQuantity, "Invalid price.")
+		}
+		if input.StopPrice == nil || input.StopPrice.Sign() <= 0 {
+			return SpotOrderResult{}, newSimulatedError(errorCodeInvalidQuantity, "Invalid stop price.")
+		}
+	case OrderTypeMarket:
+		if (input.Quantity == nil || input.Quantity.Sign() <= 0) && (input.QuoteOrderQty == nil || input.QuoteOrderQty.Sign() <= 0) {
+			return SpotOrderResult{}, newSimulatedError(errorCodeInvalidQuantity, "Invalid quantity.")
+		}
+	default:
+		return SpotOrderResult{}, fmt.Errorf("%v orders are not supported by the simulated exchange", input.Type)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	sym, ok := e.symbols[input.Symbol]
+	if !ok {
+		return SpotOrderResult{}, newSimulatedError(errorCodeInvalidSymbol, "Invalid symbol.")
+	}
+	if id, ok := e.clientOrderIDs[clientOrderIDKey(input.Symbol, input.NewClientOrderID)]; ok && input.NewClientOrderID != "" && !e.orders[id].Status.IsTerminal() {
+		return SpotOrderResult{}, newSimulatedError(errorCodeNewOrderRejected, "Duplicate order sent.")
+	}
+
+	book := e.book(input.Symbol)
+	var fills []simulatedFill
+	executedQty, quoteQty := new(big.Float), new(big.Float)
+	if isStopLimit(input.Type) {
+		if book.lastPrice != nil && stopTriggered(input.Type, input.Side, input.StopPrice, book.lastPrice) {
+			return SpotOrderResult{}, newSimulatedError(errorCodeNewOrderRejected, "Stop price would trigger immediately.")
+		}
+	} else {
+		levels := book.levels(input.Side)
+		if input.Type == OrderTypeMarket {
+			if len(levels) == 0 {
+				return SpotOrderResult{}, newSimulatedError(errorCodeNewOrderRejected, "Market is closed.")
+			}
+			// assume liquidity beyond the known levels is available at the price of the last level
+			levels = append(levels[:len(levels):len(levels)], PriceLevel{Price: levels[len(levels)-1].Price})
+		}
+		fills, executedQty, quoteQty = e.match(levels, input)
+	}
+
+	origQty := input.Quantity
+	if origQty == nil {
+		origQty = executedQty
+	}
+	remaining := new(big.Float).Sub(origQty, executedQty)
+	status := OrderStatusFilled
+	switch {
+	case input.Type == OrderTypeLimitMaker && len(fills) > 0:
+		return SpotOrderResult{}, newSimulatedError(errorCodeNewOrderRejected, "Order would immediately match and take.")
+	case remaining.Sign() == 0:
+	case isStopLimit(input.Type):
+		status = OrderStatusNew
+	case input.Type == OrderTypeMarket || input.TimeInForce == TimeInForceImmediateOrCancel:
+		status = OrderStatusExpired
+	case input.TimeInForce == TimeInForceFillOrKill:
+		status = OrderStatusExpired
+		fills, executedQty, quoteQty = nil, new(big.Float), new(big.Float)
+	case executedQty.Sign() > 0:
+		status = OrderStatusPartiallyFilled
+	default:
+		status = OrderStatusNew
+	}
+	resting := new(big.Float)
+	if !status.IsTerminal() {
+		resting = remaining
+	}
+
+	// the order is rejected unless the account holds the funds for the fills and the resting quantity
+	var required *big.Float
+	var asset string
+	if input.Side == OrderSideBuy {
+		asset = sym.quote
+		required = new(big.Float).Copy(quoteQty)
+		if resting.Sign() > 0 {
+			required.Add(required, new(big.Float).Mul(resting, input.Price))
+		}
+	} else {
+		asset = sym.base
+		required = new(big.Float).Add(executedQty, resting)
+	}
+	if e.balance(asset).Free.Cmp(required) < 0 {
+		return SpotOrderResult{}, newSimulatedError(errorCodeNewOrderRejected, "Account has insufficient balance for requested action.")
+	}
+
+	e.nextOrderID++
+	clientOrderID := input.NewClientOrderID
+	if clientOrderID == "" {
+		clientOrderID = fmt.Sprintf("simulated-%v", e.nextOrderID)
+	}
+	price := input.Price
+	if price == nil {
+		price = new(big.Float)
+	}
+	stopPrice := input.StopPrice
+	if stopPrice == nil {
+		stopPrice = new(big.Float)
+	}
+	o := &simulatedOrder{SpotOrder: SpotOrder{
+		Symbol:                input.Symbol,
+		OrderID:               e.nextOrderID,
+		OrderListID:           -1,
+		ClientOrderID:         clientOrderID,
+		Price:                 price,
+		OriginalQty:           origQty,
+		ExecutedQty:           new(big.Float),
+		CumulativeQuoteQty:    new(big.Float),
+		Status:                OrderStatusNew,
+		TimeInForce:           input.TimeInForce,
+		Type:                  input.Type,
+		Side:                  input.Side,
+		StopPrice:             stopPrice,
+		IcebergQty:            new(big.Float),
+		Time:                  now,
+		UpdateTime:            now,
+		IsWorking:             !isStopLimit(input.Type),
+		OriginalQuoteOrderQty: new(big.Float),
+	}}
+	if input.QuoteOrderQty != nil {
+		o.OriginalQuoteOrderQty = input.QuoteOrderQty
+	}
+	e.orders[o.OrderID] = o
+	e.clientOrderIDs[clientOrderIDKey(o.Symbol, o.ClientOrderID)] = o.OrderID
+
+	result := SpotOrderResult{
+		Symbol:        o.Symbol,
+		OrderID:       int(o.OrderID),
+		OrderListID:   -1,
+		ClientOrderID: o.ClientOrderID,
+		TransactTime:  now,
+		Price:         o.Price,
+		OrigQty:       o.OriginalQty,
+		TimeInForce:   o.TimeInForce,
+		Type:          o.Type,
+		Side:          o.Side,
+		Fills:         []Fill{},
+	}
+	if resting.Sign() > 0 {
+		e.lock(o, resting)
+	}
+	e.execute(o, ExecutionTypeNew, nil, false, now)
+	for _, f := range fills {
+		e.consumeLiquidity(book, o.Side, f)
+		result.Fills = append(result.Fills, e.fill(o, f, false, false, now))
+	}
+	o.Status = status
+	if status == OrderStatusExpired {
+		e.execute(o, ExecutionTypeExpired, nil, false, now)
+	}
+	result.ExecutedQty = o.ExecutedQty
+	result.CumulativeQuoteQty = o.CumulativeQuoteQty
+	result.Status = o.Status
+	e.updateTime = now
+	return result, nil
+}
+
+// match returns the fills of an order taking liquidity from levels, along with the total base and quote
+// quantities of the fills.  Levels without a quantity have unlimited liquidity.
+func (e *SimulatedExchange) match(levels []PriceLevel, input spotOrderInput) ([]simulatedFill, *big.Float, *big.Float) {
+	var fills []simulatedFill
+	executedQty, quoteQty := new(big.Float), new(big.Float)
+	for _, level := range levels {
+		if input.Price != nil && ((input.Side == OrderSideBuy && level.Price.Cmp(input.Price) > 0) || (input.Side == OrderSideSell && level.Price.Cmp(input.Price) < 0)) {
+			break
+		}
+		price := e.slip(level.Price, input.Side, input.Price)
+		var qty *big.Float
+		if input.Quantity != nil {
+			qty = new(big.Float).Sub(input.Quantity, executedQty)
+		} else {
+			qty = truncateQuantity(new(big.Float).Quo(new(big.Float).Sub(input.QuoteOrderQty, quoteQty), price))
+		}
+		if level.Quantity != nil {
+			qty = minFloat(qty, level.Quantity)
+		}
+		if qty.Sign() <= 0 {
+			break
+		}
+		fills = append(fills, simulatedFill{price: price, qty: qty})
+		executedQty.Add(executedQty, qty)
+		quoteQty.Add(quoteQty, new(big.Float).Mul(qty, price))
+	}
+	return fills, executedQty, quoteQty
+}
+
+// slip applies slippage against a taker to price, without exceeding the order's limit
+func (e *SimulatedExchange) slip(price *big.Float, side OrderSide, limit *big.Float) *big.Float {
+	adjustment := new(big.Float).Mul(price, e.slippage)
+	if side == OrderSideBuy {
+		price = new(big.Float).Add(price, adjustment)
+		if limit != nil && price.Cmp(limit) > 0 {
+			return limit
+		}
+		return price
+	}
+	price = new(big.Float).Sub(price, adjustment)
+	if limit != nil && price.Cmp(limit) < 0 {
+		return limit
+	}
+	return price
+}
+
+// consumeLiquidity removes the quantity of a fill from the book, so that it isn't taken again before the next
+// depth update.  It must be called with e.mu held.
+func (e *SimulatedExchange) consumeLiquidity(book *simulatedBook, side OrderSide, f simulatedFill) {
+	levels := &book.asks
+	if side == OrderSideSell {
+		levels = &book.bids
+	}
+	qty := new(big.Float).Copy(f.qty)
+	for len(*levels) > 0 && qty.Sign() > 0 {
+		level := (*levels)[0]
+		if level.Quantity.Cmp(qty) > 0 {
+			(*levels)[0] = PriceLevel{Price: level.Price, Quantity: new(big.Float).Sub(level.Quantity, qty)}
+			return
+		}
+		qty.Sub(qty, level.Quantity)
+		*levels = (*levels)[1:]
+	}
+}
+
+// applyFill updates the balances for a fill of o, spending locked funds if the fill is of a resting or triggered
+// order.  Commission is charged in the asset received.  It must be called with e.mu held.
+func (e *SimulatedExchange) applyFill(o *simulatedOrder, f simulatedFill, isMaker bool, fromLocked bool) Fill {
+	fee := e.takerFee
+	if isMaker {
+		fee = e.makerFee
+	}
+	sym := e.symbols[o.Symbol]
+	quoteQty := new(big.Float).Mul(f.qty, f.price)
+	e.nextTradeID++
+	fill := Fill{
+		Price:   f.price,
+		Qty:     f.qty,
+		TradeID: e.nextTradeID,
+	}
+	if o.Side == OrderSideBuy {
+		fill.CommissionAsset = sym.base
+		fill.Commission = new(big.Float).Mul(f.qty, fee)
+		if fromLocked {
+			// funds were locked at the limit price, so any price improvement is returned
+			locked := new(big.Float).Mul(f.qty, o.Price)
+			e.adjustBalance(sym.quote, new(big.Float).Sub(locked, quoteQty), new(big.Float).Neg(locked))
+		} else {
+			e.adjustBalance(sym.quote, new(big.Float).Neg(quoteQty), nil)
+		}
+		e.adjustBalance(sym.base, new(big.Float).Sub(f.qty, fill.Commission), nil)
+	} else {
+		fill.CommissionAsset = sym.quote
+		fill.Commission = new(big.Float).Mul(quoteQty, fee)
+		if fromLocked {
+			e.adjustBalance(sym.base, nil, new(big.Float).Neg(f.qty))
+		} else {
+			e.adjustBalance(sym.base, new(big.Float).Neg(f.qty), nil)
+		}
+		e.adjustBalance(sym.quote, new(big.Float).Sub(quoteQty, fill.Commission), nil)
+	}
+	return fill
+}
+
+// fill applies a fill of o, updating its executed quantities and reporting the execution.  It must be called with
+// e.mu held.
+func (e *SimulatedExchange) fill(o *simulatedOrder, f simulatedFill, isMaker bool, fromLocked bool, at time.Time) Fill {
+	fill := e.applyFill(o, f, isMaker, fromLocked)
+	e.setExecuted(o, f, at)
+	e.execute(o, ExecutionTypeTrade, &fill, isMaker, at)
+	return fill
+}
+
+// execute reports an execution of o to the execution handler, if one is set.  It must be called with e.mu held.
+func (e *SimulatedExchange) execute(o *simulatedOrder, executionType ExecutionType, fill *Fill, isMaker bool, at time.Time) {
+	if e.onExecution == nil {
+		return
+	}
+	sym := e.symbols[o.Symbol]
+	e.onExecution(SimulatedExecution{
+		Order:         o.SpotOrder,
+		ExecutionType: executionType,
+		Fill:          fill,
+		IsMaker:       isMaker,
+		Time:          at,
+		Balances:      []Balance{e.balance(sym.base), e.balance(sym.quote)},
+	})
+}
+
+// setExecuted adds a fill to the executed quantities of o.  It must be called with e.mu held.
+func (e *SimulatedExchange) setExecuted(o *simulatedOrder, f simulatedFill, at time.Time) {
+	o.ExecutedQty = new(big.Float).Add(o.ExecutedQty, f.qty)
+	o.CumulativeQuoteQty = new(big.Float).Add(o.CumulativeQuoteQty, new(big.Float).Mul(f.qty, f.price))
+	o.UpdateTime = at
+	if o.Status == OrderStatusNew || o.Status == OrderStatusPartiallyFilled {
+		o.Status = OrderStatusPartiallyFilled
+		if o.remaining().Sign() == 0 {
+			o.Status = OrderStatusFilled
+		}
+	}
+	e.updateTime = at
+}
+
+// lock moves the funds for qty of o from free to locked.  It must be called with e.mu held.
+func (e *SimulatedExchange) lock(o *simulatedOrder, qty *big.Float) {
+	sym := e.symbols[o.Symbol]
+	if o.Side == OrderSideBuy {
+		amount := new(big.Float).Mul(qty, o.Price)
+		e.adjustBalance(sym.quote, new(big.Float).Neg(amount), amount)
+	} else {
+		e.adjustBalance(sym.base, new(big.Float).Neg(qty), qty)
+	}
+}
+
+// find returns the order with the given order ID, or the most recent with the given client order ID.  It must be
+// called with e.mu held.
+func (e *SimulatedExchange) find(symbol string, orderID int64, clientOrderID string) (*simulatedOrder, bool) {
+	if orderID == 0 {
+		orderID = e.clientOrderIDs[clientOrderIDKey(symbol, clientOrderID)]
+	}
+	o, ok := e.orders[orderID]
+	if !ok || o.Symbol != symbol {
+		return nil, false
+	}
+	return o, true
+}
+
+// Cancel cancels an open order, releasing its locked funds
+func (e *SimulatedExchange) Cancel(req SimulatedCancelRequest, now time.Time) (CancelSpotOrderResult, error) {
+	return e.cancel(cancelSpotOrderInput{
+		Symbol:            req.Symbol,
+		OrderID:           req.OrderID,
+		OrigClientOrderID: req.OrigClientOrderID,
+		NewClientOrderID:  req.NewClientOrderID,
+	}, now)
+}
+
+// cancel cancels an open order, releasing its locked funds
+func (e *SimulatedExchange) cancel(input cancelSpotOrderInput, now time.Time) (CancelSpotOrderResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	o, ok := e.find(input.Symbol, input.OrderID, input.OrigClientOrderID)
+	if !ok || o.Status.IsTerminal() {
+		return CancelSpotOrderResult{}, newSimulatedError(errorCodeCancelRejected, "Unknown order sent.")
+	}
+	remaining := o.remaining()
+	e.lock(o, remaining.Neg(remaining))
+	o.Status = OrderStatusCanceled
+	o.UpdateTime = now
+	e.updateTime = now
+	e.execute(o, ExecutionTypeCanceled, nil, false, now)
+
+	clientOrderID := input.NewClientOrderID
+	if clientOrderID == "" {
+		clientOrderID = fmt.Sprintf("simulated-cancel-%v", o.OrderID)
+	}
+	return CancelSpotOrderResult{
+		Symbol:                o.Symbol,
+		OriginalClientOrderID: o.ClientOrderID,
+		OrderID:               o.OrderID,
+		OrderListID:           o.OrderListID,
+		ClientOrderID:         clientOrderID,
+		Price:                 o.Price,
+		OriginalQty:           o.OriginalQty,
+		ExecutedQty:           o.ExecutedQty,
+		CumulativeQuoteQty:    o.CumulativeQuoteQty,
+		Status:                o.Status,
+		TimeInForce:           o.TimeInForce,
+		Type:                  o.Type,
+		Side:                  o.Side,
+	}, nil
+}
+
+// Query returns an order placed on the exchange, identified by orderID or, if it is zero, by the most recent
+// order with origClientOrderID
+func (e *SimulatedExchange) Query(symbol string, orderID int64, origClientOrderID string) (SpotOrder, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	o, ok := e.find(symbol, orderID, origClientOrderID)
+	if !ok {
+		return SpotOrder{}, newSimulatedError(errorCodeNoSuchOrder, "Order does not exist.")
+	}
+	return o.SpotOrder, nil
+}
+
+func (e *SimulatedExchange) query(input queryOrderInput) (SpotOrder, error) {
+	return e.Query(input.Symbol, input.OrderID, input.OrigClientOrderID)
+}
+
+// Order returns the order with the given order ID, or false if there is no such order
+func (e *SimulatedExchange) Order(orderID int64) (SpotOrder, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	o, ok := e.orders[orderID]
+	if !ok {
+		return SpotOrder{}, false
+	}
+	return o.SpotOrder, true
+}
+
+// OpenOrders returns the open orders, sorted by order ID, on the given symbol or on all symbols if it is empty
+func (e *SimulatedExchange) OpenOrders(symbol string) []SpotOrder {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := []SpotOrder{}
+	for _, o := range e.orders {
+		if !o.Status.IsTerminal() && (symbol == "" || o.Symbol == symbol) {
+			out = append(out, o.SpotOrder)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].OrderID < out[j].OrderID
+	})
+	return out
+}
+
+// AccountInformation returns the balances held on the exchange
+func (e *SimulatedExchange) AccountInformation() AccountInformation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	info := AccountInformation{
+		MakerCommission: commissionBasisPoints(e.makerFee),
+		TakerCommission: commissionBasisPoints(e.takerFee),
+		CanTrade:        true,
+		UpdateTime:      e.updateTime,
+		AccountType:     "SPOT",
+		Balances:        make(map[string]Balance),
+		Permissions:     []string{"SPOT"},
+	}
+	for asset, b := range e.balances {
+		info.Balances[asset] = b
+	}
+	return info
+}
+
+// equity returns the total value of the balances in the valuation asset.  Other assets are valued at the last
+// traded price of a symbol trading them against the valuation asset, and are ignored if there is none.
+func (e *SimulatedExchange) equity(valuationAsset string) *big.Float {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	total := new(big.Float)
+	for asset, b := range e.balances {
+		amount := new(big.Float).Add(b.Free, b.Locked)
+		if asset == valuationAsset {
+			total.Add(total, amount)
+			continue
+		}
+		for symbol, sym := range e.symbols {
+			book, ok := e.books[symbol]
+			if !ok || book.lastPrice == nil || book.lastPrice.Sign() == 0 {
+				continue
+			}
+			if sym.base == asset && sym.quote == valuationAsset {
+				total.Add(total, amount.Mul(amount, book.lastPrice))
+				break
+			}
+			if sym.base == valuationAsset && sym.quote == asset {
+				total.Add(total, amount.Quo(amount, book.lastPrice))
+				break
+			}
+		}
+	}
+	return total
+}
+
+func clientOrderIDKey(symbol string, clientOrderID string) string {
+	return symbol + " " + clientOrderID
+}
+
+// commissionBasisPoints converts a fee rate into the units used by binance for account commissions
+func commissionBasisPoints(fee *big.Float) int64 {
+	bp, _ := new(big.Float).Mul(fee, big.NewFloat(10000)).Float64()
+	return int64(bp + 0.5)
+}
+
+func minFloat(a, b *big.Float) *big.Float {
+	if a.Cmp(b) <= 0 {
+		return new(big.Float).Copy(a)
+	}
+	return new(big.Float).Copy(b)
+}
+
+// truncateQuantity truncates f to simulatedQuantityDecimals decimal places
+func truncateQuantity(f *big.Float) *big.Float {
+	i, _ := new(big.Float).Mul(f, simulatedQuantityScale).Int(nil)
+	return new(big.Float).Quo(new(big.Float).SetInt(i), simulatedQuantityScale)
+}
@@ -0,0 +1,542 @@
+package gobinance
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var simulatedTime = time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+var floatComparer = cmp.Comparer(func(a, b *big.Float) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+})
+
+func bf(v float64) *big.Float {
+	return big.NewFloat(v)
+}
+
+func priceLevels(priceQtys ...float64) []PriceLevel {
+	var out []PriceLevel
+	for i := 0; i < len(priceQtys); i += 2 {
+		out = append(out, PriceLevel{Price: bf(priceQtys[i]), Quantity: bf(priceQtys[i+1])})
+	}
+	return out
+}
+
+func newTestSimulatedExchange() *SimulatedExchange {
+	e := NewSimulatedExchange()
+	e.makerFee, e.takerFee = new(big.Float), new(big.Float)
+	e.AddSymbol("BTCUSDT", "BTC", "USDT")
+	e.SetBalance("BTC", bf(10))
+	e.SetBalance("USDT", bf(1000))
+	return e
+}
+
+func expectSimulatedBalances(t *testing.T, e *SimulatedExchange, expected map[string]Balance) {
+	t.Helper()
+	got := e.AccountInformation().Balances
+	if diff := cmp.Diff(expected, got, floatComparer); diff != "" {
+		t.Errorf("unexpected balances.\n%s", diff)
+	}
+}
+
+func expectSimulatedError(t *testing.T, err error, code int) {
+	t.Helper()
+	var httpErr *HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != code {
+		t.Errorf("expected error code %v but got %v", code, err)
+	}
+}
+
+func TestSimulatedExchange_Place(t *testing.T) {
+	t.Parallel()
+	limitBuy := spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideBuy, Quantity: bf(2), Price: bf(101), TimeInForce: TimeInForceGoodTilCanceled}
+	testCases := []struct {
+		name             string
+		setup            func(e *SimulatedExchange)
+		input            spotOrderInput
+		expectedErrCode  int
+		expectedStatus   OrderStatus
+		expectedFills    []Fill
+		expectedBalances map[string]Balance
+	}{
+		{
+			name: "market buy walks the book",
+			setup: func(e *SimulatedExchange) {
+				e.takerFee = bf(0.25)
+				e.Depth("BTCUSDT", priceLevels(99, 1), priceLevels(100, 1, 101, 1))
+			},
+			input:          spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeMarket, Side: OrderSideBuy, Quantity: bf(1.5)},
+			expectedStatus: OrderStatusFilled,
+			expectedFills: []Fill{
+				{Price: bf(100), Qty: bf(1), Commission: bf(0.25), CommissionAsset: "BTC", TradeID: 1},
+				{Price: bf(101), Qty: bf(0.5), Commission: bf(0.125), CommissionAsset: "BTC", TradeID: 2},
+			},
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(11.125), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(849.5), Locked: bf(0)},
+			},
+		},
+		{
+			name: "market buy of a quote quantity",
+			setup: func(e *SimulatedExchange) {
+				e.Depth("BTCUSDT", nil, priceLevels(100, 1, 200, 1))
+			},
+			input:          spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeMarket, Side: OrderSideBuy, QuoteOrderQty: bf(150)},
+			expectedStatus: OrderStatusFilled,
+			expectedFills: []Fill{
+				{Price: bf(100), Qty: bf(1), Commission: bf(0), CommissionAsset: "BTC", TradeID: 1},
+				{Price: bf(200), Qty: bf(0.25), Commission: bf(0), CommissionAsset: "BTC", TradeID: 2},
+			},
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(11.25), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(850), Locked: bf(0)},
+			},
+		},
+		{
+			name: "market sell beyond the known levels with slippage",
+			setup: func(e *SimulatedExchange) {
+				e.slippage = bf(0.125)
+				e.takerFee = bf(0.5)
+				e.Depth("BTCUSDT", priceLevels(160, 1), nil)
+			},
+			input:          spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeMarket, Side: OrderSideSell, Quantity: bf(3)},
+			expectedStatus: OrderStatusFilled,
+			expectedFills: []Fill{
+				{Price: bf(140), Qty: bf(1), Commission: bf(70), CommissionAsset: "USDT", TradeID: 1},
+				{Price: bf(140), Qty: bf(2), Commission: bf(140), CommissionAsset: "USDT", TradeID: 2},
+			},
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(7), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(1210), Locked: bf(0)},
+			},
+		},
+		{
+			name:  "market order without market data",
+			input: spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeMarket, Side: OrderSideSell, Quantity: bf(1)},
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(10), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(1000), Locked: bf(0)},
+			},
+		},
+		{
+			name: "limit order rests the remainder",
+			setup: func(e *SimulatedExchange) {
+				e.Depth("BTCUSDT", nil, priceLevels(100, 1, 102, 1))
+			},
+			input:          limitBuy,
+			expectedStatus: OrderStatusPartiallyFilled,
+			expectedFills: []Fill{
+				{Price: bf(100), Qty: bf(1), Commission: bf(0), CommissionAsset: "BTC", TradeID: 1},
+			},
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(11), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(799), Locked: bf(101)},
+			},
+		},
+		{
+			name: "slippage does not exceed the limit price",
+			setup: func(e *SimulatedExchange) {
+				e.slippage = bf(0.5)
+				e.Depth("BTCUSDT", nil, priceLevels(100, 5))
+			},
+			input:          limitBuy,
+			expectedStatus: OrderStatusFilled,
+			expectedFills: []Fill{
+				{Price: bf(101), Qty: bf(2), Commission: bf(0), CommissionAsset: "BTC", TradeID: 1},
+			},
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(12), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(798), Locked: bf(0)},
+			},
+		},
+		{
+			name: "immediate or cancel expires the remainder",
+			setup: func(e *SimulatedExchange) {
+				e.Depth("BTCUSDT", nil, priceLevels(100, 1, 102, 1))
+			},
+			input: spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideBuy, Quantity: bf(2), Price: bf(101),
+				TimeInForce: TimeInForceImmediateOrCancel},
+			expectedStatus: OrderStatusExpired,
+			expectedFills: []Fill{
+				{Price: bf(100), Qty: bf(1), Commission: bf(0), CommissionAsset: "BTC", TradeID: 1},
+			},
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(11), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(900), Locked: bf(0)},
+			},
+		},
+		{
+			name: "fill or kill expires without fills",
+			setup: func(e *SimulatedExchange) {
+				e.Depth("BTCUSDT", nil, priceLevels(100, 1, 102, 1))
+			},
+			input: spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideBuy, Quantity: bf(2), Price: bf(101),
+				TimeInForce: TimeInForceFillOrKill},
+			expectedStatus: OrderStatusExpired,
+			expectedFills:  []Fill{},
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(10), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(1000), Locked: bf(0)},
+			},
+		},
+		{
+			name: "limit order against the last price",
+			setup: func(e *SimulatedExchange) {
+				e.Trade("BTCUSDT", bf(100), bf(1), simulatedTime)
+			},
+			input:          spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideSell, Quantity: bf(4), Price: bf(99), TimeInForce: TimeInForceGoodTilCanceled},
+			expectedStatus: OrderStatusFilled,
+			expectedFills: []Fill{
+				{Price: bf(100), Qty: bf(4), Commission: bf(0), CommissionAsset: "USDT", TradeID: 1},
+			},
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(6), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(1400), Locked: bf(0)},
+			},
+		},
+		{
+			name: "limit maker which would take",
+			setup: func(e *SimulatedExchange) {
+				e.Depth("BTCUSDT", nil, priceLevels(100, 1))
+			},
+			input:           spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeLimitMaker, Side: OrderSideBuy, Quantity: bf(1), Price: bf(100)},
+			expectedErrCode: errorCodeNewOrderRejected,
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(10), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(1000), Locked: bf(0)},
+			},
+		},
+		{
+			name:            "insufficient balance",
+			input:           spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideBuy, Quantity: bf(20), Price: bf(100), TimeInForce: TimeInForceGoodTilCanceled},
+			expectedErrCode: errorCodeNewOrderRejected,
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(10), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(1000), Locked: bf(0)},
+			},
+		},
+		{
+			name: "duplicate client order id",
+			setup: func(e *SimulatedExchange) {
+				_, _ = e.place(spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideSell, Quantity: bf(1), Price: bf(200),
+					TimeInForce: TimeInForceGoodTilCanceled, NewClientOrderID: "abc"}, simulatedTime)
+			},
+			input: spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideSell, Quantity: bf(1), Price: bf(200),
+				TimeInForce: TimeInForceGoodTilCanceled, NewClientOrderID: "abc"},
+			expectedErrCode: errorCodeNewOrderRejected,
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(9), Locked: bf(1)},
+				"USDT": {Asset: "USDT", Free: bf(1000), Locked: bf(0)},
+			},
+		},
+		{
+			name:            "unknown symbol",
+			input:           spotOrderInput{Symbol: "ETHUSDT", Type: OrderTypeLimit, Side: OrderSideSell, Quantity: bf(1), Price: bf(1)},
+			expectedErrCode: errorCodeInvalidSymbol,
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(10), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(1000), Locked: bf(0)},
+			},
+		},
+		{
+			name:            "invalid quantity",
+			input:           spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeMarket, Side: OrderSideSell},
+			expectedErrCode: errorCodeInvalidQuantity,
+			expectedBalances: map[string]Balance{
+				"BTC":  {Asset: "BTC", Free: bf(10), Locked: bf(0)},
+				"USDT": {Asset: "USDT", Free: bf(1000), Locked: bf(0)},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			e := newTestSimulatedExchange()
+			if tc.setup != nil {
+				tc.setup(e)
+			}
+			result, err := e.place(tc.input, simulatedTime)
+			defer expectSimulatedBalances(t, e, tc.expectedBalances)
+			if tc.expectedStatus == "" {
+				if err == nil {
+					t.Errorf("expected an error but got %#v", result)
+				}
+				if tc.expectedErrCode != 0 {
+					expectSimulatedError(t, err, tc.expectedErrCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Status != tc.expectedStatus {
+				t.Errorf("unexpected status. expected %v but got %v", tc.expectedStatus, result.Status)
+			}
+			if diff := cmp.Diff(tc.expectedFills, result.Fills, floatComparer); diff != "" {
+				t.Errorf("unexpected fills.\n%s", diff)
+			}
+			order, err := e.query(queryOrderInput{Symbol: "BTCUSDT", OrderID: int64(result.OrderID)})
+			if err != nil {
+				t.Fatalf("unexpected error querying order: %v", err)
+			}
+			if order.Status != result.Status || order.ExecutedQty.Cmp(result.ExecutedQty) != 0 || order.CumulativeQuoteQty.Cmp(result.CumulativeQuoteQty) != 0 {
+				t.Errorf("queried order %#v does not match result %#v", order, result)
+			}
+		})
+	}
+}
+
+func TestSimulatedExchange_ConsumesLiquidity(t *testing.T) {
+	t.Parallel()
+	e := newTestSimulatedExchange()
+	e.Depth("BTCUSDT", nil, priceLevels(100, 1, 101, 1))
+	input := spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeMarket, Side: OrderSideBuy, Quantity: bf(0.5)}
+
+	var prices []float64
+	for i := 0; i < 3; i++ {
+		result, err := e.place(input, simulatedTime)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		price, _ := result.Fills[0].Price.Float64()
+		prices = append(prices, price)
+	}
+	if diff := cmp.Diff([]float64{100, 100, 101}, prices); diff != "" {
+		t.Errorf("unexpected fill prices.\n%s", diff)
+	}
+}
+
+func TestSimulatedExchange_MakerFills(t *testing.T) {
+	t.Parallel()
+	e := newTestSimulatedExchange()
+	e.makerFee = bf(0.25)
+	buy, err := e.place(spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideBuy, Quantity: bf(2), Price: bf(100),
+		TimeInForce: TimeInForceGoodTilCanceled}, simulatedTime)
+	if err != nil || buy.Status != OrderStatusNew {
+		t.Fatalf("unexpected result %#v, %v", buy, err)
+	}
+	sell, err := e.place(spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideSell, Quantity: bf(1), Price: bf(110),
+		TimeInForce: TimeInForceGoodTilCanceled, NewClientOrderID: "sell"}, simulatedTime)
+	if err != nil || sell.Status != OrderStatusNew {
+		t.Fatalf("unexpected result %#v, %v", sell, err)
+	}
+	expectSimulatedBalances(t, e, map[string]Balance{
+		"BTC":  {Asset: "BTC", Free: bf(9), Locked: bf(1)},
+		"USDT": {Asset: "USDT", Free: bf(800), Locked: bf(200)},
+	})
+
+	// trades between the orders' prices fill neither
+	e.Trade("BTCUSDT", bf(105), bf(10), simulatedTime)
+	e.Trade("BTCUSDT", bf(100), bf(0.5), simulatedTime.Add(time.Second))
+	order, _ := e.query(queryOrderInput{Symbol: "BTCUSDT", OrderID: int64(buy.OrderID)})
+	if order.Status != OrderStatusPartiallyFilled || order.ExecutedQty.Cmp(bf(0.5)) != 0 || order.CumulativeQuoteQty.Cmp(bf(50)) != 0 ||
+		!order.UpdateTime.Equal(simulatedTime.Add(time.Second)) {
+		t.Errorf("unexpected order %#v", order)
+	}
+	e.Trade("BTCUSDT", bf(99), bf(5), simulatedTime)
+	e.Trade("BTCUSDT", bf(120), bf(5), simulatedTime)
+	expectSimulatedBalances(t, e, map[string]Balance{
+		"BTC":  {Asset: "BTC", Free: bf(10.5), Locked: bf(0)},
+		"USDT": {Asset: "USDT", Free: bf(882.5), Locked: bf(0)},
+	})
+	if open := e.OpenOrders(""); len(open) != 0 {
+		t.Errorf("expected no open orders but got %#v", open)
+	}
+	order, err = e.query(queryOrderInput{Symbol: "BTCUSDT", OrigClientOrderID: "sell"})
+	if err != nil || order.Status != OrderStatusFilled {
+		t.Errorf("unexpected order %#v, %v", order, err)
+	}
+}
+
+func TestSimulatedExchange_Cancel(t *testing.T) {
+	t.Parallel()
+	e := newTestSimulatedExchange()
+	result, err := e.place(spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideBuy, Quantity: bf(2), Price: bf(100),
+		TimeInForce: TimeInForceGoodTilCanceled, NewClientOrderID: "abc"}, simulatedTime)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e.Trade("BTCUSDT", bf(100), bf(0.5), simulatedTime)
+	if open := e.OpenOrders("BTCUSDT"); len(open) != 1 {
+		t.Errorf("expected a single open order but got %#v", open)
+	}
+
+	cancelled, err := e.cancel(cancelSpotOrderInput{Symbol: "BTCUSDT", OrigClientOrderID: "abc"}, simulatedTime)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cancelled.Status != OrderStatusCanceled || cancelled.OrderID != int64(result.OrderID) || cancelled.ExecutedQty.Cmp(bf(0.5)) != 0 {
+		t.Errorf("unexpected result %#v", cancelled)
+	}
+	expectSimulatedBalances(t, e, map[string]Balance{
+		"BTC":  {Asset: "BTC", Free: bf(10.5), Locked: bf(0)},
+		"USDT": {Asset: "USDT", Free: bf(950), Locked: bf(0)},
+	})
+
+	_, err = e.cancel(cancelSpotOrderInput{Symbol: "BTCUSDT", OrderID: int64(result.OrderID)}, simulatedTime)
+	expectSimulatedError(t, err, errorCodeCancelRejected)
+	_, err = e.query(queryOrderInput{Symbol: "BTCUSDT", OrderID: 1234})
+	expectSimulatedError(t, err, errorCodeNoSuchOrder)
+	_, err = e.query(queryOrderInput{Symbol: "ETHUSDT", OrderID: int64(result.OrderID)})
+	expectSimulatedError(t, err, errorCodeNoSuchOrder)
+}
+
+func TestSimulatedExchange_StopOrders(t *testing.T) {
+	t.Parallel()
+	e := newTestSimulatedExchange()
+	e.Trade("BTCUSDT", bf(100), bf(1), simulatedTime)
+
+	_, err := e.place(spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeStopLossLimit, Side: OrderSideBuy, Quantity: bf(1), Price: bf(100),
+		StopPrice: bf(99), TimeInForce: TimeInForceGoodTilCanceled}, simulatedTime)
+	expectSimulatedError(t, err, errorCodeNewOrderRejected)
+	_, err = e.place(spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeTakeProfitLimit, Side: OrderSideBuy, Quantity: bf(1), Price: bf(100),
+		TimeInForce: TimeInForceGoodTilCanceled}, simulatedTime)
+	expectSimulatedError(t, err, errorCodeInvalidQuantity)
+
+	stopLoss, err := e.place(spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeStopLossLimit, Side: OrderSideSell, Quantity: bf(1), Price: bf(94),
+		StopPrice: bf(95), TimeInForce: TimeInForceGoodTilCanceled}, simulatedTime)
+	if err != nil || stopLoss.Status != OrderStatusNew || len(stopLoss.Fills) != 0 {
+		t.Fatalf("unexpected result %#v, %v", stopLoss, err)
+	}
+	takeProfit, err := e.place(spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeTakeProfitLimit, Side: OrderSideBuy, Quantity: bf(1), Price: bf(91),
+		StopPrice: bf(90), TimeInForce: TimeInForceGoodTilCanceled}, simulatedTime)
+	if err != nil || takeProfit.Status != OrderStatusNew {
+		t.Fatalf("unexpected result %#v, %v", takeProfit, err)
+	}
+	unfilled, err := e.place(spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeStopLossLimit, Side: OrderSideSell, Quantity: bf(2), Price: bf(85),
+		StopPrice: bf(80), TimeInForce: TimeInForceImmediateOrCancel}, simulatedTime)
+	if err != nil || unfilled.Status != OrderStatusNew {
+		t.Fatalf("unexpected result %#v, %v", unfilled, err)
+	}
+	expectSimulatedBalances(t, e, map[string]Balance{
+		"BTC":  {Asset: "BTC", Free: bf(7), Locked: bf(3)},
+		"USDT": {Asset: "USDT", Free: bf(909), Locked: bf(91)},
+	})
+
+	// trades beyond the limit price but short of the stop price leave the orders untriggered
+	e.Trade("BTCUSDT", bf(96), bf(1), simulatedTime)
+	if open := e.OpenOrders("BTCUSDT"); len(open) != 3 || open[0].IsWorking {
+		t.Errorf("unexpected open orders %#v", open)
+	}
+
+	e.Trade("BTCUSDT", bf(95), bf(1), simulatedTime.Add(time.Second))
+	order, _ := e.query(queryOrderInput{Symbol: "BTCUSDT", OrderID: int64(stopLoss.OrderID)})
+	if order.Status != OrderStatusFilled || order.CumulativeQuoteQty.Cmp(bf(95)) != 0 || !order.IsWorking ||
+		!order.UpdateTime.Equal(simulatedTime.Add(time.Second)) {
+		t.Errorf("unexpected order %#v", order)
+	}
+	// the take profit fills below its limit price, returning the difference
+	e.Trade("BTCUSDT", bf(90), bf(1), simulatedTime)
+	order, _ = e.query(queryOrderInput{Symbol: "BTCUSDT", OrderID: int64(takeProfit.OrderID)})
+	if order.Status != OrderStatusFilled || order.CumulativeQuoteQty.Cmp(bf(90)) != 0 {
+		t.Errorf("unexpected order %#v", order)
+	}
+	// the immediate or cancel stop loss cannot fill at its limit price once triggered, so expires
+	e.Trade("BTCUSDT", bf(80), bf(1), simulatedTime)
+	order, _ = e.query(queryOrderInput{Symbol: "BTCUSDT", OrderID: int64(unfilled.OrderID)})
+	if order.Status != OrderStatusExpired || order.ExecutedQty.Sign() != 0 {
+		t.Errorf("unexpected order %#v", order)
+	}
+	expectSimulatedBalances(t, e, map[string]Balance{
+		"BTC":  {Asset: "BTC", Free: bf(10), Locked: bf(0)},
+		"USDT": {Asset: "USDT", Free: bf(1005), Locked: bf(0)},
+	})
+}
+
+func TestSimulatedExchange_StopOrdersLimitedByTradeQuantity(t *testing.T) {
+	t.Parallel()
+	e := newTestSimulatedExchange()
+	e.Trade("BTCUSDT", bf(100), bf(1), simulatedTime)
+
+	stopLoss, err := e.place(spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeStopLossLimit, Side: OrderSideSell, Quantity: bf(2), Price: bf(90),
+		StopPrice: bf(95), TimeInForce: TimeInForceGoodTilCanceled}, simulatedTime)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	resting, err := e.place(spotOrderInput{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideBuy, Quantity: bf(2), Price: bf(95),
+		TimeInForce: TimeInForceGoodTilCanceled}, simulatedTime)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// without a book, the triggered stop takes the trade's quantity, leaving none for the resting order
+	e.Trade("BTCUSDT", bf(95), big.NewFloat(0.5), simulatedTime)
+	order, _ := e.Order(int64(stopLoss.OrderID))
+	if order.Status != OrderStatusPartiallyFilled || order.ExecutedQty.Cmp(big.NewFloat(0.5)) != 0 {
+		t.Errorf("unexpected stop order %#v", order)
+	}
+	order, _ = e.Order(int64(resting.OrderID))
+	if order.Status != OrderStatusNew || order.ExecutedQty.Sign() != 0 {
+		t.Errorf("unexpected resting order %#v", order)
+	}
+}
+
+func TestSimulatedExchange_Executions(t *testing.T) {
+	t.Parallel()
+	e := newTestSimulatedExchange()
+	type execution struct {
+		orderID       int64
+		executionType ExecutionType
+		status        OrderStatus
+		isMaker       bool
+		fillQty       *big.Float
+		btc           *big.Float
+	}
+	var got []execution
+	e.SetExecutionHandler(func(x SimulatedExecution) {
+		var fillQty *big.Float
+		if x.Fill != nil {
+			fillQty = x.Fill.Qty
+		}
+		got = append(got, execution{
+			orderID:       x.Order.OrderID,
+			executionType: x.ExecutionType,
+			status:        x.Order.Status,
+			isMaker:       x.IsMaker,
+			fillQty:       fillQty,
+			btc:           x.Balances[0].Free,
+		})
+	})
+
+	_, err := e.Place(SimulatedOrderRequest{Symbol: "BTCUSDT", Type: OrderTypeMarket, Side: OrderSideBuy, Quantity: bf(1)}, simulatedTime)
+	expectSimulatedError(t, err, errorCodeNewOrderRejected)
+	for _, req := range []SimulatedOrderRequest{
+		{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideBuy, Quantity: bf(1), Price: bf(100), TimeInForce: TimeInForceGoodTilCanceled},
+		{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideBuy, Quantity: bf(2), Price: bf(99), TimeInForce: TimeInForceGoodTilCanceled},
+	} {
+		if _, err := e.Place(req, simulatedTime); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// a trade without a quantity fills every order it crosses
+	e.Trade("BTCUSDT", bf(99), nil, simulatedTime)
+	e.Depth("BTCUSDT", priceLevels(95, 1), nil)
+	if _, err := e.Place(SimulatedOrderRequest{Symbol: "BTCUSDT", Type: OrderTypeLimit, Side: OrderSideSell, Quantity: bf(2), Price: bf(90),
+		TimeInForce: TimeInForceImmediateOrCancel}, simulatedTime); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := e.Cancel(SimulatedCancelRequest{Symbol: "BTCUSDT", OrderID: 1}, simulatedTime); err == nil {
+		t.Errorf("expected an error cancelling a filled order")
+	}
+
+	expected := []execution{
+		{orderID: 1, executionType: ExecutionTypeNew, status: OrderStatusNew, btc: bf(10)},
+		{orderID: 2, executionType: ExecutionTypeNew, status: OrderStatusNew, btc: bf(10)},
+		{orderID: 1, executionType: ExecutionTypeTrade, status: OrderStatusFilled, isMaker: true, fillQty: bf(1), btc: bf(11)},
+		{orderID: 2, executionType: ExecutionTypeTrade, status: OrderStatusFilled, isMaker: true, fillQty: bf(2), btc: bf(13)},
+		{orderID: 3, executionType: ExecutionTypeNew, status: OrderStatusNew, btc: bf(13)},
+		{orderID: 3, executionType: ExecutionTypeTrade, status: OrderStatusPartiallyFilled, fillQty: bf(1), btc: bf(12)},
+		{orderID: 3, executionType: ExecutionTypeExpired, status: OrderStatusExpired, btc: bf(12)},
+	}
+	if diff := cmp.Diff(expected, got, cmp.AllowUnexported(execution{}), floatComparer); diff != "" {
+		t.Errorf("unexpected executions.\n%s", diff)
+	}
+}
@@ -3,12 +3,23 @@ package gobinance
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
 	"time"
 )
 
+const (
+	// defaultRecvWindow is the receive window used by binance when none is provided
+	defaultRecvWindow = 5 * time.Second
+	// orderResolutionTimeout is the maximum time spent querying an order placed using SpotIdempotent, once the receive
+	// window has passed
+	orderResolutionTimeout = 10 * time.Second
+	// errorCodeNoSuchOrder is the error code returned by binance when querying an order that does not exist
+	errorCodeNoSuchOrder = -2013
+)
+
 type spotOrderInput struct {
 	Symbol           string            `param:"symbol,omitempty"`
 	Side             OrderSide         `param:"side,omitempty"`
@@ -22,6 +33,8 @@ type spotOrderInput struct {
 	IcebergQty       int               `param:"icebergQty,omitempty"`
 	NewOrderRespType OrderResponseType `param:"newOrderRespType,omitempty"`
 	RecvWindow       int64             `param:"recvWindow,omitempty"`
+
+	idempotent bool
 }
 
 type Fill struct {
@@ -29,6 +42,7 @@ type Fill struct {
 	Qty             *big.Float
 	Commission      *big.Float
 	CommissionAsset string
+	TradeID         int64
 }
 
 type SpotOrderResult struct {
@@ -105,6 +119,25 @@ func SpotOrderRecvWindow(d time.Duration) SpotOrderOption {
 	}
 }
 
+// SpotIdempotent is a SpotOrderOption which makes it safe to treat a failed request to place an order as definite.
+//
+// A client order ID is generated for the order unless one is provided using SpotClientOrderID, using the client's
+// ClientOrderIDGenerator or a ULIDGenerator if that is nil.  If the request
+// fails in a way that leaves it unknown whether binance placed the order, such as a timeout or a 5xx response,
+// the order is queried by its client order ID once binance would no longer accept the request.  If the order
+// exists it is returned without error (although without Fills), and if it does not the original error is returned.
+// If the outcome cannot be resolved, the error returned is an *OrderStatusUnknownError, which matches
+// ErrOrderStatusUnknown using errors.Is.
+//
+// As the failure may have been caused by the deadline of ctx, resolution does not use ctx's deadline, and may take up
+// to the receive window plus 10 seconds.  Cancelling ctx while waiting for the receive window leaves the outcome
+// unknown.
+func SpotIdempotent() SpotOrderOption {
+	return func(s *spotOrderInput) {
+		s.idempotent = true
+	}
+}
+
 func applySpotOrderOptions(input *spotOrderInput, opts ...SpotOrderOption) {
 	for _, o := range opts {
 		o(input)
@@ -225,6 +258,9 @@ func (c *Client) PlaceLimitMakerOrder(ctx context.Context, symbol string, side O
 func (c *Client) placeOrder(ctx context.Context, input spotOrderInput, opts []SpotOrderOption) (SpotOrderResult, error) {
 	input.NewOrderRespType = OrderResponseTypeFull
 	applySpotOrderOptions(&input, opts...)
+	if err := c.assignClientOrderID(&input); err != nil {
+		return SpotOrderResult{}, err
+	}
 	params, err := toURLValues(input)
 	if err != nil {
 		return SpotOrderResult{}, fmt.Errorf("error building request parameters: %w", err)
@@ -237,5 +273,93 @@ func (c *Client) placeOrder(ctx context.Context, input spotOrderInput, opts []Sp
 
 	var result SpotOrderResult
 	err = performRequest(c.Doer, req, &result)
+	if err != nil && input.idempotent && isAmbiguousOrderError(err) {
+		return c.resolveOrder(ctx, input, err)
+	}
 	return result, err
 }
+
+// assignClientOrderID generates the client order ID of an order placed without one, if the client has a
+// ClientOrderIDGenerator or the order is idempotent
+func (c *Client) assignClientOrderID(input *spotOrderInput) error {
+	if input.NewClientOrderID != "" || (c.ClientOrderIDGenerator == nil && !input.idempotent) {
+		return nil
+	}
+	generator := c.ClientOrderIDGenerator
+	if generator == nil {
+		generator = &ULIDGenerator{Now: c.Now}
+	}
+	id, err := generator.NewClientOrderID()
+	if err != nil {
+		return fmt.Errorf("error generating client order id: %w", err)
+	}
+	input.NewClientOrderID = id
+	return nil
+}
+
+// isAmbiguousOrderError returns true if err leaves it unknown whether the order was placed.  Only 4xx responses
+// indicate that binance definitely rejected the order.
+func isAmbiguousOrderError(err error) bool {
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) {
+		return httpErr.HttpStatus >= 500
+	}
+	return true
+}
+
+// resolveOrder waits until binance would reject the request to place the order, then queries the order to find
+// out whether it was placed.  The deadline of ctx is ignored, but if ctx is cancelled while waiting the outcome is
+// unknown.
+func (c *Client) resolveOrder(ctx context.Context, input spotOrderInput, placeErr error) (SpotOrderResult, error) {
+	recvWindow := defaultRecvWindow
+	if input.RecvWindow > 0 {
+		recvWindow = time.Duration(input.RecvWindow) * time.Millisecond
+	} else if c.RecvWindow > 0 {
+		recvWindow = c.RecvWindow
+	}
+	resolveCtx, cancel := context.WithTimeout(context.Background(), recvWindow+orderResolutionTimeout)
+	defer cancel()
+	unknown := func(err error) error {
+		return &OrderStatusUnknownError{
+			ClientOrderID: input.NewClientOrderID,
+			PlaceErr:      placeErr,
+			ResolveErr:    err,
+		}
+	}
+
+	t := time.NewTimer(recvWindow)
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.Canceled) {
+			t.Stop()
+			return SpotOrderResult{}, unknown(ctx.Err())
+		}
+		// the deadline of ctx has passed, which is likely what caused the request to fail
+		<-t.C
+	}
+
+	order, err := c.QueryOrderByClientID(resolveCtx, input.Symbol, input.NewClientOrderID)
+	if err != nil {
+		var httpErr *HttpError
+		if errors.As(err, &httpErr) && httpErr.Code == errorCodeNoSuchOrder {
+			return SpotOrderResult{}, fmt.Errorf("order was not placed: %w", placeErr)
+		}
+		return SpotOrderResult{}, unknown(err)
+	}
+	return SpotOrderResult{
+		Symbol:             order.Symbol,
+		OrderID:            int(order.OrderID),
+		OrderListID:        int(order.OrderListID),
+		ClientOrderID:      order.ClientOrderID,
+		TransactTime:       order.Time,
+		Price:              order.Price,
+		OrigQty:            order.OriginalQty,
+		ExecutedQty:        order.ExecutedQty,
+		CumulativeQuoteQty: order.CumulativeQuoteQty,
+		Status:             order.Status,
+		TimeInForce:        order.TimeInForce,
+		Type:               order.Type,
+		Side:               order.Side,
+	}, nil
+}
@@ -2,6 +2,7 @@ package gobinance_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/beyondallrepair/gobinance"
 	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
@@ -393,3 +394,169 @@ func TestClient_PlaceTakeProfitOrder(t *testing.T) {
 	})
 	runSpotOrderTestCases(t, common...)
 }
+
+func TestClient_PlaceOrderIdempotent(t *testing.T) {
+	t.Parallel()
+	const queryResponse = `{"symbol":"BTCUSDT","orderId":28,"orderListId":-1,"clientOrderId":"test-client-id","price":"2.25","origQty":"1.25","executedQty":"0","cummulativeQuoteQty":"0","status":"NEW","timeInForce":"GTC","type":"LIMIT","side":"SELL","time":1507725176595,"updateTime":1507725176595}`
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	call := func(ctx context.Context, client *gobinance.Client, opts []gobinance.SpotOrderOption) (gobinance.SpotOrderResult, error) {
+		opts = append(opts, gobinance.SpotIdempotent(), gobinance.SpotOrderRecvWindow(time.Millisecond))
+		return client.PlaceLimitOrder(ctx, "BTCUSDT", gobinance.OrderSideSell, big.NewFloat(1.25), big.NewFloat(2.25), gobinance.TimeInForceGoodTilCanceled, opts...)
+	}
+	// expectPlaceThenQuery expects the order to be placed, returning the given response and error, followed by a
+	// query for the order returning queryStatus and queryBody
+	expectPlaceThenQuery := func(placeResp *http.Response, placeErr error, queryStatus int, queryBody string) func(t *testing.T, mocks *clientMocks) {
+		return func(t *testing.T, mocks *clientMocks) {
+			mocks.MockSigner.EXPECT().Sign(gomock.Any()).Return(mockSignature).AnyTimes()
+			var clientOrderID string
+			place := mocks.MockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				clientOrderID = req.URL.Query().Get("newClientOrderId")
+				return placeResp, placeErr
+			})
+			mocks.MockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				if req.Method != http.MethodGet || req.URL.Path != "/api/v3/order" {
+					t.Errorf("expected order query but got %v %v", req.Method, req.URL.Path)
+				}
+				if got := req.URL.Query().Get("origClientOrderId"); got != clientOrderID {
+					t.Errorf("expected query for client order id %q but got %q", clientOrderID, got)
+				}
+				return &http.Response{
+					StatusCode: queryStatus,
+					Body:       ioutil.NopCloser(strings.NewReader(queryBody)),
+				}, nil
+			}).After(place)
+		}
+	}
+	resolvedOrder := gobinance.SpotOrderResult{
+		Symbol:             "BTCUSDT",
+		OrderID:            28,
+		OrderListID:        -1,
+		ClientOrderID:      "test-client-id",
+		TransactTime:       time.Unix(0, int64(1507725176595*time.Millisecond)),
+		Price:              big.NewFloat(2.25),
+		OrigQty:            big.NewFloat(1.25),
+		ExecutedQty:        big.NewFloat(0),
+		CumulativeQuoteQty: big.NewFloat(0),
+		Status:             gobinance.OrderStatusNew,
+		TimeInForce:        gobinance.TimeInForceGoodTilCanceled,
+		Type:               gobinance.OrderTypeLimit,
+		Side:               gobinance.OrderSideSell,
+	}
+	serverError := func() *http.Response {
+		return &http.Response{
+			StatusCode: 503,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"code":-1000,"msg":"Unknown error, please check your request or try again later."}`)),
+		}
+	}
+
+	runSpotOrderTestCases(t,
+		spotOrderTestCase{
+			name: "client order id generated",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockSigner.EXPECT().Sign(gomock.Any()).Return(mockSignature)
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+					if id := req.URL.Query().Get("newClientOrderId"); len(id) != 26 {
+						t.Errorf("expected a generated client order id but got %q", id)
+					}
+					return nil, fmt.Errorf("stop early")
+				})
+				// the error is ambiguous, so the order is queried
+				mocks.MockSigner.EXPECT().Sign(gomock.Any()).Return(mockSignature)
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).Return(nil, fmt.Errorf("stop early"))
+			},
+			ctx:        context.Background(),
+			call:       call,
+			errorCheck: errNotNil,
+		},
+		spotOrderTestCase{
+			name: "client order id provided",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockSigner.EXPECT().Sign(gomock.Any()).Return(mockSignature)
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+					if id := req.URL.Query().Get("newClientOrderId"); id != "test-client-id" {
+						t.Errorf("unexpected client order id %q", id)
+					}
+					return &http.Response{
+						StatusCode: 400,
+						Body:       ioutil.NopCloser(strings.NewReader(`{"code":-1013,"msg":"Filter failure: PRICE_FILTER"}`)),
+					}, nil
+				})
+			},
+			ctx:        context.Background(),
+			options:    []gobinance.SpotOrderOption{gobinance.SpotClientOrderID("test-client-id")},
+			call:       call,
+			errorCheck: isHttpError(400, -1013),
+		},
+		spotOrderTestCase{
+			name:           "timeout resolved to placed order",
+			setup:          expectPlaceThenQuery(nil, context.DeadlineExceeded, 200, queryResponse),
+			ctx:            context.Background(),
+			call:           call,
+			errorCheck:     errNil,
+			expectedResult: resolvedOrder,
+		},
+		spotOrderTestCase{
+			name:  "server error resolved to order not placed",
+			setup: expectPlaceThenQuery(serverError(), nil, 400, `{"code":-2013,"msg":"Order does not exist."}`),
+			ctx:   context.Background(),
+			call:  call,
+			errorCheck: func(t *testing.T, err error) bool {
+				if errors.Is(err, gobinance.ErrOrderStatusUnknown) {
+					t.Errorf("expected a definite failure but got %v", err)
+				}
+				return isHttpError(503, -1000)(t, errors.Unwrap(err))
+			},
+		},
+		spotOrderTestCase{
+			name:           "resolved after the context's deadline caused the failure",
+			setup:          expectPlaceThenQuery(nil, context.DeadlineExceeded, 200, queryResponse),
+			ctx:            expired,
+			call:           call,
+			expectedResult: resolvedOrder,
+			errorCheck:     errNil,
+		},
+		spotOrderTestCase{
+			name: "cancelled while resolving",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockSigner.EXPECT().Sign(gomock.Any()).Return(mockSignature)
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).Return(nil, context.Canceled)
+			},
+			ctx: cancelled,
+			call: func(ctx context.Context, client *gobinance.Client, opts []gobinance.SpotOrderOption) (gobinance.SpotOrderResult, error) {
+				// the receive window is not waited for once the context is done
+				return client.PlaceLimitOrder(ctx, "BTCUSDT", gobinance.OrderSideSell, big.NewFloat(1.25), big.NewFloat(2.25), gobinance.TimeInForceGoodTilCanceled,
+					gobinance.SpotIdempotent(), gobinance.SpotOrderRecvWindow(time.Hour))
+			},
+			errorCheck: func(t *testing.T, err error) bool {
+				var unknown *gobinance.OrderStatusUnknownError
+				if !errors.As(err, &unknown) || unknown.ResolveErr != context.Canceled {
+					t.Errorf("expected ErrOrderStatusUnknown resolving with context.Canceled but got %v", err)
+				}
+				return false
+			},
+		},
+		spotOrderTestCase{
+			name:  "resolution failure",
+			setup: expectPlaceThenQuery(nil, context.DeadlineExceeded, 500, `{}`),
+			ctx:   context.Background(),
+			call:  call,
+			errorCheck: func(t *testing.T, err error) bool {
+				var unknown *gobinance.OrderStatusUnknownError
+				if !errors.Is(err, gobinance.ErrOrderStatusUnknown) || !errors.As(err, &unknown) {
+					t.Errorf("expected ErrOrderStatusUnknown but got %v", err)
+					return false
+				}
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("expected error to wrap the placement error but got %v", err)
+				}
+				if len(unknown.ClientOrderID) != 26 {
+					t.Errorf("expected the generated client order id but got %q", unknown.ClientOrderID)
+				}
+				return false
+			},
+		},
+	)
+}
@@ -0,0 +1,236 @@
+package gobinance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// BackpressurePolicy determines what happens to the messages read from a stream once its buffer is full because
+// the consumer has fallen behind
+type BackpressurePolicy int
+
+const (
+	// BackpressureBlock stops reading from the connection until the consumer catches up.  Nothing is dropped, but
+	// binance closes connections which are not read from for too long.
+	BackpressureBlock BackpressurePolicy = iota
+	// BackpressureDropOldest drops the oldest buffered message to make room for each new one
+	BackpressureDropOldest
+	// BackpressureDropNewest drops each new message until there is room for it
+	BackpressureDropNewest
+	// BackpressureConflate buffers only the latest message of each stream, event type and symbol, replacing any
+	// older message in its place.  It suits streams of snapshots, such as tickers and partial depth, rather than
+	// streams such as trades or depth updates in which every message matters.  The buffer's size is not limited
+	// other than by the number of symbols.
+	BackpressureConflate
+)
+
+// Validate returns nil if the value is a valid BackpressurePolicy, or an error if not.
+func (p BackpressurePolicy) Validate() error {
+	switch p {
+	case BackpressureBlock:
+	case BackpressureDropOldest:
+	case BackpressureDropNewest:
+	case BackpressureConflate:
+	default:
+		return fmt.Errorf("BackpressurePolicy, %v, is not known", int(p))
+	}
+	return nil
+}
+
+// StreamBuffer controls how messages read from a websocket stream are buffered while the consumer of the stream's
+// channel is busy.  The zero value blocks reading once a single message is buffered.
+//
+// Messages dropped are reported to the client's StreamObserver.  Errors and responses to StreamManager requests are
+// never dropped, and the user data stream and the diff depth stream of a LocalOrderBook always use
+// BackpressureBlock.
+//
+// Policies other than BackpressureBlock lose messages, so must not be used for streams whose consumers need every
+// message, such as those of DepthUpdates, which are diffs that cannot be applied once one has been missed.
+type StreamBuffer struct {
+	Policy BackpressurePolicy
+	// Size is the number of messages buffered before the policy applies.  Values less than one are treated as one.
+	Size int
+}
+
+// Validate returns nil if the buffer's policy is valid, or an error if not
+func (b StreamBuffer) Validate() error {
+	return b.Policy.Validate()
+}
+
+func (b StreamBuffer) limit() int {
+	if b.Size < 1 {
+		return 1
+	}
+	return b.Size
+}
+
+// ClientStreamBuffer sets how the client's websocket streams are buffered.  The default blocks once a single
+// message is buffered.
+func ClientStreamBuffer(buffer StreamBuffer) ClientOption {
+	return func(c *Client) {
+		c.StreamBuffer = buffer
+	}
+}
+
+type streamBufferKey struct{}
+
+// WithStreamBuffer returns a copy of ctx which causes streams opened using it to be buffered using buffer, in place
+// of the StreamBuffer set on the Client
+func WithStreamBuffer(ctx context.Context, buffer StreamBuffer) context.Context {
+	return context.WithValue(ctx, streamBufferKey{}, buffer)
+}
+
+// streamBuffer returns the buffering of a stream, from the context if set there, or the client otherwise
+func (c *Client) streamBuffer(ctx context.Context) StreamBuffer {
+	if buffer, ok := ctx.Value(streamBufferKey{}).(StreamBuffer); ok {
+		return buffer
+	}
+	return c.StreamBuffer
+}
+
+// queuedMessage is a message read from a websocket connection, or the error which ended it
+type queuedMessage struct {
+	data []byte
+	err  error
+	// key identifies the messages which replace one another when conflated
+	key string
+	// keep is true for messages which must not be dropped
+	keep bool
+}
+
+// streamQueue holds the messages read from a websocket connection until the consumer is ready for them, applying
+// the policy of a StreamBuffer once full
+type streamQueue struct {
+	buffer StreamBuffer
+
+	mu      sync.Mutex
+	entries []queuedMessage
+	// ready is signalled when a message is added, and space when one is removed
+	ready chan struct{}
+	space chan struct{}
+}
+
+func newStreamQueue(buffer StreamBuffer) *streamQueue {
+	return &streamQueue{
+		buffer: buffer,
+		ready:  make(chan struct{}, 1),
+		space:  make(chan struct{}, 1),
+	}
+}
+
+// newMessage returns the queuedMessage holding data.  Messages are only decoded to find their key when they may be
+// dropped.
+func (q *streamQueue) newMessage(data []byte) queuedMessage {
+	m := queuedMessage{data: data}
+	if q.buffer.Policy != BackpressureBlock {
+		m.key, m.keep = streamMessageKey(data)
+	}
+	return m
+}
+
+// push adds m to the queue, waiting for space when the policy is to block.  It returns the number of messages
+// dropped, and false if the context was done before m could be added.
+func (q *streamQueue) push(ctx context.Context, m queuedMessage) (int, bool) {
+	for {
+		q.mu.Lock()
+		dropped, added := q.add(m)
+		q.mu.Unlock()
+		if added {
+			signal(q.ready)
+			return dropped, true
+		}
+		select {
+		case <-q.space:
+		case <-ctx.Done():
+			return 0, false
+		}
+	}
+}
+
+// add adds m to the queue according to the policy, returning the number of messages dropped, and false if m must
+// wait for space.  q.mu must be held.
+func (q *streamQueue) add(m queuedMessage) (int, bool) {
+	if q.buffer.Policy == BackpressureConflate && !m.keep {
+		for i, e := range q.entries {
+			if !e.keep && e.key == m.key {
+				q.entries[i] = m
+				return 1, true
+			}
+		}
+		q.entries = append(q.entries, m)
+		return 0, true
+	}
+	if m.keep || len(q.entries) < q.buffer.limit() {
+		q.entries = append(q.entries, m)
+		return 0, true
+	}
+	switch q.buffer.Policy {
+	case BackpressureDropNewest:
+		return 1, true
+	case BackpressureDropOldest:
+		for i, e := range q.entries {
+			if !e.keep {
+				q.entries = append(q.entries[:i], q.entries[i+1:]...)
+				q.entries = append(q.entries, m)
+				return 1, true
+			}
+		}
+		q.entries = append(q.entries, m)
+		return 0, true
+	}
+	return 0, false
+}
+
+// pop removes and returns the oldest message in the queue, waiting for one to be added if empty.  It returns false
+// if the context is done first.
+func (q *streamQueue) pop(ctx context.Context) (queuedMessage, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.entries) > 0 {
+			m := q.entries[0]
+			q.entries[0] = queuedMessage{}
+			q.entries = q.entries[1:]
+			q.mu.Unlock()
+			signal(q.space)
+			return m, true
+		}
+		q.mu.Unlock()
+		select {
+		case <-q.ready:
+		case <-ctx.Done():
+			return queuedMessage{}, false
+		}
+	}
+}
+
+// signal notifies a waiter on ch, if there is not already a notification pending
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// streamMessageKey returns the key under which a message is conflated, made from the stream, event type and symbol
+// it holds, and whether it is a response to a StreamManager request, which must never be dropped.  Messages which
+// are not JSON objects, such as the arrays of all market tickers, all share the empty key.
+func streamMessageKey(data []byte) (string, bool) {
+	var msg struct {
+		ID     *uint64 `json:"id"`
+		Stream string  `json:"stream"`
+		Event  string  `json:"e"`
+		Symbol string  `json:"s"`
+		// these fields avoid the case insensitive matching of the fields above
+		EventTime json.RawMessage `json:"E"`
+		Side      json.RawMessage `json:"S"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return "", false
+	}
+	if msg.ID != nil {
+		return "", true
+	}
+	return msg.Stream + "|" + msg.Event + "|" + msg.Symbol, false
+}
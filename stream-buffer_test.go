@@ -0,0 +1,135 @@
+package gobinance
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"time"
+)
+
+func TestStreamQueue_Policies(t *testing.T) {
+	t.Parallel()
+	const (
+		btc1 = `{"e":"24hrTicker","E":1,"s":"BTCUSDT","c":"1"}`
+		eth1 = `{"e":"24hrTicker","E":2,"s":"ETHUSDT","c":"2"}`
+		btc2 = `{"e":"24hrTicker","E":3,"s":"BTCUSDT","c":"3"}`
+		resp = `{"result":null,"id":1}`
+		eth2 = `{"e":"24hrTicker","E":4,"s":"ETHUSDT","c":"4"}`
+	)
+	messages := []string{btc1, eth1, btc2, resp, eth2}
+	testCases := []struct {
+		name     string
+		buffer   StreamBuffer
+		expected []string
+		dropped  int
+	}{
+		{
+			name:     "drop oldest",
+			buffer:   StreamBuffer{Policy: BackpressureDropOldest, Size: 2},
+			expected: []string{btc2, resp, eth2},
+			dropped:  2,
+		},
+		{
+			name:     "drop newest",
+			buffer:   StreamBuffer{Policy: BackpressureDropNewest, Size: 2},
+			expected: []string{btc1, eth1, resp},
+			dropped:  2,
+		},
+		{
+			name:     "conflate",
+			buffer:   StreamBuffer{Policy: BackpressureConflate, Size: 1},
+			expected: []string{btc2, eth2, resp},
+			dropped:  2,
+		},
+		{
+			name:     "block with room for all",
+			buffer:   StreamBuffer{Size: 5},
+			expected: messages,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			q := newStreamQueue(tc.buffer)
+			var dropped int
+			for _, msg := range messages {
+				n, ok := q.push(ctx, q.newMessage([]byte(msg)))
+				if !ok {
+					t.Fatalf("unexpected failure to push %v", msg)
+				}
+				dropped += n
+			}
+			streamErr := errors.New("stream ended")
+			if n, ok := q.push(ctx, queuedMessage{err: streamErr, keep: true}); n != 0 || !ok {
+				t.Errorf("expected errors to be pushed without dropping but got %v, %v", n, ok)
+			}
+			if dropped != tc.dropped {
+				t.Errorf("expected %v dropped but got %v", tc.dropped, dropped)
+			}
+
+			var got []string
+			for len(got) < len(tc.expected) {
+				m, ok := q.pop(ctx)
+				if !ok {
+					t.Fatalf("unexpected failure to pop")
+				}
+				got = append(got, string(m.data))
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected messages.\n%s", diff)
+			}
+			if m, _ := q.pop(ctx); m.err != streamErr {
+				t.Errorf("expected the error to be last but got %#v", m)
+			}
+		})
+	}
+}
+
+func TestStreamQueue_Block(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q := newStreamQueue(StreamBuffer{})
+	if _, ok := q.push(ctx, q.newMessage([]byte("1"))); !ok {
+		t.Fatalf("unexpected failure to push")
+	}
+
+	pushed := make(chan bool)
+	go func() {
+		_, ok := q.push(ctx, q.newMessage([]byte("2")))
+		pushed <- ok
+	}()
+	select {
+	case <-pushed:
+		t.Fatalf("expected push to block while the queue is full")
+	case <-time.After(10 * time.Millisecond):
+	}
+	if m, _ := q.pop(ctx); string(m.data) != "1" {
+		t.Errorf("unexpected message %q", m.data)
+	}
+	if ok := <-pushed; !ok {
+		t.Errorf("expected push to complete once there was space")
+	}
+
+	go func() {
+		_, ok := q.push(ctx, q.newMessage([]byte("3")))
+		pushed <- ok
+	}()
+	cancel()
+	if ok := <-pushed; ok {
+		t.Errorf("expected push to fail once the context was cancelled")
+	}
+}
+
+func TestBackpressurePolicy_Validate(t *testing.T) {
+	t.Parallel()
+	if err := (StreamBuffer{Policy: BackpressureConflate}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (StreamBuffer{Policy: BackpressurePolicy(99)}).Validate(); err == nil {
+		t.Errorf("expected an error for an unknown policy")
+	}
+}
@@ -0,0 +1,319 @@
+package gobinance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// websocketTextMessage is the message type of a UTF-8 text message, as defined in RFC 6455
+	websocketTextMessage = 1
+	// streamMessagesPerSecond is the maximum number of messages binance accepts from a client on a
+	// single websocket connection each second
+	streamMessagesPerSecond = 5
+)
+
+// StreamEvent is an event received on a stream managed by a StreamManager
+type StreamEvent struct {
+	// Stream is the name of the stream that the event was received on, e.g. `btcusdt@trade`.
+	// It is empty when the connection is not in combined mode.
+	Stream string
+	// Data is the JSON payload of the event
+	Data json.RawMessage
+}
+
+// StreamEventOrError is a union of StreamEvent or error
+type StreamEventOrError struct {
+	StreamEvent
+	Err error
+}
+
+// StreamRequestError is returned when binance rejects a request sent over a managed websocket connection
+type StreamRequestError struct {
+	// ID is the identifier of the request that was rejected
+	ID uint64
+	errorDTO
+}
+
+// ErrorCode returns the value of the `code` field in binance's error response.
+func (s *StreamRequestError) ErrorCode() int {
+	return s.Code
+}
+
+// Error implements the error interface and returns a human-readable description of the error
+func (s *StreamRequestError) Error() string {
+	return fmt.Sprintf("binance rejected stream request %v. error code was %v: %v", s.ID, s.Code, s.Msg)
+}
+
+// StreamManager manages a single websocket connection to binance, allowing streams to be subscribed to
+// and unsubscribed from while the connection is open.
+//
+// Events from all subscribed streams are sent on the channel returned by Events.  Responses to requests are read
+// from the connection in order with events, so a response is only processed once every event received before it
+// has been sent on the channel.  Subscribe, Unsubscribe, ListSubscriptions and SetProperty therefore block until
+// their context is done if Events is not being consumed, so must not be called from the goroutine consuming
+// Events once subscribed to a stream.
+type StreamManager struct {
+	// nextID is the identifier of the most recent request.  It is accessed atomically and so
+	// must remain the first field in the struct to guarantee alignment.
+	nextID uint64
+
+	con      NextReaderWriterCloser
+	observer StreamObserver
+	buffer   StreamBuffer
+	events   chan StreamEventOrError
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	pendingMu sync.Mutex
+	pending   map[uint64]chan streamMessage
+	closedErr error
+
+	writeMu   sync.Mutex
+	lastWrite time.Time
+}
+
+type streamRequest struct {
+	Method string        `json:"method"`
+	Params []interface{} `json:"params,omitempty"`
+	ID     uint64        `json:"id"`
+}
+
+// streamMessage holds either a response to a streamRequest or an event from a combined stream
+type streamMessage struct {
+	ID     *uint64         `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *errorDTO       `json:"error"`
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// NewStreamManager opens a websocket connection to binance's combined stream endpoint with no subscriptions.
+// Streams may then be added and removed using the returned StreamManager.
+//
+// The connection is closed when the underlying context is cancelled, when Close is called, or upon a
+// connection error or the server closing the connection.
+func (c *Client) NewStreamManager(ctx context.Context) (*StreamManager, error) {
+	buffer := c.streamBuffer(ctx)
+	if err := buffer.Validate(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	con, err := c.dialWebsocket(ctx, streamManagerStreamName, "/stream")
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	rwc, ok := con.(NextReaderWriterCloser)
+	if !ok {
+		_ = con.Close()
+		cancel()
+		return nil, fmt.Errorf("websocket connection does not support writing messages")
+	}
+
+	m := &StreamManager{
+		con:      rwc,
+		observer: c.streamObserver(),
+		buffer:   buffer,
+		events:   make(chan StreamEventOrError, 1),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		pending:  make(map[uint64]chan streamMessage),
+	}
+	go m.run(ctx)
+	return m, nil
+}
+
+// Events returns a channel on which events from all subscribed streams are sent.  The channel is closed
+// once the connection has been closed.  Requests cannot complete while it is not being consumed.
+func (m *StreamManager) Events() <-chan StreamEventOrError {
+	return m.events
+}
+
+// Subscribe subscribes to the named streams, e.g. `btcusdt@trade`.
+func (m *StreamManager) Subscribe(ctx context.Context, streams ...string) error {
+	_, err := m.request(ctx, "SUBSCRIBE", stringsToParams(streams))
+	return err
+}
+
+// Unsubscribe unsubscribes from the named streams
+func (m *StreamManager) Unsubscribe(ctx context.Context, streams ...string) error {
+	_, err := m.request(ctx, "UNSUBSCRIBE", stringsToParams(streams))
+	return err
+}
+
+// ListSubscriptions returns the names of the streams currently subscribed to
+func (m *StreamManager) ListSubscriptions(ctx context.Context) ([]string, error) {
+	result, err := m.request(ctx, "LIST_SUBSCRIPTIONS", nil)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	if err := json.Unmarshal(result, &out); err != nil {
+		return nil, fmt.Errorf("error decoding subscriptions: %w", err)
+	}
+	return out, nil
+}
+
+// SetProperty sets a property of the connection, such as `combined`.
+//
+// Note that when `combined` is set to false, the Stream field of events is no longer populated.
+func (m *StreamManager) SetProperty(ctx context.Context, property string, value interface{}) error {
+	_, err := m.request(ctx, "SET_PROPERTY", []interface{}{property, value})
+	return err
+}
+
+// Close closes the connection and waits for the Events channel to be closed
+func (m *StreamManager) Close() {
+	m.cancel()
+	<-m.done
+}
+
+func (m *StreamManager) run(ctx context.Context) {
+	defer close(m.done)
+	defer close(m.events)
+	defer m.closePending()
+	defer m.con.Close()
+	defer m.cancel()
+
+	readWebsocket(ctx, m.con, streamManagerStreamName, m.observer, m.buffer, func(reader io.Reader, err error) error {
+		if err != nil {
+			m.emit(ctx, StreamEventOrError{Err: err})
+			return nil
+		}
+		return m.handle(ctx, reader)
+	})
+}
+
+// handle passes a message read from the connection to the pending request or consumer, returning an error if it
+// could not be decoded
+func (m *StreamManager) handle(ctx context.Context, reader io.Reader) error {
+	bs, err := ioutil.ReadAll(reader)
+	if err != nil {
+		m.emit(ctx, StreamEventOrError{Err: fmt.Errorf("error reading stream message: %w", err)})
+		return nil
+	}
+	var msg streamMessage
+	if err := json.Unmarshal(bs, &msg); err != nil {
+		m.emit(ctx, StreamEventOrError{Err: fmt.Errorf("error decoding stream message: %w", err)})
+		return err
+	}
+
+	if msg.ID != nil {
+		m.pendingMu.Lock()
+		respCh, ok := m.pending[*msg.ID]
+		m.pendingMu.Unlock()
+		if ok {
+			// the channel is buffered for the single response expected, so any duplicate response is discarded
+			select {
+			case respCh <- msg:
+			default:
+			}
+		}
+		return nil
+	}
+
+	event := StreamEvent{
+		Stream: msg.Stream,
+		Data:   msg.Data,
+	}
+	if msg.Stream == "" && msg.Data == nil {
+		// not in combined mode, so the message is the event itself
+		event.Data = bs
+	}
+	m.emit(ctx, StreamEventOrError{StreamEvent: event})
+	return nil
+}
+
+func (m *StreamManager) emit(ctx context.Context, e StreamEventOrError) {
+	select {
+	case m.events <- e:
+	case <-ctx.Done():
+	}
+}
+
+// closePending causes all outstanding and future requests to fail
+func (m *StreamManager) closePending() {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	m.closedErr = fmt.Errorf("stream connection is closed")
+	for id, respCh := range m.pending {
+		close(respCh)
+		delete(m.pending, id)
+	}
+}
+
+// request sends a request to binance and waits for the corresponding response, returning its result
+func (m *StreamManager) request(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	id := atomic.AddUint64(&m.nextID, 1)
+	respCh := make(chan streamMessage, 1)
+
+	m.pendingMu.Lock()
+	if m.closedErr != nil {
+		m.pendingMu.Unlock()
+		return nil, m.closedErr
+	}
+	m.pending[id] = respCh
+	m.pendingMu.Unlock()
+	defer func() {
+		m.pendingMu.Lock()
+		delete(m.pending, id)
+		m.pendingMu.Unlock()
+	}()
+
+	if err := m.send(ctx, streamRequest{Method: method, Params: params, ID: id}); err != nil {
+		return nil, fmt.Errorf("error sending %v request: %w", method, err)
+	}
+
+	select {
+	case resp, ok := <-respCh:
+		if !ok {
+			m.pendingMu.Lock()
+			defer m.pendingMu.Unlock()
+			return nil, m.closedErr
+		}
+		if resp.Error != nil {
+			return nil, &StreamRequestError{ID: id, errorDTO: *resp.Error}
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// send writes a request to the connection, waiting as necessary to respect binance's limit on the
+// number of messages sent per second.
+func (m *StreamManager) send(ctx context.Context, req streamRequest) error {
+	bs, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("error encoding request: %w", err)
+	}
+
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+	if wait := time.Until(m.lastWrite.Add(time.Second / streamMessagesPerSecond)); wait > 0 {
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+	m.lastWrite = time.Now()
+	return m.con.WriteMessage(websocketTextMessage, bs)
+}
+
+func stringsToParams(strs []string) []interface{} {
+	out := make([]interface{}, len(strs))
+	for i, s := range strs {
+		out[i] = s
+	}
+	return out
+}
@@ -0,0 +1,233 @@
+package gobinance_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStreamConnection wires a MockNextReaderWriterCloser up such that each message written to it
+// is passed to `respond`, and any messages returned by `respond` are subsequently read from it.
+type fakeStreamConnection struct {
+	*mock_gobinance.MockNextReaderWriterCloser
+	incoming  chan string
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newFakeStreamConnection(ctrl *gomock.Controller, respond func(method string, params []interface{}, id uint64) []string) *fakeStreamConnection {
+	f := &fakeStreamConnection{
+		MockNextReaderWriterCloser: mock_gobinance.NewMockNextReaderWriterCloser(ctrl),
+		incoming:                   make(chan string, 10),
+		closed:                     make(chan struct{}),
+	}
+	f.EXPECT().NextReader().DoAndReturn(func() (int, io.Reader, error) {
+		select {
+		case msg := <-f.incoming:
+			return 1, strings.NewReader(msg), nil
+		case <-f.closed:
+			return 0, nil, io.EOF
+		}
+	}).AnyTimes()
+	f.EXPECT().WriteMessage(1, gomock.Any()).DoAndReturn(func(_ int, bs []byte) error {
+		var req struct {
+			Method string
+			Params []interface{}
+			ID     uint64
+		}
+		if err := json.Unmarshal(bs, &req); err != nil {
+			return err
+		}
+		for _, msg := range respond(req.Method, req.Params, req.ID) {
+			f.incoming <- msg
+		}
+		return nil
+	}).AnyTimes()
+	f.EXPECT().Close().DoAndReturn(func() error {
+		f.closeOnce.Do(func() { close(f.closed) })
+		return nil
+	}).AnyTimes()
+	return f
+}
+
+func TestClient_NewStreamManager(t *testing.T) {
+	t.Parallel()
+	const BaseURL = "wss://example.com"
+	testError := fmt.Errorf("test error")
+
+	testCases := []struct {
+		name    string
+		respond func(method string, params []interface{}, id uint64) []string
+		dialErr error
+		test    func(t *testing.T, uut *gobinance.StreamManager, err error)
+	}{
+		{
+			name:    "dial error",
+			dialErr: testError,
+			test: func(t *testing.T, uut *gobinance.StreamManager, err error) {
+				if !errors.Is(err, testError) {
+					t.Errorf("expected error to extend from\n\t%#v\nbut got\n\t%#v", testError, err)
+				}
+			},
+		},
+		{
+			name: "subscribe and receive events",
+			respond: func(method string, params []interface{}, id uint64) []string {
+				switch method {
+				case "SUBSCRIBE":
+					return []string{
+						fmt.Sprintf(`{"result":null,"id":%v}`, id),
+						fmt.Sprintf(`{"stream":"%v","data":{"e":"trade","s":"BTCUSDT"}}`, params[0]),
+					}
+				case "LIST_SUBSCRIPTIONS":
+					return []string{fmt.Sprintf(`{"result":["btcusdt@trade"],"id":%v}`, id)}
+				}
+				return []string{fmt.Sprintf(`{"result":null,"id":%v}`, id)}
+			},
+			test: func(t *testing.T, uut *gobinance.StreamManager, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				ctx := context.Background()
+				if err := uut.Subscribe(ctx, "btcusdt@trade"); err != nil {
+					t.Errorf("unexpected error subscribing: %v", err)
+				}
+				event := <-uut.Events()
+				expected := gobinance.StreamEventOrError{
+					StreamEvent: gobinance.StreamEvent{
+						Stream: "btcusdt@trade",
+						Data:   json.RawMessage(`{"e":"trade","s":"BTCUSDT"}`),
+					},
+				}
+				if diff := cmp.Diff(expected, event); diff != "" {
+					t.Errorf("unexpected event.\n%s", diff)
+				}
+				subs, err := uut.ListSubscriptions(ctx)
+				if err != nil {
+					t.Errorf("unexpected error listing subscriptions: %v", err)
+				}
+				if diff := cmp.Diff([]string{"btcusdt@trade"}, subs); diff != "" {
+					t.Errorf("unexpected subscriptions.\n%s", diff)
+				}
+				if err := uut.Unsubscribe(ctx, "btcusdt@trade"); err != nil {
+					t.Errorf("unexpected error unsubscribing: %v", err)
+				}
+			},
+		},
+		{
+			name: "request rejected",
+			respond: func(method string, params []interface{}, id uint64) []string {
+				return []string{fmt.Sprintf(`{"error":{"code":2,"msg":"Invalid request"},"id":%v}`, id)}
+			},
+			test: func(t *testing.T, uut *gobinance.StreamManager, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				err = uut.SetProperty(context.Background(), "combined", "bad")
+				var reqErr *gobinance.StreamRequestError
+				if !errors.As(err, &reqErr) {
+					t.Fatalf("expected a StreamRequestError but got %#v", err)
+				}
+				if code := reqErr.ErrorCode(); code != 2 {
+					t.Errorf("unexpected error code.  expected %v but got %v", 2, code)
+				}
+			},
+		},
+		{
+			name: "duplicate responses are ignored",
+			respond: func(method string, params []interface{}, id uint64) []string {
+				response := fmt.Sprintf(`{"result":null,"id":%v}`, id)
+				return []string{response, response}
+			},
+			test: func(t *testing.T, uut *gobinance.StreamManager, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				for i := 0; i < 2; i++ {
+					if err := uut.Subscribe(ctx, fmt.Sprintf("symbol%v@trade", i)); err != nil {
+						t.Errorf("unexpected error subscribing: %v", err)
+					}
+				}
+			},
+		},
+		{
+			name: "requests are rate limited",
+			respond: func(method string, params []interface{}, id uint64) []string {
+				return []string{fmt.Sprintf(`{"result":null,"id":%v}`, id)}
+			},
+			test: func(t *testing.T, uut *gobinance.StreamManager, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				start := time.Now()
+				for i := 0; i < 6; i++ {
+					if err := uut.Subscribe(context.Background(), fmt.Sprintf("symbol%v@trade", i)); err != nil {
+						t.Errorf("unexpected error subscribing: %v", err)
+					}
+				}
+				if elapsed := time.Since(start); elapsed < time.Second {
+					t.Errorf("expected 6 requests to take at least 1s but took %v", elapsed)
+				}
+			},
+		},
+		{
+			name: "requests fail after close",
+			respond: func(method string, params []interface{}, id uint64) []string {
+				return nil
+			},
+			test: func(t *testing.T, uut *gobinance.StreamManager, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				uut.Close()
+				if _, ok := <-uut.Events(); ok {
+					t.Errorf("expected events channel to be closed")
+				}
+				if err := uut.Subscribe(context.Background(), "btcusdt@trade"); err == nil {
+					t.Errorf("expected an error but got nil")
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDialer := mock_gobinance.NewMockDialContexter(ctrl)
+			if tc.dialErr != nil {
+				mockDialer.EXPECT().DialContext(gomock.Any(), BaseURL+"/stream", nil).Return(nil, nil, tc.dialErr)
+			} else {
+				con := newFakeStreamConnection(ctrl, tc.respond)
+				mockDialer.EXPECT().DialContext(gomock.Any(), BaseURL+"/stream", nil).Return(con, nil, nil)
+			}
+
+			baseURL, _ := url.Parse(BaseURL)
+			client := &gobinance.Client{
+				WebsocketApiURL: baseURL,
+				DialContexter:   mockDialer,
+			}
+			uut, err := client.NewStreamManager(context.Background())
+			if uut != nil {
+				defer uut.Close()
+			}
+			tc.test(t, uut, err)
+		})
+	}
+}
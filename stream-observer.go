@@ -0,0 +1,235 @@
+package gobinance
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -destination=mocks/mock_stream-observer.go . StreamObserver
+
+const (
+	// userDataStreamName is the name given to the user data stream when observed, in place of its listen key
+	userDataStreamName = "userData"
+	// streamManagerStreamName is the name given to connections made by a StreamManager when observed
+	streamManagerStreamName = "stream"
+)
+
+// StreamObserver receives events describing the lifecycle and throughput of the websocket streams opened by a
+// Client, so that they can be exported to a metrics system or used to alert when a stream stalls.  Streams are
+// named as binance names them, such as btcusdt@trade, except for the user data stream, which is named userData so
+// that its listen key is not exposed, and the connections of a StreamManager, which are named stream.
+//
+// Methods are called from the goroutines reading the streams, so must be safe for concurrent use and should not
+// block.
+type StreamObserver interface {
+	// Dialled is called after each attempt to connect with the time taken, and the error if the attempt failed
+	Dialled(stream string, d time.Duration, err error)
+	// Connected is called once a connection has been established
+	Connected(stream string)
+	// Disconnected is called once an established connection has closed.  reason is the error read from the
+	// connection, or the context's error when the stream was closed by its caller.
+	Disconnected(stream string, reason error)
+	// MessageReceived is called with the size in bytes of each message as soon as it is read from the connection,
+	// before it is decoded or passed to the consumer
+	MessageReceived(stream string, size int)
+	// Dropped is called with the number of messages dropped according to the stream's StreamBuffer when its
+	// consumer falls behind
+	Dropped(stream string, n int)
+	// DecodeError is called for each message which could not be decoded
+	DecodeError(stream string, err error)
+	// Delivered is called with the time taken to pass each message to the consumer.  Once the consumer falls
+	// behind, this is dominated by the time spent blocked sending to the stream's channel.
+	Delivered(stream string, d time.Duration)
+}
+
+// ClientStreamObserver sets the StreamObserver notified of the activity of the client's websocket streams
+func ClientStreamObserver(observer StreamObserver) ClientOption {
+	return func(c *Client) {
+		c.StreamObserver = observer
+	}
+}
+
+// streamObserver returns the client's StreamObserver, or one which ignores all events if it is not set
+func (c *Client) streamObserver() StreamObserver {
+	if c.StreamObserver == nil {
+		return noopStreamObserver{}
+	}
+	return c.StreamObserver
+}
+
+type noopStreamObserver struct{}
+
+func (noopStreamObserver) Dialled(string, time.Duration, error) {}
+func (noopStreamObserver) Connected(string)                     {}
+func (noopStreamObserver) Disconnected(string, error)           {}
+func (noopStreamObserver) MessageReceived(string, int)          {}
+func (noopStreamObserver) Dropped(string, int)                  {}
+func (noopStreamObserver) DecodeError(string, error)            {}
+func (noopStreamObserver) Delivered(string, time.Duration)      {}
+
+// StreamStats summarises the activity of a stream observed by a StreamMonitor
+type StreamStats struct {
+	// Connected is true while the stream has an established connection
+	Connected bool
+	// ConnectedAt is the time the most recent connection was established
+	ConnectedAt time.Time
+	Connects    int64
+	// DialErrors is the number of attempts to connect which failed
+	DialErrors int64
+	// LastDialDuration is the time taken by the most recent attempt to connect
+	LastDialDuration time.Duration
+	Disconnects      int64
+	// LastDisconnectReason is the reason given for the most recent disconnection
+	LastDisconnectReason error
+	Messages             int64
+	Bytes                int64
+	// Dropped is the number of messages dropped because the consumer fell behind
+	Dropped      int64
+	DecodeErrors int64
+	// LastMessageAt is the time the most recent message was received, or zero if none has been
+	LastMessageAt time.Time
+	// Blocked is the total time taken to pass messages to the consumer
+	Blocked time.Duration
+}
+
+// StreamMonitor is a StreamObserver which keeps StreamStats for each stream, so that the age of the last message
+// received can be checked to detect streams which have stalled.  Message rates may be found by sampling the
+// Messages count of each stream.  The zero value is ready to use.
+type StreamMonitor struct {
+	// Now returns the current time.  When nil, time.Now is used.
+	Now func() time.Time
+
+	mu      sync.Mutex
+	streams map[string]*StreamStats
+}
+
+func (m *StreamMonitor) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
+
+// update calls fn with the stats of the stream while holding the lock
+func (m *StreamMonitor) update(stream string, fn func(s *StreamStats)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.streams == nil {
+		m.streams = make(map[string]*StreamStats)
+	}
+	s, ok := m.streams[stream]
+	if !ok {
+		s = &StreamStats{}
+		m.streams[stream] = s
+	}
+	fn(s)
+}
+
+// Dialled records an attempt to connect
+func (m *StreamMonitor) Dialled(stream string, d time.Duration, err error) {
+	m.update(stream, func(s *StreamStats) {
+		s.LastDialDuration = d
+		if err != nil {
+			s.DialErrors++
+		}
+	})
+}
+
+// Connected records the stream as connected
+func (m *StreamMonitor) Connected(stream string) {
+	now := m.now()
+	m.update(stream, func(s *StreamStats) {
+		s.Connected = true
+		s.ConnectedAt = now
+		s.Connects++
+	})
+}
+
+// Disconnected records the stream as disconnected
+func (m *StreamMonitor) Disconnected(stream string, reason error) {
+	m.update(stream, func(s *StreamStats) {
+		s.Connected = false
+		s.Disconnects++
+		s.LastDisconnectReason = reason
+	})
+}
+
+// MessageReceived records the receipt of a message
+func (m *StreamMonitor) MessageReceived(stream string, size int) {
+	now := m.now()
+	m.update(stream, func(s *StreamStats) {
+		s.Messages++
+		s.Bytes += int64(size)
+		s.LastMessageAt = now
+	})
+}
+
+// Dropped records messages dropped because the consumer fell behind
+func (m *StreamMonitor) Dropped(stream string, n int) {
+	m.update(stream, func(s *StreamStats) {
+		s.Dropped += int64(n)
+	})
+}
+
+// DecodeError records a message which could not be decoded
+func (m *StreamMonitor) DecodeError(stream string, err error) {
+	m.update(stream, func(s *StreamStats) {
+		s.DecodeErrors++
+	})
+}
+
+// Delivered records the time taken to pass a message to the consumer
+func (m *StreamMonitor) Delivered(stream string, d time.Duration) {
+	m.update(stream, func(s *StreamStats) {
+		s.Blocked += d
+	})
+}
+
+// Stats returns the stats of each stream observed
+func (m *StreamMonitor) Stats() map[string]StreamStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]StreamStats, len(m.streams))
+	for name, s := range m.streams {
+		out[name] = *s
+	}
+	return out
+}
+
+// LastMessageAge returns the time since the stream last received a message, or since it connected if it has not
+// received one since.  It returns false if the stream is not connected.
+func (m *StreamMonitor) LastMessageAge(stream string) (time.Duration, bool) {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.streams[stream]
+	if !ok || !s.Connected {
+		return 0, false
+	}
+	return now.Sub(lastActivity(s)), true
+}
+
+// Stalled returns the sorted names of the connected streams which have not received a message for longer than
+// maxAge
+func (m *StreamMonitor) Stalled(maxAge time.Duration) []string {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []string
+	for name, s := range m.streams {
+		if s.Connected && now.Sub(lastActivity(s)) > maxAge {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// lastActivity returns the time the stream last received a message, or the time it connected if later
+func lastActivity(s *StreamStats) time.Time {
+	if s.LastMessageAt.After(s.ConnectedAt) {
+		return s.LastMessageAt
+	}
+	return s.ConnectedAt
+}
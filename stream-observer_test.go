@@ -0,0 +1,149 @@
+package gobinance_test
+
+import (
+	"context"
+	"errors"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestClient_StreamObserver(t *testing.T) {
+	t.Parallel()
+	const trade = `{"e":"trade","E":1604705434642,"s":"BTCUSDT","t":455634704,"p":"15617.99000000","q":"0.00720000","b":3530255770,"a":3530255647,"T":1604705434637,"m":false,"M":true}`
+	dialErr := errors.New("connection refused")
+	testCases := []struct {
+		name         string
+		dialer       func(ctrl *gomock.Controller) gobinance.DialContexter
+		expectations func(observer *mock_gobinance.MockStreamObserver)
+		events       int
+	}{
+		{
+			name: "messages are observed",
+			dialer: func(ctrl *gomock.Controller) gobinance.DialContexter {
+				return mockWebsocketMessages(ctrl, testWebsocketBaseURL+"/ws/btcusdt@trade", trade, `not json`)
+			},
+			expectations: func(observer *mock_gobinance.MockStreamObserver) {
+				observer.EXPECT().Dialled("btcusdt@trade", gomock.Any(), nil)
+				observer.EXPECT().Connected("btcusdt@trade")
+				observer.EXPECT().MessageReceived("btcusdt@trade", len(trade))
+				observer.EXPECT().MessageReceived("btcusdt@trade", len(`not json`))
+				observer.EXPECT().DecodeError("btcusdt@trade", gomock.Not(gomock.Nil()))
+				observer.EXPECT().Delivered("btcusdt@trade", gomock.Any()).Times(2)
+				observer.EXPECT().Disconnected("btcusdt@trade", errStreamEnded)
+			},
+			events: 3,
+		},
+		{
+			name: "dial errors are observed",
+			dialer: func(ctrl *gomock.Controller) gobinance.DialContexter {
+				dialer := mock_gobinance.NewMockDialContexter(ctrl)
+				dialer.EXPECT().DialContext(gomock.Any(), gomock.Any(), nil).Return(nil, nil, dialErr)
+				return dialer
+			},
+			expectations: func(observer *mock_gobinance.MockStreamObserver) {
+				observer.EXPECT().Dialled("btcusdt@trade", gomock.Any(), dialErr)
+			},
+			events: 1,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			observer := mock_gobinance.NewMockStreamObserver(ctrl)
+			tc.expectations(observer)
+			baseURL, _ := url.Parse(testWebsocketBaseURL)
+			client := &gobinance.Client{
+				WebsocketApiURL: baseURL,
+				DialContexter:   tc.dialer(ctrl),
+				StreamObserver:  observer,
+			}
+
+			var events int
+			for range client.Trades(context.Background(), "BTCUSDT") {
+				events++
+			}
+			if events != tc.events {
+				t.Errorf("expected %v events but got %v", tc.events, events)
+			}
+		})
+	}
+}
+
+func TestStreamMonitor(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	uut := &gobinance.StreamMonitor{Now: func() time.Time { return now }}
+	reason := errors.New("connection reset")
+
+	uut.Dialled("btcusdt@trade", 30*time.Millisecond, nil)
+	uut.Connected("btcusdt@trade")
+	uut.Dialled("ethusdt@trade", 20*time.Millisecond, nil)
+	uut.Connected("ethusdt@trade")
+	now = now.Add(time.Second)
+	uut.MessageReceived("btcusdt@trade", 100)
+	uut.Delivered("btcusdt@trade", 5*time.Millisecond)
+	now = now.Add(time.Second)
+	uut.MessageReceived("btcusdt@trade", 120)
+	uut.DecodeError("btcusdt@trade", errors.New("invalid character"))
+	uut.Delivered("btcusdt@trade", 7*time.Millisecond)
+	uut.Dialled("bnbusdt@trade", 40*time.Millisecond, reason)
+	now = now.Add(3 * time.Second)
+
+	if age, ok := uut.LastMessageAge("btcusdt@trade"); !ok || age != 3*time.Second {
+		t.Errorf("expected a last message age of 3s but got %v, %v", age, ok)
+	}
+	if age, ok := uut.LastMessageAge("ethusdt@trade"); !ok || age != 5*time.Second {
+		t.Errorf("expected the age since connecting of 5s but got %v, %v", age, ok)
+	}
+	if age, ok := uut.LastMessageAge("bnbusdt@trade"); ok {
+		t.Errorf("expected no age for a stream which is not connected but got %v", age)
+	}
+	if diff := cmp.Diff([]string{"btcusdt@trade", "ethusdt@trade"}, uut.Stalled(2*time.Second)); diff != "" {
+		t.Errorf("unexpected stalled streams.\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"ethusdt@trade"}, uut.Stalled(4*time.Second)); diff != "" {
+		t.Errorf("unexpected stalled streams.\n%s", diff)
+	}
+
+	uut.Disconnected("ethusdt@trade", reason)
+	if stalled := uut.Stalled(0); len(stalled) != 1 || stalled[0] != "btcusdt@trade" {
+		t.Errorf("expected disconnected streams not to be stalled but got %v", stalled)
+	}
+
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	expected := map[string]gobinance.StreamStats{
+		"btcusdt@trade": {
+			Connected:        true,
+			ConnectedAt:      start,
+			Connects:         1,
+			LastDialDuration: 30 * time.Millisecond,
+			Messages:         2,
+			Bytes:            220,
+			DecodeErrors:     1,
+			LastMessageAt:    start.Add(2 * time.Second),
+			Blocked:          12 * time.Millisecond,
+		},
+		"ethusdt@trade": {
+			ConnectedAt:          start,
+			Connects:             1,
+			LastDialDuration:     20 * time.Millisecond,
+			Disconnects:          1,
+			LastDisconnectReason: reason,
+		},
+		"bnbusdt@trade": {
+			DialErrors:       1,
+			LastDialDuration: 40 * time.Millisecond,
+		},
+	}
+	if diff := cmp.Diff(expected, uut.Stats(), cmp.Comparer(func(a, b error) bool { return a == b })); diff != "" {
+		t.Errorf("unexpected stats.\n%s", diff)
+	}
+}
@@ -1,9 +1,18 @@
 package gobinance_test
 
 import (
+	"bytes"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
 	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
+	"io/ioutil"
 	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -16,13 +25,18 @@ const (
 	testBinanceApiKey = "test-binance-api-key"
 	testUserAgent     = "test-user-agent"
 	testBaseURL       = "https://example.com"
-	testRecvWindow    = 3 * time.Second
-	mockSignature     = "mock-signature"
+	// testWebsocketBaseURL is the WebsocketApiURL of clients created by newTestStreamClient
+	testWebsocketBaseURL = "wss://example.com"
+	testRecvWindow       = 3 * time.Second
+	mockSignature        = "mock-signature"
 )
 
 // bigFloatComparer is used by google compare's function in order to allow
 // checking for equality of big Floats
 var bigFloatComparer = cmp.Comparer(func(a, b *big.Float) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
 	return a.Cmp(b) == 0
 })
 
@@ -93,3 +107,85 @@ func isHttpError(expectedStatus int, expectedErrCode int) func(*testing.T, error
 		return false
 	}
 }
+
+// errStreamEnded is returned by connections set up using mockWebsocketMessages once all messages have been read
+var errStreamEnded = fmt.Errorf("stream ended")
+
+// mockWebsocketMessages returns a mock DialContexter which expects a single connection to `expectedURL`.  The
+// connection returns each of `messages` in turn, followed by errStreamEnded.
+func mockWebsocketMessages(ctrl *gomock.Controller, expectedURL string, messages ...string) *mock_gobinance.MockDialContexter {
+	mockDialer := mock_gobinance.NewMockDialContexter(ctrl)
+	mockNextReader := mock_gobinance.NewMockNextReaderCloser(ctrl)
+	mockDialer.EXPECT().DialContext(gomock.Not(gomock.Nil()), expectedURL, nil).Return(mockNextReader, nil, nil)
+	mockNextReader.EXPECT().Close()
+
+	var prev *gomock.Call
+	for _, msg := range messages {
+		call := mockNextReader.EXPECT().NextReader().Return(0, bytes.NewBufferString(msg), nil)
+		if prev != nil {
+			call.After(prev)
+		}
+		prev = call
+	}
+	last := mockNextReader.EXPECT().NextReader().Return(0, nil, errStreamEnded)
+	if prev != nil {
+		last.After(prev)
+	}
+	return mockDialer
+}
+
+// newTestStreamClient returns a client whose DialContexter is set up using mockWebsocketMessages
+func newTestStreamClient(t *testing.T, expectedURL string, messages ...string) *gobinance.Client {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	baseURL, _ := url.Parse(testWebsocketBaseURL)
+	return &gobinance.Client{
+		WebsocketApiURL: baseURL,
+		DialContexter:   mockWebsocketMessages(ctrl, expectedURL, messages...),
+	}
+}
+
+// mockRoutedDoer returns a mock Doer which responds to requests according to their path.  Each request to a path
+// in `routes` returns the next of its bodies, repeating the last once exhausted.  Requests to manage the user data
+// stream's listen key succeed with the key "test-listen-key".
+func mockRoutedDoer(t *testing.T, ctrl *gomock.Controller, routes map[string][]string) *mock_gobinance.MockDoer {
+	var mu sync.Mutex
+	mockDoer := mock_gobinance.NewMockDoer(ctrl)
+	mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body string
+		if req.URL.Path == "/api/v3/userDataStream" {
+			body = `{"listenKey":"test-listen-key"}`
+		} else if bodies, ok := routes[req.URL.Path]; ok {
+			body = bodies[0]
+			if len(bodies) > 1 {
+				routes[req.URL.Path] = bodies[1:]
+			}
+		} else {
+			t.Errorf("unexpected request to %v", req.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	}).AnyTimes()
+	return mockDoer
+}
+
+// newTestUserDataClient returns a client set up to make signed requests using `doer`, and to stream using `dialer`
+func newTestUserDataClient(ctrl *gomock.Controller, doer gobinance.Doer, dialer gobinance.DialContexter) *gobinance.Client {
+	mockSigner := mock_gobinance.NewMockSigner(ctrl)
+	mockSigner.EXPECT().Sign(gomock.Any()).Return(mockSignature).AnyTimes()
+	httpURL, _ := url.Parse(testBaseURL)
+	wsURL, _ := url.Parse(testWebsocketBaseURL)
+	return &gobinance.Client{
+		HTTPApiURL:      httpURL,
+		WebsocketApiURL: wsURL,
+		APIKey:          testBinanceApiKey,
+		Signer:          mockSigner,
+		Doer:            doer,
+		DialContexter:   dialer,
+		Now:             mockNow,
+	}
+}
@@ -0,0 +1,372 @@
+package gobinance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TickerEvent define websocket ticker event, holding statistics about a symbol over a window of time.
+//
+// For rolling window tickers, LastQuantity and the best bid and ask fields are not populated.
+type TickerEvent struct {
+	Event              string
+	Time               time.Time
+	Symbol             string
+	PriceChange        *big.Float
+	PriceChangePercent *big.Float
+	WeightedAvgPrice   *big.Float
+	FirstTradePrice    *big.Float
+	LastPrice          *big.Float
+	LastQuantity       *big.Float
+	BestBidPrice       *big.Float
+	BestBidQuantity    *big.Float
+	BestAskPrice       *big.Float
+	BestAskQuantity    *big.Float
+	OpenPrice          *big.Float
+	HighPrice          *big.Float
+	LowPrice           *big.Float
+	BaseVolume         *big.Float
+	QuoteVolume        *big.Float
+	OpenTime           time.Time
+	CloseTime          time.Time
+	FirstTradeID       int64
+	LastTradeID        int64
+	NumberOfTrades     int64
+}
+
+// UnmarshalJSON provides custom unmarshalling for TickerEvents.
+func (t *TickerEvent) UnmarshalJSON(bs []byte) error {
+	var tmp struct {
+		Event              string          `json:"e"`
+		Time               millisTimestamp `json:"E"`
+		Symbol             string          `json:"s"`
+		PriceChange        *big.Float      `json:"p"`
+		PriceChangePercent *big.Float      `json:"P"`
+		WeightedAvgPrice   *big.Float      `json:"w"`
+		FirstTradePrice    *big.Float      `json:"x"`
+		LastPrice          *big.Float      `json:"c"`
+		LastQuantity       *big.Float      `json:"Q"`
+		BestBidPrice       *big.Float      `json:"b"`
+		BestBidQuantity    *big.Float      `json:"B"`
+		BestAskPrice       *big.Float      `json:"a"`
+		BestAskQuantity    *big.Float      `json:"A"`
+		OpenPrice          *big.Float      `json:"o"`
+		HighPrice          *big.Float      `json:"h"`
+		LowPrice           *big.Float      `json:"l"`
+		BaseVolume         *big.Float      `json:"v"`
+		QuoteVolume        *big.Float      `json:"q"`
+		OpenTime           millisTimestamp `json:"O"`
+		CloseTime          millisTimestamp `json:"C"`
+		FirstTradeID       int64           `json:"F"`
+		LastTradeID        int64           `json:"L"`
+		NumberOfTrades     int64           `json:"n"`
+	}
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	*t = TickerEvent{
+		Event:              tmp.Event,
+		Time:               time.Time(tmp.Time),
+		Symbol:             tmp.Symbol,
+		PriceChange:        tmp.PriceChange,
+		PriceChangePercent: tmp.PriceChangePercent,
+		WeightedAvgPrice:   tmp.WeightedAvgPrice,
+		FirstTradePrice:    tmp.FirstTradePrice,
+		LastPrice:          tmp.LastPrice,
+		LastQuantity:       tmp.LastQuantity,
+		BestBidPrice:       tmp.BestBidPrice,
+		BestBidQuantity:    tmp.BestBidQuantity,
+		BestAskPrice:       tmp.BestAskPrice,
+		BestAskQuantity:    tmp.BestAskQuantity,
+		OpenPrice:          tmp.OpenPrice,
+		HighPrice:          tmp.HighPrice,
+		LowPrice:           tmp.LowPrice,
+		BaseVolume:         tmp.BaseVolume,
+		QuoteVolume:        tmp.QuoteVolume,
+		OpenTime:           time.Time(tmp.OpenTime),
+		CloseTime:          time.Time(tmp.CloseTime),
+		FirstTradeID:       tmp.FirstTradeID,
+		LastTradeID:        tmp.LastTradeID,
+		NumberOfTrades:     tmp.NumberOfTrades,
+	}
+	return nil
+}
+
+// TickerEventOrError is a union of TickerEvent or error
+type TickerEventOrError struct {
+	TickerEvent
+	Err error
+}
+
+// TickerEventsOrError is a union of a list of TickerEvents or error
+type TickerEventsOrError struct {
+	Tickers []TickerEvent
+	Err     error
+}
+
+// MiniTickerEvent define websocket mini ticker event
+type MiniTickerEvent struct {
+	Event       string
+	Time        time.Time
+	Symbol      string
+	ClosePrice  *big.Float
+	OpenPrice   *big.Float
+	HighPrice   *big.Float
+	LowPrice    *big.Float
+	BaseVolume  *big.Float
+	QuoteVolume *big.Float
+}
+
+// UnmarshalJSON provides custom unmarshalling for MiniTickerEvents.
+func (m *MiniTickerEvent) UnmarshalJSON(bs []byte) error {
+	var tmp struct {
+		Event       string          `json:"e"`
+		Time        millisTimestamp `json:"E"`
+		Symbol      string          `json:"s"`
+		ClosePrice  *big.Float      `json:"c"`
+		OpenPrice   *big.Float      `json:"o"`
+		HighPrice   *big.Float      `json:"h"`
+		LowPrice    *big.Float      `json:"l"`
+		BaseVolume  *big.Float      `json:"v"`
+		QuoteVolume *big.Float      `json:"q"`
+	}
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	*m = MiniTickerEvent{
+		Event:       tmp.Event,
+		Time:        time.Time(tmp.Time),
+		Symbol:      tmp.Symbol,
+		ClosePrice:  tmp.ClosePrice,
+		OpenPrice:   tmp.OpenPrice,
+		HighPrice:   tmp.HighPrice,
+		LowPrice:    tmp.LowPrice,
+		BaseVolume:  tmp.BaseVolume,
+		QuoteVolume: tmp.QuoteVolume,
+	}
+	return nil
+}
+
+// MiniTickerEventOrError is a union of MiniTickerEvent or error
+type MiniTickerEventOrError struct {
+	MiniTickerEvent
+	Err error
+}
+
+// MiniTickerEventsOrError is a union of a list of MiniTickerEvents or error
+type MiniTickerEventsOrError struct {
+	MiniTickers []MiniTickerEvent
+	Err         error
+}
+
+// BookTickerEvent define websocket book ticker event, holding the best bid and ask on the order book
+type BookTickerEvent struct {
+	UpdateID        int64
+	Symbol          string
+	BestBidPrice    *big.Float
+	BestBidQuantity *big.Float
+	BestAskPrice    *big.Float
+	BestAskQuantity *big.Float
+}
+
+// UnmarshalJSON provides custom unmarshalling for BookTickerEvents.
+func (b *BookTickerEvent) UnmarshalJSON(bs []byte) error {
+	var tmp struct {
+		UpdateID        int64      `json:"u"`
+		Symbol          string     `json:"s"`
+		BestBidPrice    *big.Float `json:"b"`
+		BestBidQuantity *big.Float `json:"B"`
+		BestAskPrice    *big.Float `json:"a"`
+		BestAskQuantity *big.Float `json:"A"`
+	}
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	*b = BookTickerEvent{
+		UpdateID:        tmp.UpdateID,
+		Symbol:          tmp.Symbol,
+		BestBidPrice:    tmp.BestBidPrice,
+		BestBidQuantity: tmp.BestBidQuantity,
+		BestAskPrice:    tmp.BestAskPrice,
+		BestAskQuantity: tmp.BestAskQuantity,
+	}
+	return nil
+}
+
+// BookTickerEventOrError is a union of BookTickerEvent or error
+type BookTickerEventOrError struct {
+	BookTickerEvent
+	Err error
+}
+
+// Ticker initiates a websocket connection to binance and returns a channel from which 24 hour rolling window
+// statistics for the symbol are streamed.  The channel is closed when the underlying context is cancelled, or upon
+// a connection error or the server closing the connection.
+func (c *Client) Ticker(ctx context.Context, symbol string) <-chan TickerEventOrError {
+	return c.tickerStream(ctx, fmt.Sprintf("/ws/%s@ticker", url.PathEscape(strings.ToLower(symbol))))
+}
+
+// AllTickers initiates a websocket connection to binance and returns a channel from which 24 hour rolling window
+// statistics for all symbols that changed are streamed.  The channel is closed when the underlying context is
+// cancelled, or upon a connection error or the server closing the connection.
+func (c *Client) AllTickers(ctx context.Context) <-chan TickerEventsOrError {
+	return c.tickersStream(ctx, "/ws/!ticker@arr")
+}
+
+// RollingWindowTicker initiates a websocket connection to binance and returns a channel from which rolling window
+// statistics of the given window size are streamed for the symbol.  The channel is closed when the underlying
+// context is cancelled, or upon a connection error or the server closing the connection.
+func (c *Client) RollingWindowTicker(ctx context.Context, symbol string, window TickerWindowSize) <-chan TickerEventOrError {
+	if err := window.Validate(); err != nil {
+		out := make(chan TickerEventOrError, 1)
+		out <- TickerEventOrError{Err: err}
+		close(out)
+		return out
+	}
+	return c.tickerStream(ctx, fmt.Sprintf("/ws/%s@ticker_%s", url.PathEscape(strings.ToLower(symbol)), window))
+}
+
+// AllRollingWindowTickers initiates a websocket connection to binance and returns a channel from which rolling window
+// statistics of the given window size are streamed for all symbols that changed.  The channel is closed when the
+// underlying context is cancelled, or upon a connection error or the server closing the connection.
+func (c *Client) AllRollingWindowTickers(ctx context.Context, window TickerWindowSize) <-chan TickerEventsOrError {
+	if err := window.Validate(); err != nil {
+		out := make(chan TickerEventsOrError, 1)
+		out <- TickerEventsOrError{Err: err}
+		close(out)
+		return out
+	}
+	return c.tickersStream(ctx, fmt.Sprintf("/ws/!ticker_%s@arr", window))
+}
+
+// MiniTicker initiates a websocket connection to binance and returns a channel from which 24 hour rolling window
+// mini ticker statistics for the symbol are streamed.  The channel is closed when the underlying context is
+// cancelled, or upon a connection error or the server closing the connection.
+func (c *Client) MiniTicker(ctx context.Context, symbol string) <-chan MiniTickerEventOrError {
+	out := make(chan MiniTickerEventOrError, 1)
+	handle := func(reader io.Reader, err error) error {
+		if err != nil {
+			out <- MiniTickerEventOrError{Err: err}
+			return nil
+		}
+		var ticker MiniTickerEvent
+		dec := json.NewDecoder(reader)
+		if err := dec.Decode(&ticker); err != nil {
+			out <- MiniTickerEventOrError{Err: fmt.Errorf("error decoding mini ticker event: %w", err)}
+			return err
+		}
+		out <- MiniTickerEventOrError{MiniTickerEvent: ticker}
+		return nil
+	}
+	path := fmt.Sprintf("/ws/%s@miniTicker", url.PathEscape(strings.ToLower(symbol)))
+
+	go c.openWebsocket(ctx, path, handle, func() {
+		close(out)
+	})
+	return out
+}
+
+// AllMiniTickers initiates a websocket connection to binance and returns a channel from which 24 hour rolling
+// window mini ticker statistics for all symbols that changed are streamed.  The channel is closed when the
+// underlying context is cancelled, or upon a connection error or the server closing the connection.
+func (c *Client) AllMiniTickers(ctx context.Context) <-chan MiniTickerEventsOrError {
+	out := make(chan MiniTickerEventsOrError, 1)
+	handle := func(reader io.Reader, err error) error {
+		if err != nil {
+			out <- MiniTickerEventsOrError{Err: err}
+			return nil
+		}
+		var tickers []MiniTickerEvent
+		dec := json.NewDecoder(reader)
+		if err := dec.Decode(&tickers); err != nil {
+			out <- MiniTickerEventsOrError{Err: fmt.Errorf("error decoding mini ticker events: %w", err)}
+			return err
+		}
+		out <- MiniTickerEventsOrError{MiniTickers: tickers}
+		return nil
+	}
+
+	go c.openWebsocket(ctx, "/ws/!miniTicker@arr", handle, func() {
+		close(out)
+	})
+	return out
+}
+
+// BookTicker initiates a websocket connection to binance and returns a channel from which updates to the best bid
+// and ask prices and quantities of the symbol are streamed.  The channel is closed when the underlying context is
+// cancelled, or upon a connection error or the server closing the connection.
+func (c *Client) BookTicker(ctx context.Context, symbol string) <-chan BookTickerEventOrError {
+	out := make(chan BookTickerEventOrError, 1)
+	handle := func(reader io.Reader, err error) error {
+		if err != nil {
+			out <- BookTickerEventOrError{Err: err}
+			return nil
+		}
+		var ticker BookTickerEvent
+		dec := json.NewDecoder(reader)
+		if err := dec.Decode(&ticker); err != nil {
+			out <- BookTickerEventOrError{Err: fmt.Errorf("error decoding book ticker event: %w", err)}
+			return err
+		}
+		out <- BookTickerEventOrError{BookTickerEvent: ticker}
+		return nil
+	}
+	path := fmt.Sprintf("/ws/%s@bookTicker", url.PathEscape(strings.ToLower(symbol)))
+
+	go c.openWebsocket(ctx, path, handle, func() {
+		close(out)
+	})
+	return out
+}
+
+func (c *Client) tickerStream(ctx context.Context, path string) <-chan TickerEventOrError {
+	out := make(chan TickerEventOrError, 1)
+	handle := func(reader io.Reader, err error) error {
+		if err != nil {
+			out <- TickerEventOrError{Err: err}
+			return nil
+		}
+		var ticker TickerEvent
+		dec := json.NewDecoder(reader)
+		if err := dec.Decode(&ticker); err != nil {
+			out <- TickerEventOrError{Err: fmt.Errorf("error decoding ticker event: %w", err)}
+			return err
+		}
+		out <- TickerEventOrError{TickerEvent: ticker}
+		return nil
+	}
+
+	go c.openWebsocket(ctx, path, handle, func() {
+		close(out)
+	})
+	return out
+}
+
+func (c *Client) tickersStream(ctx context.Context, path string) <-chan TickerEventsOrError {
+	out := make(chan TickerEventsOrError, 1)
+	handle := func(reader io.Reader, err error) error {
+		if err != nil {
+			out <- TickerEventsOrError{Err: err}
+			return nil
+		}
+		var tickers []TickerEvent
+		dec := json.NewDecoder(reader)
+		if err := dec.Decode(&tickers); err != nil {
+			out <- TickerEventsOrError{Err: fmt.Errorf("error decoding ticker events: %w", err)}
+			return err
+		}
+		out <- TickerEventsOrError{Tickers: tickers}
+		return nil
+	}
+
+	go c.openWebsocket(ctx, path, handle, func() {
+		close(out)
+	})
+	return out
+}
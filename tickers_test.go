@@ -0,0 +1,211 @@
+package gobinance_test
+
+import (
+	"context"
+	"errors"
+	"github.com/beyondallrepair/gobinance"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"time"
+)
+
+const (
+	tickerMessage     = `{"e":"24hrTicker","E":123456789,"s":"BNBBTC","p":"0.0015","P":"250.00","w":"0.0018","x":"0.0009","c":"0.0025","Q":"10","b":"0.0024","B":"11","a":"0.0026","A":"100","o":"0.0010","h":"0.0027","l":"0.0008","v":"10000","q":"18","O":0,"C":86400000,"F":1,"L":18150,"n":18151}`
+	miniTickerMessage = `{"e":"24hrMiniTicker","E":123456789,"s":"BNBBTC","c":"0.0025","o":"0.0010","h":"0.0027","l":"0.0008","v":"10000","q":"18"}`
+)
+
+func expectedTickerEvent(t *testing.T) gobinance.TickerEvent {
+	return gobinance.TickerEvent{
+		Event:              "24hrTicker",
+		Time:               time.Date(1970, 01, 02, 10, 17, 36, int(789*time.Millisecond), time.UTC),
+		Symbol:             "BNBBTC",
+		PriceChange:        mustParseBigFloat(t, "0.0015"),
+		PriceChangePercent: mustParseBigFloat(t, "250.00"),
+		WeightedAvgPrice:   mustParseBigFloat(t, "0.0018"),
+		FirstTradePrice:    mustParseBigFloat(t, "0.0009"),
+		LastPrice:          mustParseBigFloat(t, "0.0025"),
+		LastQuantity:       mustParseBigFloat(t, "10"),
+		BestBidPrice:       mustParseBigFloat(t, "0.0024"),
+		BestBidQuantity:    mustParseBigFloat(t, "11"),
+		BestAskPrice:       mustParseBigFloat(t, "0.0026"),
+		BestAskQuantity:    mustParseBigFloat(t, "100"),
+		OpenPrice:          mustParseBigFloat(t, "0.0010"),
+		HighPrice:          mustParseBigFloat(t, "0.0027"),
+		LowPrice:           mustParseBigFloat(t, "0.0008"),
+		BaseVolume:         mustParseBigFloat(t, "10000"),
+		QuoteVolume:        mustParseBigFloat(t, "18"),
+		OpenTime:           time.Date(1970, 01, 01, 0, 0, 0, 0, time.UTC),
+		CloseTime:          time.Date(1970, 01, 02, 0, 0, 0, 0, time.UTC),
+		FirstTradeID:       1,
+		LastTradeID:        18150,
+		NumberOfTrades:     18151,
+	}
+}
+
+func expectedMiniTickerEvent(t *testing.T) gobinance.MiniTickerEvent {
+	return gobinance.MiniTickerEvent{
+		Event:       "24hrMiniTicker",
+		Time:        time.Date(1970, 01, 02, 10, 17, 36, int(789*time.Millisecond), time.UTC),
+		Symbol:      "BNBBTC",
+		ClosePrice:  mustParseBigFloat(t, "0.0025"),
+		OpenPrice:   mustParseBigFloat(t, "0.0010"),
+		HighPrice:   mustParseBigFloat(t, "0.0027"),
+		LowPrice:    mustParseBigFloat(t, "0.0008"),
+		BaseVolume:  mustParseBigFloat(t, "10000"),
+		QuoteVolume: mustParseBigFloat(t, "18"),
+	}
+}
+
+func TestClient_Ticker(t *testing.T) {
+	t.Parallel()
+	uut := newTestStreamClient(t, testWebsocketBaseURL+"/ws/bnbbtc@ticker", tickerMessage)
+
+	var got []gobinance.TickerEventOrError
+	for e := range uut.Ticker(context.Background(), "BNBBTC") {
+		got = append(got, e)
+	}
+	expected := []gobinance.TickerEventOrError{
+		{TickerEvent: expectedTickerEvent(t)},
+		{Err: errStreamEnded},
+	}
+	if diff := cmp.Diff(expected, got, bigFloatComparer, cmp.Comparer(errors.Is)); diff != "" {
+		t.Errorf("unexpected events.  %s", diff)
+	}
+}
+
+func TestClient_AllTickers(t *testing.T) {
+	t.Parallel()
+	uut := newTestStreamClient(t, testWebsocketBaseURL+"/ws/!ticker@arr", "["+tickerMessage+","+tickerMessage+"]")
+
+	var got []gobinance.TickerEventsOrError
+	for e := range uut.AllTickers(context.Background()) {
+		got = append(got, e)
+	}
+	expected := []gobinance.TickerEventsOrError{
+		{Tickers: []gobinance.TickerEvent{expectedTickerEvent(t), expectedTickerEvent(t)}},
+		{Err: errStreamEnded},
+	}
+	if diff := cmp.Diff(expected, got, bigFloatComparer, cmp.Comparer(errors.Is)); diff != "" {
+		t.Errorf("unexpected events.  %s", diff)
+	}
+}
+
+func TestClient_RollingWindowTicker(t *testing.T) {
+	t.Parallel()
+	t.Run("invalid window", func(t *testing.T) {
+		t.Parallel()
+		uut := &gobinance.Client{}
+		var got []gobinance.TickerEventOrError
+		for e := range uut.RollingWindowTicker(context.Background(), "BNBBTC", "2h") {
+			got = append(got, e)
+		}
+		if len(got) != 1 || got[0].Err == nil {
+			t.Errorf("expected a single error but got %#v", got)
+		}
+	})
+	t.Run("events received", func(t *testing.T) {
+		t.Parallel()
+		uut := newTestStreamClient(t, testWebsocketBaseURL+"/ws/bnbbtc@ticker_1h",
+			`{"e":"1hTicker","E":123456789,"s":"BNBBTC","p":"0.0015","P":"250.00","o":"0.0010","h":"0.0027","l":"0.0008","c":"0.0025","w":"0.0018","v":"10000","q":"18","O":0,"C":86400000,"F":1,"L":18150,"n":18151}`)
+
+		var got []gobinance.TickerEventOrError
+		for e := range uut.RollingWindowTicker(context.Background(), "BNBBTC", gobinance.TickerWindowSize1h) {
+			got = append(got, e)
+		}
+		expectedEvent := expectedTickerEvent(t)
+		expectedEvent.Event = "1hTicker"
+		expectedEvent.FirstTradePrice = nil
+		expectedEvent.LastQuantity = nil
+		expectedEvent.BestBidPrice = nil
+		expectedEvent.BestBidQuantity = nil
+		expectedEvent.BestAskPrice = nil
+		expectedEvent.BestAskQuantity = nil
+		expected := []gobinance.TickerEventOrError{
+			{TickerEvent: expectedEvent},
+			{Err: errStreamEnded},
+		}
+		if diff := cmp.Diff(expected, got, bigFloatComparer, cmp.Comparer(errors.Is)); diff != "" {
+			t.Errorf("unexpected events.  %s", diff)
+		}
+	})
+}
+
+func TestClient_AllRollingWindowTickers(t *testing.T) {
+	t.Parallel()
+	uut := newTestStreamClient(t, testWebsocketBaseURL+"/ws/!ticker_4h@arr", "[]")
+
+	var got []gobinance.TickerEventsOrError
+	for e := range uut.AllRollingWindowTickers(context.Background(), gobinance.TickerWindowSize4h) {
+		got = append(got, e)
+	}
+	expected := []gobinance.TickerEventsOrError{
+		{Tickers: []gobinance.TickerEvent{}},
+		{Err: errStreamEnded},
+	}
+	if diff := cmp.Diff(expected, got, bigFloatComparer, cmp.Comparer(errors.Is)); diff != "" {
+		t.Errorf("unexpected events.  %s", diff)
+	}
+}
+
+func TestClient_MiniTicker(t *testing.T) {
+	t.Parallel()
+	uut := newTestStreamClient(t, testWebsocketBaseURL+"/ws/bnbbtc@miniTicker", miniTickerMessage, "not json")
+
+	var got []gobinance.MiniTickerEventOrError
+	for e := range uut.MiniTicker(context.Background(), "BNBBTC") {
+		got = append(got, e)
+	}
+	if l := len(got); l != 3 {
+		t.Fatalf("expected 3 events but got %v", l)
+	}
+	if got[1].Err == nil {
+		t.Errorf("expected a decoding error but got nil")
+	}
+	if diff := cmp.Diff(expectedMiniTickerEvent(t), got[0].MiniTickerEvent, bigFloatComparer); diff != "" {
+		t.Errorf("unexpected event.  %s", diff)
+	}
+}
+
+func TestClient_AllMiniTickers(t *testing.T) {
+	t.Parallel()
+	uut := newTestStreamClient(t, testWebsocketBaseURL+"/ws/!miniTicker@arr", "["+miniTickerMessage+"]")
+
+	var got []gobinance.MiniTickerEventsOrError
+	for e := range uut.AllMiniTickers(context.Background()) {
+		got = append(got, e)
+	}
+	expected := []gobinance.MiniTickerEventsOrError{
+		{MiniTickers: []gobinance.MiniTickerEvent{expectedMiniTickerEvent(t)}},
+		{Err: errStreamEnded},
+	}
+	if diff := cmp.Diff(expected, got, bigFloatComparer, cmp.Comparer(errors.Is)); diff != "" {
+		t.Errorf("unexpected events.  %s", diff)
+	}
+}
+
+func TestClient_BookTicker(t *testing.T) {
+	t.Parallel()
+	uut := newTestStreamClient(t, testWebsocketBaseURL+"/ws/bnbusdt@bookTicker",
+		`{"u":400900217,"s":"BNBUSDT","b":"25.35190000","B":"31.21000000","a":"25.36520000","A":"40.66000000"}`)
+
+	var got []gobinance.BookTickerEventOrError
+	for e := range uut.BookTicker(context.Background(), "BNBUSDT") {
+		got = append(got, e)
+	}
+	expected := []gobinance.BookTickerEventOrError{
+		{
+			BookTickerEvent: gobinance.BookTickerEvent{
+				UpdateID:        400900217,
+				Symbol:          "BNBUSDT",
+				BestBidPrice:    mustParseBigFloat(t, "25.35190000"),
+				BestBidQuantity: mustParseBigFloat(t, "31.21000000"),
+				BestAskPrice:    mustParseBigFloat(t, "25.36520000"),
+				BestAskQuantity: mustParseBigFloat(t, "40.66000000"),
+			},
+		},
+		{Err: errStreamEnded},
+	}
+	if diff := cmp.Diff(expected, got, bigFloatComparer, cmp.Comparer(errors.Is)); diff != "" {
+		t.Errorf("unexpected events.  %s", diff)
+	}
+}
@@ -16,6 +16,9 @@ import (
 // the field will not be in the output when the value of that field is the Zero value of its type.
 //
 // The `emptyvalue` tag may be used to specify the value to be used in the output when the field is empty.
+//
+// Pointers to values other than structs, such as *int64, are empty only when nil, so may be used to send zero values
+// of optional parameters.
 func toURLValues(i interface{}) (url.Values, error) {
 	out := url.Values{}
 
@@ -50,7 +53,11 @@ func toURLValues(i interface{}) (url.Values, error) {
 			}
 		}
 
-		stringValue := fmt.Sprint(iVal.Field(f).Interface())
+		field := iVal.Field(f)
+		if field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() != reflect.Struct {
+			field = field.Elem()
+		}
+		stringValue := fmt.Sprint(field.Interface())
 		if iVal.Field(f).IsZero() {
 			if omitEmpty {
 			continue
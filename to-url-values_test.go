@@ -112,6 +112,18 @@ func TestToURLValues(t *testing.T) {
 				"EmptyString":   []string{""},
 			},
 		},
+		{
+			name: "pointers to zero-values with omitempty",
+			input: struct {
+				NilInt  *int64 `param:"NilInt,omitempty"`
+				ZeroInt *int64 `param:"ZeroInt,omitempty"`
+			}{
+				ZeroInt: new(int64),
+			},
+			expectedOutput: url.Values{
+				"ZeroInt": []string{"0"},
+			},
+		},
 		{
 			name: "zero-values with emptyvalue",
 			input: struct {
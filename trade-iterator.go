@@ -0,0 +1,125 @@
+package gobinance
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrStopStream may be returned by the handler given to StreamTrades to stop the stream without error
+	ErrStopStream = errors.New("stream stopped")
+	// ErrIteratorClosed is returned by the Next method of an iterator which has been closed
+	ErrIteratorClosed = errors.New("iterator closed")
+	// errStreamClosed is returned when a stream's channel is closed without an error or the context being done
+	errStreamClosed = errors.New("stream closed")
+)
+
+// StreamTrades streams the live trades of the symbol as Trades does, calling handle with each one in turn.  It
+// blocks until the stream ends, which happens upon the first error, and closes the stream before returning:
+//
+//   - nil if handle returned ErrStopStream
+//   - the error returned by handle, if it returned any other
+//   - the context's error if it is done
+//   - otherwise the error which ended the stream, such as a connection error or a trade which could not be decoded
+func (c *Client) StreamTrades(ctx context.Context, symbol string, handle func(trade TradeEvent) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	events := c.Trades(ctx, symbol)
+	defer drainTrades(events)
+	defer cancel()
+
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return streamEndedErr(ctx)
+			}
+			if e.Err != nil {
+				return e.Err
+			}
+			if err := handle(e.TradeEvent); err != nil {
+				if errors.Is(err, ErrStopStream) {
+					return nil
+				}
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// TradeIterator reads the live trades of a symbol one at a time.  It must be closed once no longer needed, and is
+// not safe for concurrent use.
+type TradeIterator struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	events <-chan TradeEventOrError
+	err    error
+}
+
+// TradeIterator streams the live trades of the symbol as Trades does, returning an iterator from which they are
+// read.  The stream remains open until the iterator is closed, ctx is done, or an error ends it.
+func (c *Client) TradeIterator(ctx context.Context, symbol string) *TradeIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	return &TradeIterator{
+		ctx:    ctx,
+		cancel: cancel,
+		events: c.Trades(ctx, symbol),
+	}
+}
+
+// Next waits for the next trade.  The stream ends upon the first error, such as a connection error, a trade which
+// could not be decoded, or the context given to TradeIterator being done, and that error is then returned by every
+// later call.  If ctx is done before a trade is received, its error is returned but the stream remains open.
+func (it *TradeIterator) Next(ctx context.Context) (TradeEvent, error) {
+	if it.err != nil {
+		return TradeEvent{}, it.err
+	}
+	select {
+	case e, ok := <-it.events:
+		if !ok {
+			return TradeEvent{}, it.end(streamEndedErr(it.ctx))
+		}
+		if e.Err != nil {
+			return TradeEvent{}, it.end(e.Err)
+		}
+		return e.TradeEvent, nil
+	case <-ctx.Done():
+		return TradeEvent{}, ctx.Err()
+	}
+}
+
+// Err returns the error which ended the stream, or nil while it is open
+func (it *TradeIterator) Err() error {
+	return it.err
+}
+
+// Close closes the stream, after which Next returns ErrIteratorClosed unless the stream had already ended
+func (it *TradeIterator) Close() {
+	it.end(ErrIteratorClosed)
+}
+
+// end records err as the reason the stream ended, unless it had already, and closes the stream
+func (it *TradeIterator) end(err error) error {
+	if it.err == nil {
+		it.err = err
+		it.cancel()
+		drainTrades(it.events)
+	}
+	return it.err
+}
+
+// drainTrades discards the events remaining on a stream whose context has been cancelled, so that the goroutine
+// sending them is not left blocked, and waits for the channel to be closed
+func drainTrades(events <-chan TradeEventOrError) {
+	for range events {
+	}
+}
+
+// streamEndedErr returns the reason a stream's channel was closed without an error being sent on it
+func streamEndedErr(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errStreamClosed
+}
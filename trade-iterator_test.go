@@ -0,0 +1,160 @@
+package gobinance_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"net/url"
+	"testing"
+)
+
+const (
+	testTradesURL    = testWebsocketBaseURL + "/ws/btcusdt@trade"
+	testTradeMessage = `{"e":"trade","E":1604705434642,"s":"BTCUSDT","t":455634704,"p":"15617.99000000","q":"0.00720000","b":3530255770,"a":3530255647,"T":1604705434637,"m":false,"M":true}`
+)
+
+// newEndlessTradeClient returns a client whose trade stream sends testTradeMessage until it is closed
+func newEndlessTradeClient(t *testing.T) *gobinance.Client {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockDialer := mock_gobinance.NewMockDialContexter(ctrl)
+	mockNextReader := mock_gobinance.NewMockNextReaderCloser(ctrl)
+	mockDialer.EXPECT().DialContext(gomock.Not(gomock.Nil()), testTradesURL, nil).Return(mockNextReader, nil, nil)
+	mockNextReader.EXPECT().Close()
+	mockNextReader.EXPECT().NextReader().DoAndReturn(func() (int, *bytes.Buffer, error) {
+		return 0, bytes.NewBufferString(testTradeMessage), nil
+	}).AnyTimes()
+	baseURL, _ := url.Parse(testWebsocketBaseURL)
+	return &gobinance.Client{
+		WebsocketApiURL: baseURL,
+		DialContexter:   mockDialer,
+	}
+}
+
+func TestClient_StreamTrades(t *testing.T) {
+	t.Parallel()
+	handlerErr := errors.New("handler error")
+	testCases := []struct {
+		name     string
+		client   func(t *testing.T) *gobinance.Client
+		stopWith error
+		trades   int
+		errCheck func(t *testing.T, err error)
+	}{
+		{
+			name: "stream ends with an error",
+			client: func(t *testing.T) *gobinance.Client {
+				return newTestStreamClient(t, testTradesURL, testTradeMessage, testTradeMessage)
+			},
+			trades: 2,
+			errCheck: func(t *testing.T, err error) {
+				if !errors.Is(err, errStreamEnded) {
+					t.Errorf("expected errStreamEnded but got %v", err)
+				}
+			},
+		},
+		{
+			name: "invalid trade ends the stream",
+			client: func(t *testing.T) *gobinance.Client {
+				return newTestStreamClient(t, testTradesURL, testTradeMessage, `not json`)
+			},
+			trades: 1,
+			errCheck: func(t *testing.T, err error) {
+				if err == nil || errors.Is(err, errStreamEnded) {
+					t.Errorf("expected a decoding error but got %v", err)
+				}
+			},
+		},
+		{
+			name:     "handler stops the stream",
+			client:   newEndlessTradeClient,
+			stopWith: gobinance.ErrStopStream,
+			trades:   3,
+			errCheck: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name:     "handler errors",
+			client:   newEndlessTradeClient,
+			stopWith: handlerErr,
+			trades:   3,
+			errCheck: func(t *testing.T, err error) {
+				if err != handlerErr {
+					t.Errorf("expected the handler's error but got %v", err)
+				}
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var trades int
+			err := tc.client(t).StreamTrades(context.Background(), "BTCUSDT", func(trade gobinance.TradeEvent) error {
+				if trade.TradeID != 455634704 {
+					t.Errorf("unexpected trade %#v", trade)
+				}
+				trades++
+				if trades == 3 {
+					return tc.stopWith
+				}
+				return nil
+			})
+			tc.errCheck(t, err)
+			if trades != tc.trades {
+				t.Errorf("expected %v trades but got %v", tc.trades, trades)
+			}
+		})
+	}
+}
+
+func TestClient_TradeIterator(t *testing.T) {
+	t.Parallel()
+	t.Run("stream ends", func(t *testing.T) {
+		t.Parallel()
+		it := newTestStreamClient(t, testTradesURL, testTradeMessage).TradeIterator(context.Background(), "BTCUSDT")
+		defer it.Close()
+		trade, err := it.Next(context.Background())
+		if err != nil || trade.TradeID != 455634704 {
+			t.Fatalf("unexpected trade %#v, %v", trade, err)
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := it.Next(context.Background()); !errors.Is(err, errStreamEnded) {
+				t.Errorf("expected errStreamEnded but got %v", err)
+			}
+		}
+		if err := it.Err(); !errors.Is(err, errStreamEnded) {
+			t.Errorf("expected errStreamEnded but got %v", err)
+		}
+	})
+	t.Run("closed", func(t *testing.T) {
+		t.Parallel()
+		it := newEndlessTradeClient(t).TradeIterator(context.Background(), "BTCUSDT")
+		if _, err := it.Next(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		it.Close()
+		if _, err := it.Next(context.Background()); err != gobinance.ErrIteratorClosed {
+			t.Errorf("expected ErrIteratorClosed but got %v", err)
+		}
+	})
+	t.Run("context done", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		it := newEndlessTradeClient(t).TradeIterator(ctx, "BTCUSDT")
+		cancel()
+		var err error
+		for err == nil {
+			_, err = it.Next(context.Background())
+		}
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled but got %v", err)
+		}
+	})
+}
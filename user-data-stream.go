@@ -0,0 +1,387 @@
+package gobinance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// userDataStreamKeepAliveInterval is how often listen keys are kept alive.  Binance expires them after 60 minutes
+	// and recommends a keep alive every 30 minutes.
+	userDataStreamKeepAliveInterval = 30 * time.Minute
+	// userDataStreamCloseTimeout is the maximum time spent closing a listen key once a user data stream has ended
+	userDataStreamCloseTimeout = 10 * time.Second
+)
+
+// StartUserDataStream creates a listen key which can be used to stream events relating to the account associated
+// with the API Key provided to the client.  The listen key remains valid for 60 minutes unless kept alive.
+func (c *Client) StartUserDataStream(ctx context.Context) (string, error) {
+	req, err := c.buildUnsignedRequest(ctx, http.MethodPost, "/api/v3/userDataStream", nil, true)
+	if err != nil {
+		return "", fmt.Errorf("error building request: %w", err)
+	}
+	var out struct {
+		ListenKey string `json:"listenKey"`
+	}
+	if err := performRequest(c.Doer, req, &out); err != nil {
+		return "", err
+	}
+	return out.ListenKey, nil
+}
+
+// KeepAliveUserDataStream extends the validity of the listen key for a further 60 minutes
+func (c *Client) KeepAliveUserDataStream(ctx context.Context, listenKey string) error {
+	return c.listenKeyRequest(ctx, http.MethodPut, listenKey)
+}
+
+// CloseUserDataStream invalidates the listen key, closing any streams using it
+func (c *Client) CloseUserDataStream(ctx context.Context, listenKey string) error {
+	return c.listenKeyRequest(ctx, http.MethodDelete, listenKey)
+}
+
+func (c *Client) listenKeyRequest(ctx context.Context, method string, listenKey string) error {
+	params := url.Values{"listenKey": {listenKey}}
+	req, err := c.buildUnsignedRequest(ctx, method, "/api/v3/userDataStream", params, true)
+	if err != nil {
+		return fmt.Errorf("error building request: %w", err)
+	}
+	return performRequest(c.Doer, req, nil)
+}
+
+// AccountPositionEvent is sent on the user data stream whenever the balance of an asset changes
+type AccountPositionEvent struct {
+	Event          string
+	Time           time.Time
+	LastUpdateTime time.Time
+	// Balances holds the new balances of the assets that changed
+	Balances []Balance
+}
+
+// UnmarshalJSON provides custom unmarshalling for AccountPositionEvents.
+func (a *AccountPositionEvent) UnmarshalJSON(bs []byte) error {
+	var tmp struct {
+		Event          string          `json:"e"`
+		Time           millisTimestamp `json:"E"`
+		LastUpdateTime millisTimestamp `json:"u"`
+		Balances       []struct {
+			Asset  string     `json:"a"`
+			Free   *big.Float `json:"f"`
+			Locked *big.Float `json:"l"`
+		} `json:"B"`
+	}
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	balances := make([]Balance, len(tmp.Balances))
+	for i, b := range tmp.Balances {
+		balances[i] = Balance{
+			Asset:  b.Asset,
+			Free:   b.Free,
+			Locked: b.Locked,
+		}
+	}
+	*a = AccountPositionEvent{
+		Event:          tmp.Event,
+		Time:           time.Time(tmp.Time),
+		LastUpdateTime: time.Time(tmp.LastUpdateTime),
+		Balances:       balances,
+	}
+	return nil
+}
+
+// BalanceUpdateEvent is sent on the user data stream upon deposits, withdrawals and transfers of an asset
+type BalanceUpdateEvent struct {
+	Event        string
+	Time         time.Time
+	Asset        string
+	BalanceDelta *big.Float
+	ClearTime    time.Time
+}
+
+// UnmarshalJSON provides custom unmarshalling for BalanceUpdateEvents.
+func (b *BalanceUpdateEvent) UnmarshalJSON(bs []byte) error {
+	var tmp struct {
+		Event        string          `json:"e"`
+		Time         millisTimestamp `json:"E"`
+		Asset        string          `json:"a"`
+		BalanceDelta *big.Float      `json:"d"`
+		ClearTime    millisTimestamp `json:"T"`
+	}
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	*b = BalanceUpdateEvent{
+		Event:        tmp.Event,
+		Time:         time.Time(tmp.Time),
+		Asset:        tmp.Asset,
+		BalanceDelta: tmp.BalanceDelta,
+		ClearTime:    time.Time(tmp.ClearTime),
+	}
+	return nil
+}
+
+// ExecutionReportEvent is sent on the user data stream whenever an order is created, updated or filled
+type ExecutionReportEvent struct {
+	Event                 string
+	Time                  time.Time
+	Symbol                string
+	ClientOrderID         string
+	Side                  OrderSide
+	Type                  OrderType
+	TimeInForce           TimeInForce
+	Quantity              *big.Float
+	Price                 *big.Float
+	StopPrice             *big.Float
+	IcebergQty            *big.Float
+	OrderListID           int64
+	OriginalClientOrderID string
+	ExecutionType         ExecutionType
+	Status                OrderStatus
+	RejectReason          string
+	OrderID               int64
+	LastExecutedQty       *big.Float
+	CumulativeFilledQty   *big.Float
+	LastExecutedPrice     *big.Float
+	Commission            *big.Float
+	CommissionAsset       string
+	TransactionTime       time.Time
+	TradeID               int64
+	IsWorking             bool
+	IsMaker               bool
+	CreationTime          time.Time
+	CumulativeQuoteQty    *big.Float
+	LastQuoteQty          *big.Float
+	QuoteOrderQty         *big.Float
+}
+
+// UnmarshalJSON provides custom unmarshalling for ExecutionReportEvents.
+func (e *ExecutionReportEvent) UnmarshalJSON(bs []byte) error {
+	var tmp struct {
+		Event                 string          `json:"e"`
+		Time                  millisTimestamp `json:"E"`
+		Symbol                string          `json:"s"`
+		ClientOrderID         string          `json:"c"`
+		Side                  OrderSide       `json:"S"`
+		Type                  OrderType       `json:"o"`
+		TimeInForce           TimeInForce     `json:"f"`
+		Quantity              *big.Float      `json:"q"`
+		Price                 *big.Float      `json:"p"`
+		StopPrice             *big.Float      `json:"P"`
+		IcebergQty            *big.Float      `json:"F"`
+		OrderListID           int64           `json:"g"`
+		OriginalClientOrderID string          `json:"C"`
+		ExecutionType         ExecutionType   `json:"x"`
+		Status                OrderStatus     `json:"X"`
+		RejectReason          string          `json:"r"`
+		OrderID               int64           `json:"i"`
+		LastExecutedQty       *big.Float      `json:"l"`
+		CumulativeFilledQty   *big.Float      `json:"z"`
+		LastExecutedPrice     *big.Float      `json:"L"`
+		Commission            *big.Float      `json:"n"`
+		CommissionAsset       string          `json:"N"`
+		TransactionTime       millisTimestamp `json:"T"`
+		TradeID               int64           `json:"t"`
+		Ignore                int64           `json:"I"` // add this field to avoid case insensitive unmarshaling
+		IsWorking             bool            `json:"w"`
+		WorkingTime           millisTimestamp `json:"W"` // add this field to avoid case insensitive unmarshaling
+		IsMaker               bool            `json:"m"`
+		Placeholder           bool            `json:"M"` // add this field to avoid case insensitive unmarshaling
+		CreationTime          millisTimestamp `json:"O"`
+		CumulativeQuoteQty    *big.Float      `json:"Z"`
+		LastQuoteQty          *big.Float      `json:"Y"`
+		QuoteOrderQty         *big.Float      `json:"Q"`
+	}
+	if err := json.Unmarshal(bs, &tmp); err != nil {
+		return err
+	}
+	*e = ExecutionReportEvent{
+		Event:                 tmp.Event,
+		Time:                  time.Time(tmp.Time),
+		Symbol:                tmp.Symbol,
+		ClientOrderID:         tmp.ClientOrderID,
+		Side:                  tmp.Side,
+		Type:                  tmp.Type,
+		TimeInForce:           tmp.TimeInForce,
+		Quantity:              tmp.Quantity,
+		Price:                 tmp.Price,
+		StopPrice:             tmp.StopPrice,
+		IcebergQty:            tmp.IcebergQty,
+		OrderListID:           tmp.OrderListID,
+		OriginalClientOrderID: tmp.OriginalClientOrderID,
+		ExecutionType:         tmp.ExecutionType,
+		Status:                tmp.Status,
+		RejectReason:          tmp.RejectReason,
+		OrderID:               tmp.OrderID,
+		LastExecutedQty:       tmp.LastExecutedQty,
+		CumulativeFilledQty:   tmp.CumulativeFilledQty,
+		LastExecutedPrice:     tmp.LastExecutedPrice,
+		Commission:            tmp.Commission,
+		CommissionAsset:       tmp.CommissionAsset,
+		TransactionTime:       time.Time(tmp.TransactionTime),
+		TradeID:               tmp.TradeID,
+		IsWorking:             tmp.IsWorking,
+		IsMaker:               tmp.IsMaker,
+		CreationTime:          time.Time(tmp.CreationTime),
+		CumulativeQuoteQty:    tmp.CumulativeQuoteQty,
+		LastQuoteQty:          tmp.LastQuoteQty,
+		QuoteOrderQty:         tmp.QuoteOrderQty,
+	}
+	return nil
+}
+
+// SpotOrder returns the state of the order after the execution described by the report.
+//
+// When an order is canceled, ClientOrderID holds the ID of the cancel request, so the order's own client order
+// ID is taken from OriginalClientOrderID.
+func (e ExecutionReportEvent) SpotOrder() SpotOrder {
+	clientOrderID := e.ClientOrderID
+	if e.OriginalClientOrderID != "" {
+		clientOrderID = e.OriginalClientOrderID
+	}
+	return SpotOrder{
+		Symbol:                e.Symbol,
+		OrderID:               e.OrderID,
+		OrderListID:           e.OrderListID,
+		ClientOrderID:         clientOrderID,
+		Price:                 e.Price,
+		OriginalQty:           e.Quantity,
+		ExecutedQty:           e.CumulativeFilledQty,
+		CumulativeQuoteQty:    e.CumulativeQuoteQty,
+		Status:                e.Status,
+		TimeInForce:           e.TimeInForce,
+		Type:                  e.Type,
+		Side:                  e.Side,
+		StopPrice:             e.StopPrice,
+		IcebergQty:            e.IcebergQty,
+		Time:                  e.CreationTime,
+		UpdateTime:            e.TransactionTime,
+		IsWorking:             e.IsWorking,
+		OriginalQuoteOrderQty: e.QuoteOrderQty,
+	}
+}
+
+// UserDataEvent is an event received on the user data stream.  Exactly one of AccountPosition, BalanceUpdate and
+// ExecutionReport is set, depending on the value of Event.  All are nil for event types which are not supported.
+type UserDataEvent struct {
+	Event           string
+	Time            time.Time
+	AccountPosition *AccountPositionEvent
+	BalanceUpdate   *BalanceUpdateEvent
+	ExecutionReport *ExecutionReportEvent
+}
+
+// UnmarshalJSON decodes the event into the field relevant to its event type.
+func (u *UserDataEvent) UnmarshalJSON(bs []byte) error {
+	var header struct {
+		Event string          `json:"e"`
+		Time  millisTimestamp `json:"E"`
+	}
+	if err := json.Unmarshal(bs, &header); err != nil {
+		return err
+	}
+	out := UserDataEvent{
+		Event: header.Event,
+		Time:  time.Time(header.Time),
+	}
+	var err error
+	switch header.Event {
+	case "outboundAccountPosition":
+		out.AccountPosition = &AccountPositionEvent{}
+		err = json.Unmarshal(bs, out.AccountPosition)
+	case "balanceUpdate":
+		out.BalanceUpdate = &BalanceUpdateEvent{}
+		err = json.Unmarshal(bs, out.BalanceUpdate)
+	case "executionReport":
+		out.ExecutionReport = &ExecutionReportEvent{}
+		err = json.Unmarshal(bs, out.ExecutionReport)
+	}
+	if err != nil {
+		return err
+	}
+	*u = out
+	return nil
+}
+
+// UserDataEventOrError is a union of UserDataEvent or error
+type UserDataEventOrError struct {
+	UserDataEvent
+	Err error
+}
+
+// UserData creates a listen key and initiates a websocket connection to binance, returning a channel from which
+// events relating to the account associated with the API Key provided to the client are streamed.  The listen key
+// is kept alive while the stream is open, and closed afterwards.
+//
+// The channel is closed when the underlying context is cancelled, or upon a connection error or the server closing
+// the connection.  Errors keeping the listen key alive are sent on the channel without closing it.
+func (c *Client) UserData(ctx context.Context) <-chan UserDataEventOrError {
+	out := make(chan UserDataEventOrError, 1)
+	go func() {
+		defer close(out)
+		listenKey, err := c.StartUserDataStream(ctx)
+		if err != nil {
+			out <- UserDataEventOrError{Err: fmt.Errorf("error starting user data stream: %w", err)}
+			return
+		}
+		defer func() {
+			closeCtx, cancel := context.WithTimeout(context.Background(), userDataStreamCloseTimeout)
+			defer cancel()
+			_ = c.CloseUserDataStream(closeCtx, listenKey)
+		}()
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.keepAliveUserDataStream(ctx, listenKey, out)
+		}()
+
+		handle := func(reader io.Reader, err error) error {
+			if err != nil {
+				out <- UserDataEventOrError{Err: err}
+				return nil
+			}
+			var event UserDataEvent
+			dec := json.NewDecoder(reader)
+			if err := dec.Decode(&event); err != nil {
+				out <- UserDataEventOrError{Err: fmt.Errorf("error decoding user data event: %w", err)}
+				return err
+			}
+			out <- UserDataEventOrError{UserDataEvent: event}
+			return nil
+		}
+		// dropping or conflating the user data stream would lose changes to orders and balances, so it always blocks
+		c.openNamedWebsocket(ctx, userDataStreamName, "/ws/"+url.PathEscape(listenKey), StreamBuffer{}, handle, cancel)
+		wg.Wait()
+	}()
+	return out
+}
+
+func (c *Client) keepAliveUserDataStream(ctx context.Context, listenKey string, out chan<- UserDataEventOrError) {
+	ticker := time.NewTicker(userDataStreamKeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.KeepAliveUserDataStream(ctx, listenKey); err != nil && ctx.Err() == nil {
+				select {
+				case out <- UserDataEventOrError{Err: fmt.Errorf("error keeping user data stream alive: %w", err)}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
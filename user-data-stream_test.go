@@ -0,0 +1,296 @@
+package gobinance_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/beyondallrepair/gobinance"
+	mock_gobinance "github.com/beyondallrepair/gobinance/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_StartUserDataStream(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mocks *clientMocks)
+		errorCheck     errorCheck
+		expectedResult string
+	}{
+		{
+			name: "request values",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+					if req.Method != http.MethodPost {
+						t.Errorf("unexpected http method: expected %v but got %v", http.MethodPost, req.Method)
+					}
+					if req.URL.Path != "/api/v3/userDataStream" {
+						t.Errorf("unexpected path: expected %v but got %v", "/api/v3/userDataStream", req.URL.Path)
+					}
+					if hdr := req.Header.Get("X-MBX-APIKEY"); hdr != testBinanceApiKey {
+						t.Errorf("unexpected api key header: expected %v but got %v", testBinanceApiKey, hdr)
+					}
+				}).Return(nil, fmt.Errorf("stop early"))
+			},
+			errorCheck: errNotNil,
+		},
+		{
+			name: "http error",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: 400,
+					Body:       ioutil.NopCloser(strings.NewReader(`{ "msg":"test message", "code":-1234 }`)),
+				}, nil)
+			},
+			errorCheck: isHttpError(400, -1234),
+		},
+		{
+			name: "success",
+			setup: func(t *testing.T, mocks *clientMocks) {
+				mocks.MockDoer.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(strings.NewReader(`{"listenKey":"pqia91ma19a5s61cv6a81va65sdf19v8a65a1a5s61cv6a81va65sdf19v8a65a1"}`)),
+				}, nil)
+			},
+			errorCheck:     errNil,
+			expectedResult: "pqia91ma19a5s61cv6a81va65sdf19v8a65a1a5s61cv6a81va65sdf19v8a65a1",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mocks := &clientMocks{
+				MockDoer: mock_gobinance.NewMockDoer(ctrl),
+			}
+
+			u, _ := url.Parse(testBaseURL)
+			uut := &gobinance.Client{
+				HTTPApiURL: u,
+				APIKey:     testBinanceApiKey,
+				UserAgent:  testUserAgent,
+				Doer:       mocks.MockDoer,
+			}
+
+			tc.setup(t, mocks)
+			got, err := uut.StartUserDataStream(context.Background())
+			if cont := tc.errorCheck(t, err); !cont {
+				return
+			}
+			if got != tc.expectedResult {
+				t.Errorf("unexpected listen key: expected %v but got %v", tc.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestClient_KeepAliveAndCloseUserDataStream(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name           string
+		call           func(c *gobinance.Client) error
+		expectedMethod string
+	}{
+		{
+			name: "keep alive",
+			call: func(c *gobinance.Client) error {
+				return c.KeepAliveUserDataStream(context.Background(), "test-listen-key")
+			},
+			expectedMethod: http.MethodPut,
+		},
+		{
+			name: "close",
+			call: func(c *gobinance.Client) error {
+				return c.CloseUserDataStream(context.Background(), "test-listen-key")
+			},
+			expectedMethod: http.MethodDelete,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDoer := mock_gobinance.NewMockDoer(ctrl)
+			mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				if req.Method != tc.expectedMethod {
+					t.Errorf("unexpected http method: expected %v but got %v", tc.expectedMethod, req.Method)
+				}
+				if req.URL.Path != "/api/v3/userDataStream" {
+					t.Errorf("unexpected path: expected %v but got %v", "/api/v3/userDataStream", req.URL.Path)
+				}
+				if hdr := req.Header.Get("X-MBX-APIKEY"); hdr != testBinanceApiKey {
+					t.Errorf("unexpected api key header: expected %v but got %v", testBinanceApiKey, hdr)
+				}
+				expected := url.Values{"listenKey": {"test-listen-key"}}
+				if diff := cmp.Diff(expected, req.URL.Query()); diff != "" {
+					t.Errorf("unexpected parameters passed to request:\n%v", diff)
+				}
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+				}, nil
+			})
+
+			u, _ := url.Parse(testBaseURL)
+			uut := &gobinance.Client{
+				HTTPApiURL: u,
+				APIKey:     testBinanceApiKey,
+				UserAgent:  testUserAgent,
+				Doer:       mockDoer,
+			}
+			if err := tc.call(uut); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_UserData(t *testing.T) {
+	t.Parallel()
+	t.Run("events received and listen key closed", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockDoer := mock_gobinance.NewMockDoer(ctrl)
+		start := mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodPost {
+				t.Errorf("unexpected http method: expected %v but got %v", http.MethodPost, req.Method)
+			}
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"listenKey":"test-listen-key"}`)),
+			}, nil
+		})
+		mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodDelete {
+				t.Errorf("unexpected http method: expected %v but got %v", http.MethodDelete, req.Method)
+			}
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+			}, nil
+		}).After(start)
+
+		httpURL, _ := url.Parse(testBaseURL)
+		wsURL, _ := url.Parse(testWebsocketBaseURL)
+		uut := &gobinance.Client{
+			HTTPApiURL:      httpURL,
+			WebsocketApiURL: wsURL,
+			APIKey:          testBinanceApiKey,
+			Doer:            mockDoer,
+			DialContexter: mockWebsocketMessages(ctrl, testWebsocketBaseURL+"/ws/test-listen-key",
+				`{"e":"outboundAccountPosition","E":1564034571105,"u":1564034571073,"B":[{"a":"ETH","f":"10000.000000","l":"0.000000"}]}`,
+				`{"e":"balanceUpdate","E":1573200697110,"a":"BTC","d":"100.00000000","T":1573200697068}`,
+				`{"e":"executionReport","E":1499405658658,"s":"ETHBTC","c":"mUvoqJxFIILMdfAW5iGSOW","S":"BUY","o":"LIMIT","f":"GTC","q":"1.00000000","p":"0.10264410","P":"0.00000000","F":"0.00000000","g":-1,"C":"","x":"NEW","X":"NEW","r":"NONE","i":4293153,"l":"0.00000000","z":"0.00000000","L":"0.00000000","n":"0","N":null,"T":1499405658657,"t":-1,"I":8641984,"w":true,"m":false,"M":false,"O":1499405658657,"Z":"0.00000000","Y":"0.00000000","Q":"0.00000000"}`,
+				`{"e":"listStatus","E":1564035303637}`,
+			),
+		}
+
+		var got []gobinance.UserDataEventOrError
+		for e := range uut.UserData(context.Background()) {
+			got = append(got, e)
+		}
+		if l := len(got); l != 5 {
+			t.Fatalf("expected 5 events but got %v", l)
+		}
+		if got[4].Err == nil {
+			t.Errorf("expected the stream to end with an error")
+		}
+
+		expectedPosition := &gobinance.AccountPositionEvent{
+			Event:          "outboundAccountPosition",
+			Time:           time.Unix(0, int64(1564034571105*time.Millisecond)),
+			LastUpdateTime: time.Unix(0, int64(1564034571073*time.Millisecond)),
+			Balances: []gobinance.Balance{
+				{Asset: "ETH", Free: mustParseBigFloat(t, "10000"), Locked: mustParseBigFloat(t, "0")},
+			},
+		}
+		if diff := cmp.Diff(expectedPosition, got[0].AccountPosition, bigFloatComparer); diff != "" {
+			t.Errorf("unexpected account position event.  %s", diff)
+		}
+
+		expectedBalanceUpdate := &gobinance.BalanceUpdateEvent{
+			Event:        "balanceUpdate",
+			Time:         time.Unix(0, int64(1573200697110*time.Millisecond)),
+			Asset:        "BTC",
+			BalanceDelta: mustParseBigFloat(t, "100"),
+			ClearTime:    time.Unix(0, int64(1573200697068*time.Millisecond)),
+		}
+		if diff := cmp.Diff(expectedBalanceUpdate, got[1].BalanceUpdate, bigFloatComparer); diff != "" {
+			t.Errorf("unexpected balance update event.  %s", diff)
+		}
+
+		report := got[2].ExecutionReport
+		if report == nil {
+			t.Fatalf("expected an execution report but got %#v", got[2])
+		}
+		expectedOrder := gobinance.SpotOrder{
+			Symbol:                "ETHBTC",
+			OrderID:               4293153,
+			OrderListID:           -1,
+			ClientOrderID:         "mUvoqJxFIILMdfAW5iGSOW",
+			Price:                 mustParseBigFloat(t, "0.10264410"),
+			OriginalQty:           mustParseBigFloat(t, "1"),
+			ExecutedQty:           mustParseBigFloat(t, "0"),
+			CumulativeQuoteQty:    mustParseBigFloat(t, "0"),
+			Status:                gobinance.OrderStatusNew,
+			TimeInForce:           gobinance.TimeInForceGoodTilCanceled,
+			Type:                  gobinance.OrderTypeLimit,
+			Side:                  gobinance.OrderSideBuy,
+			StopPrice:             mustParseBigFloat(t, "0"),
+			IcebergQty:            mustParseBigFloat(t, "0"),
+			Time:                  time.Unix(0, int64(1499405658657*time.Millisecond)),
+			UpdateTime:            time.Unix(0, int64(1499405658657*time.Millisecond)),
+			IsWorking:             true,
+			OriginalQuoteOrderQty: mustParseBigFloat(t, "0"),
+		}
+		if diff := cmp.Diff(expectedOrder, report.SpotOrder(), bigFloatComparer); diff != "" {
+			t.Errorf("unexpected order from execution report.  %s", diff)
+		}
+		if report.ExecutionType != gobinance.ExecutionTypeNew {
+			t.Errorf("unexpected execution type %v", report.ExecutionType)
+		}
+
+		if got[3].Event != "listStatus" || got[3].AccountPosition != nil || got[3].BalanceUpdate != nil || got[3].ExecutionReport != nil {
+			t.Errorf("expected unsupported event to only have its header decoded but got %#v", got[3])
+		}
+	})
+
+	t.Run("error starting stream", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockDoer := mock_gobinance.NewMockDoer(ctrl)
+		mockDoer.EXPECT().Do(gomock.Any()).Return(nil, fmt.Errorf("connection refused"))
+
+		u, _ := url.Parse(testBaseURL)
+		uut := &gobinance.Client{
+			HTTPApiURL: u,
+			APIKey:     testBinanceApiKey,
+			Doer:       mockDoer,
+		}
+		var got []gobinance.UserDataEventOrError
+		for e := range uut.UserData(context.Background()) {
+			got = append(got, e)
+		}
+		if len(got) != 1 || got[0].Err == nil {
+			t.Errorf("expected a single error but got %#v", got)
+		}
+	})
+}
@@ -13,11 +13,6 @@ import (
 	"time"
 )
 
-type readerError struct {
-	io.Reader
-	error
-}
-
 // TradeEvent define websocket trade event
 type TradeEvent struct {
 	Event         string
@@ -76,18 +71,19 @@ type TradeEventOrError struct {
 // closing the connection.
 func (c *Client) Trades(ctx context.Context, symbol string) <-chan TradeEventOrError {
 	out := make(chan TradeEventOrError, 1)
-	handle := func(reader io.Reader, err error) {
+	handle := func(reader io.Reader, err error) error {
 		if err != nil {
 			out <- TradeEventOrError{Err: err}
-			return
+			return nil
 		}
 		var trade TradeEvent
 		dec := json.NewDecoder(reader)
 		if err := dec.Decode(&trade); err != nil {
 			out <- TradeEventOrError{Err: fmt.Errorf("error decoding trade event: %w", err)}
-			return
+			return err
 		}
 		out <- TradeEventOrError{TradeEvent: trade}
+		return nil
 	}
 	path := fmt.Sprintf("/ws/%s@trade", url.PathEscape(strings.ToLower(symbol)))
 
@@ -99,58 +95,107 @@ func (c *Client) Trades(ctx context.Context, symbol string) <-chan TradeEventOrE
 
 // openWebsocket does some generic handling of websocket streams.  It initiates a connection to the endpoint
 // using the WebsocketApiURL from WebsocketClient and the path provided as an input parameter.  For each event streamed
-// from the websocket, the `handler` is called.
+// from the websocket, the `handler` is called, returning an error if the event could not be decoded.  The stream is
+// observed under the name given in the path, and buffered as set on the context or client.
 //
 // This function blocks until the websocket stream is closed either from the server, or due to the underlying
 // context being cancelled or a connection error.
-func (c *Client) openWebsocket(ctx context.Context, path string, handle func(reader io.Reader, err error), after func()) {
+func (c *Client) openWebsocket(ctx context.Context, path string, handle func(reader io.Reader, err error) error, after func()) {
+	c.openNamedWebsocket(ctx, strings.TrimPrefix(path, "/ws/"), path, c.streamBuffer(ctx), handle, after)
+}
+
+// openNamedWebsocket behaves as openWebsocket, but observes the stream under `name` and buffers it using `buffer`
+func (c *Client) openNamedWebsocket(ctx context.Context, name string, path string, buffer StreamBuffer, handle func(reader io.Reader, err error) error, after func()) {
 	defer after()
+	if err := buffer.Validate(); err != nil {
+		handle(nil, err)
+		return
+	}
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	u := c.WebsocketApiURL.ResolveReference(&url.URL{
-		Path: path,
-	})
-	con, _, err := c.DialContexter.DialContext(ctx, u.String(), nil)
+	con, err := c.dialWebsocket(ctx, name, path)
 	if err != nil {
-		handle(nil, fmt.Errorf("unable to establish websocket connection: %w", err))
+		handle(nil, err)
 		return
 	}
 	defer con.Close()
 
-	wsMessages := make(chan readerError)
+	readWebsocket(ctx, con, name, c.streamObserver(), buffer, handle)
+}
+
+// dialWebsocket initiates a websocket connection to the endpoint at `path` relative to the WebsocketApiURL.
+// The path is expected to already be escaped, which preserves characters such as `!` used in stream names.
+// The attempt is reported to the client's StreamObserver under `name`.
+func (c *Client) dialWebsocket(ctx context.Context, name string, path string) (NextReaderCloser, error) {
+	ref, err := url.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid websocket path %q: %w", path, err)
+	}
+	u := c.WebsocketApiURL.ResolveReference(ref)
+	observer := c.streamObserver()
+	start := time.Now()
+	con, _, err := c.DialContexter.DialContext(ctx, u.String(), nil)
+	observer.Dialled(name, time.Since(start), err)
+	if err != nil {
+		return nil, fmt.Errorf("unable to establish websocket connection: %w", err)
+	}
+	observer.Connected(name)
+	return con, nil
+}
+
+// readWebsocket calls `handle` for each message read from `con` until either the context is cancelled or
+// an error is returned from the connection.  Errors are passed to `handle` before returning.  Messages are buffered
+// while `handle` is busy according to `buffer`.  The messages, those dropped, the errors returned by `handle` and
+// the closing of the connection are reported to `observer` under `name`.
+func readWebsocket(ctx context.Context, con NextReaderCloser, name string, observer StreamObserver, buffer StreamBuffer, handle func(reader io.Reader, err error) error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	queue := newStreamQueue(buffer)
 	go func() {
-		defer close(wsMessages)
 		for {
+			var m queuedMessage
 			_, msg, err := con.NextReader()
 			if err == nil {
 				// note that we need to make a copy of the buffer here to avoid
 				// races with the consumer vs this loop's next iteration
 				var buf []byte
 				buf, err = ioutil.ReadAll(msg)
-				msg = bytes.NewBuffer(buf)
+				if err == nil {
+					observer.MessageReceived(name, len(buf))
+					m = queue.newMessage(buf)
+				}
+			}
+			if err != nil {
+				m = queuedMessage{err: err, keep: true}
 			}
-			select {
-			case <-ctx.Done():
+			dropped, ok := queue.push(ctx, m)
+			if dropped > 0 {
+				observer.Dropped(name, dropped)
+			}
+			if !ok || err != nil {
+				// errors are permanent, so break the loop
 				return
-			case wsMessages <- readerError{msg, err}:
-				if err != nil {
-					// errors are permanent, so break the loop
-					return
-				}
 			}
 		}
 	}()
 
 	for {
-		select {
-		case msg, ok := <-wsMessages:
-			if !ok {
-				return
-			}
-			handle(msg.Reader, msg.error)
-		case <-ctx.Done():
+		m, ok := queue.pop(ctx)
+		if !ok {
+			observer.Disconnected(name, ctx.Err())
 			return
 		}
+		if m.err != nil {
+			observer.Disconnected(name, m.err)
+			handle(nil, m.err)
+			return
+		}
+		start := time.Now()
+		if err := handle(bytes.NewReader(m.data), nil); err != nil {
+			observer.DecodeError(name, err)
+		}
+		observer.Delivered(name, time.Since(start))
 	}
 }